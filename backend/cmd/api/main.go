@@ -1,15 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/handlers"
+	"github.com/seeds-metrics/analytics-backend/internal/models"
 	"github.com/seeds-metrics/analytics-backend/internal/repository"
 	"github.com/seeds-metrics/analytics-backend/internal/services"
 	"github.com/seeds-metrics/analytics-backend/pkg/database"
@@ -38,6 +47,10 @@ import (
 // @in header
 // @name Authorization
 
+// version is the build version reported in the X-API-Version header.
+// It is overridden at build time with -ldflags "-X main.version=<version>".
+var version = "1.0.0"
+
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -66,25 +79,50 @@ func main() {
 
 	log.Println("✅ Django database connection established")
 
+	// Initialize reporting database (optional, read-only)
+	var reportingDB *database.DB
+	if cfg.ReportingDB.IsConfigured() {
+		reportingDB, err = database.NewPostgresDB(&cfg.ReportingDB)
+		if err != nil {
+			log.Fatalf("Failed to connect to reporting database: %v", err)
+		}
+		defer reportingDB.Close()
+
+		log.Println("✅ Reporting database connection established")
+	} else {
+		log.Println("ℹ️  No reporting database configured, reports will use the SeedsMetrics database")
+	}
+
 	// Initialize repositories
 	loanRepo := repository.NewLoanRepository(db)
+	loanRepo.SetOfficerScope(cfg.Dashboard)
 	repaymentRepo := repository.NewRepaymentRepository(db)
 	officerRepo := repository.NewOfficerRepository(db)
 	customerRepo := repository.NewCustomerRepository(db)
-	dashboardRepo := repository.NewDashboardRepository(db.DB)
+	syncRepo := repository.NewSyncRepository(db)
+	if orphaned, err := syncRepo.FailOrphanedJobs(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to clean up interrupted sync jobs: %v", err)
+	} else if orphaned > 0 {
+		log.Printf("⚠️  Marked %d sync jobs interrupted by the last shutdown as failed", orphaned)
+	}
+	dashboardRepo := repository.NewDashboardRepository(db.DB, cfg.Dashboard)
+	if reportingDB != nil {
+		dashboardRepo.SetReportingDB(reportingDB.DB)
+	}
 
 	// Initialize Django repository (read-only access to source data)
 	djangoRepo := repository.NewDjangoRepository(djangoDB.DB)
 
 	// Initialize services
-	metricsService := services.NewMetricsService()
-	syncService := services.NewSyncService(djangoDB.DB, db)
+	metricsService := services.NewMetricsService(cfg.Dashboard)
+	syncService := services.NewSyncService(djangoDB.DB, db, cfg.ETL)
 
 	// Initialize handlers
-	etlHandler := handlers.NewETLHandler(loanRepo, repaymentRepo, officerRepo)
-	customerHandler := handlers.NewCustomerHandler(customerRepo)
+	etlHandler := handlers.NewETLHandler(loanRepo, repaymentRepo, officerRepo, syncRepo)
+	etlHandler.SetOnSyncComplete(dashboardRepo.InvalidateFilterOptions)
+	customerHandler := handlers.NewCustomerHandler(customerRepo, repaymentRepo, cfg.Dashboard)
 	healthHandler := handlers.NewHealthHandler(db, djangoRepo)
-	dashboardHandler := handlers.NewDashboardHandler(dashboardRepo, repaymentRepo, metricsService, syncService)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardRepo, repaymentRepo, metricsService, syncService, syncRepo, cfg.Dashboard)
 
 	// Setup router
 	router := setupRouter(cfg, etlHandler, customerHandler, healthHandler, dashboardHandler)
@@ -111,9 +149,15 @@ func main() {
 func setupRouter(cfg *config.Config, etlHandler *handlers.ETLHandler, customerHandler *handlers.CustomerHandler, healthHandler *handlers.HealthHandler, dashboardHandler *handlers.DashboardHandler) *gin.Engine {
 	router := gin.Default()
 
+	// Request ID for correlating client reports with server logs
+	router.Use(handlers.RequestIDMiddleware())
+
 	// CORS middleware
 	router.Use(corsMiddleware(cfg))
 
+	// API version header
+	router.Use(apiVersionMiddleware(version))
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -122,24 +166,37 @@ func setupRouter(cfg *config.Config, etlHandler *handlers.ETLHandler, customerHa
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	if cfg.Server.CompressionEnabled {
+		v1.Use(handlers.GzipCompression(cfg.Server.CompressionMinBytes))
+	}
 	{
 		// ETL endpoints
 		etl := v1.Group("/etl")
+		etl.Use(etlPayloadGuard(cfg.ETL))
 		{
 			etl.POST("/customers", customerHandler.CreateCustomer)
 			etl.POST("/officers", etlHandler.CreateOfficer)
 			etl.POST("/loans", etlHandler.CreateLoan)
 			etl.POST("/repayments", etlHandler.CreateRepayment)
 			etl.POST("/sync", etlHandler.BatchSync)
+			etl.POST("/sync/jobs", etlHandler.StartBatchSyncJob)
 		}
 
 		// Customer endpoints
 		v1.GET("/customers", customerHandler.GetCustomers)
+		v1.GET("/customers/export", customerHandler.ExportCustomers)
+		v1.GET("/customers/duplicates", customerHandler.GetDuplicateCustomers)
+		v1.GET("/customers/:customer_id/repayments", customerHandler.GetCustomerRepayments)
 
 		// Portfolio metrics
 		metrics := v1.Group("/metrics")
 		{
 			metrics.GET("/portfolio", dashboardHandler.GetPortfolioMetrics)
+			metrics.GET("/compare", dashboardHandler.ComparePeriods)
+			metrics.GET("/concentration", dashboardHandler.GetPortfolioConcentration)
+			metrics.GET("/term-distribution", dashboardHandler.GetLoanTermDistribution)
+			metrics.GET("/delay-histogram", dashboardHandler.GetRepaymentDelayHistogram)
+			metrics.GET("/rollup", dashboardHandler.GetMetricsRollup)
 		}
 
 		// Collections endpoints
@@ -148,18 +205,31 @@ func setupRouter(cfg *config.Config, etlHandler *handlers.ETLHandler, customerHa
 			collections.GET("/branches", dashboardHandler.GetBranchCollectionsLeaderboard)
 			collections.GET("/officers", dashboardHandler.GetOfficerCollectionsLeaderboard)
 			collections.GET("/daily", dashboardHandler.GetDailyCollections)
+			collections.GET("/heatmap", dashboardHandler.GetCollectionHeatmap)
+			collections.GET("/progress", dashboardHandler.GetCollectionsProgress)
+			collections.GET("/by-channel", dashboardHandler.GetCollectionsByChannel)
+			collections.GET("/by-user-type", dashboardHandler.GetCollectionsByUserType)
 			collections.GET("/agent-activity", dashboardHandler.GetAgentActivity)
+			collections.GET("/agent-activity/started-today", dashboardHandler.GetAgentActivityStartedToday)
 			collections.GET("/agent-activity-detail", dashboardHandler.GetAgentActivityDetail)
 			collections.GET("/repayment-watch", dashboardHandler.GetRepaymentWatch)
 		}
 
 		// Officer endpoints
 		officers := v1.Group("/officers")
+		officers.Use(handlers.StrictQueryParams())
 		{
 			officers.GET("", dashboardHandler.GetOfficers)
+			officers.GET("/sortable-fields", dashboardHandler.GetOfficerSortableFields)
+			officers.GET("/export", dashboardHandler.ExportOfficers)
 			officers.GET("/:officer_id", dashboardHandler.GetOfficerByID)
+			officers.GET("/:officer_id/metrics-breakdown", dashboardHandler.GetOfficerMetricsBreakdown)
+			officers.POST("/:officer_id/simulate-risk", dashboardHandler.SimulateOfficerRisk)
 			officers.PUT("/:officer_id/audit", dashboardHandler.UpdateOfficerAudit)
 			officers.GET("/:officer_id/audit-history", dashboardHandler.GetOfficerAuditHistory)
+			officers.GET("/:officer_id/collection-methods", dashboardHandler.GetOfficerCollectionMethods)
+			officers.GET("/:officer_id/snapshot", dashboardHandler.GetOfficerSnapshot)
+			officers.POST("/snapshots", dashboardHandler.CaptureOfficerSnapshots)
 			officers.GET("/:officer_id/top-risk-loans", dashboardHandler.GetTopRiskLoans)
 		}
 
@@ -168,6 +238,7 @@ func setupRouter(cfg *config.Config, etlHandler *handlers.ETLHandler, customerHa
 		{
 			fimr.GET("/loans", dashboardHandler.GetFIMRLoans)
 			fimr.GET("/summary", dashboardHandler.GetFIMRSummary)
+			fimr.GET("/upcoming", dashboardHandler.GetUpcomingFIMRLoans)
 		}
 
 		// Early indicators endpoints
@@ -181,6 +252,8 @@ func setupRouter(cfg *config.Config, etlHandler *handlers.ETLHandler, customerHa
 		branches := v1.Group("/branches")
 		{
 			branches.GET("", dashboardHandler.GetBranches)
+			branches.GET("/dpd-matrix", dashboardHandler.GetBranchDPDMatrix)
+			branches.GET("/sortable-fields", dashboardHandler.GetBranchSortableFields)
 		}
 
 		// Vertical lead endpoints
@@ -192,18 +265,54 @@ func setupRouter(cfg *config.Config, etlHandler *handlers.ETLHandler, customerHa
 
 		// Loans endpoints
 		loans := v1.Group("/loans")
+		loans.Use(handlers.StrictQueryParams())
 		{
 			loans.GET("", dashboardHandler.GetAllLoans)
+			loans.GET("/count", dashboardHandler.GetLoansCount)
+			loans.GET("/top-risk", dashboardHandler.GetPortfolioTopRiskLoans)
+			loans.GET("/multi-flagged", dashboardHandler.GetMultiFlaggedLoans)
+			loans.GET("/status-breakdown", dashboardHandler.GetLoanStatusBreakdown)
+			loans.GET("/verification-breakdown", dashboardHandler.GetVerificationStatusBreakdown)
+			loans.GET("/sortable-fields", dashboardHandler.GetLoanSortableFields)
+			loans.GET("/review-flags", dashboardHandler.GetLoanReviewFlags)
+			loans.POST("/review-flags", dashboardHandler.FlagLoansForReview)
 			loans.GET("/:loan_id/repayments", dashboardHandler.GetLoanRepayments)
+			loans.GET("/:loan_id/collection-summary", dashboardHandler.GetLoanCollectionSummary)
 			loans.POST("/recalculate-fields", dashboardHandler.RecalculateAllLoanFields)
 			loans.POST("/update-past-maturity", dashboardHandler.UpdatePastMaturityStatus)
+			loans.POST("/recompute-fimr", dashboardHandler.RecomputeFIMRTags)
 			loans.POST("/:loan_id/sync-repayments", dashboardHandler.SyncLoanRepayments)
 		}
 
+		// Repayment audit endpoints
+		v1.GET("/repayments/non-business-days", dashboardHandler.GetNonBusinessDayRepayments)
+
+		// Holiday calendar (excluded from collection business days)
+		holidays := v1.Group("/holidays")
+		{
+			holidays.GET("", dashboardHandler.GetHolidays)
+			holidays.POST("", dashboardHandler.CreateHoliday)
+			holidays.DELETE("/:date", dashboardHandler.DeleteHoliday)
+		}
+
+		// Data freshness for the "data as of" banner
+		v1.GET("/data-freshness", dashboardHandler.GetDataFreshness)
+
+		// Data integrity checks for rows the metric JOINs drop
+		integrity := v1.Group("/integrity")
+		{
+			integrity.GET("/orphans", dashboardHandler.GetOrphans)
+			integrity.GET("/status-mismatch", dashboardHandler.GetStatusMismatches)
+		}
+
 		// Sync endpoints
 		sync := v1.Group("/sync")
 		{
 			sync.POST("/repayments", dashboardHandler.SyncNewRepayments)
+			sync.POST("/loans/stale", dashboardHandler.ResyncStaleLoans)
+			sync.GET("/errors", dashboardHandler.GetSyncErrors)
+			sync.POST("/retry-errors", dashboardHandler.RetrySyncErrors)
+			sync.GET("/jobs/:job_id", etlHandler.GetSyncJob)
 		}
 
 		// Filter endpoints
@@ -223,8 +332,9 @@ func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.CORS.AllowedHeaders, ", "))
+		c.Writer.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.CORS.AllowedMethods, ", "))
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-API-Version, Deprecation, Sunset, Link, "+handlers.RequestIDHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -234,3 +344,125 @@ func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// apiVersionMiddleware stamps every response with the API build version so
+// frontend teams can tell which response shapes they are talking to.
+func apiVersionMiddleware(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("X-API-Version", version)
+		c.Next()
+	}
+}
+
+// deprecatedEndpoint marks a route as deprecated. It sets the Deprecation
+// header and, when provided, the Sunset date and a Link to the successor
+// endpoint. Pass a zero sunset time when no removal date has been agreed yet.
+func deprecatedEndpoint(sunset time.Time, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Deprecation", "true")
+		if !sunset.IsZero() {
+			c.Writer.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if successor != "" {
+			c.Writer.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+		c.Next()
+	}
+}
+
+// etlPayloadGuard protects the ETL write path from oversized or pathological
+// payloads. The body is capped with http.MaxBytesReader (413 when exceeded) and
+// then scanned token by token so that deeply nested documents or huge arrays
+// are rejected before the handlers bind them into memory-heavy structs.
+func etlPayloadGuard(cfg config.ETLConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(cfg.MaxBodyBytes))
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				handlers.RespondError(c, http.StatusRequestEntityTooLarge, models.APIResponse{
+					Status: "error",
+					Error: &models.APIError{
+						Code:    "PAYLOAD_TOO_LARGE",
+						Message: fmt.Sprintf("Request body exceeds the %d byte limit", cfg.MaxBodyBytes),
+					},
+				})
+				return
+			}
+			handlers.RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status: "error",
+				Error: &models.APIError{
+					Code:    models.ErrCodeValidation,
+					Message: "Failed to read request body",
+					Details: map[string]interface{}{"error": err.Error()},
+				},
+			})
+			return
+		}
+
+		if err := checkJSONShape(body, cfg.MaxJSONDepth, cfg.MaxArrayItems); err != nil {
+			handlers.RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status: "error",
+				Error: &models.APIError{
+					Code:    models.ErrCodeValidation,
+					Message: "Invalid request payload",
+					Details: map[string]interface{}{"error": err.Error()},
+				},
+			})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// checkJSONShape walks a JSON document without materialising it and fails if
+// the nesting depth or the number of elements in any array exceeds the limits.
+// Syntax errors are left for the handlers' own binding to report.
+func checkJSONShape(body []byte, maxDepth, maxArrayItems int) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+
+	// arrayCounts tracks the element count of each open container; objects
+	// are tracked with -1 so only arrays are counted.
+	arrayCounts := []int{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// io.EOF or a syntax error; either way there is nothing more to check
+			return nil
+		}
+
+		// Count this value against the enclosing array, if any. Closing
+		// delimiters are not values, and object keys never reach this branch
+		// because objects are tracked with -1.
+		if delim, ok := tok.(json.Delim); !ok || delim == '[' || delim == '{' {
+			if n := len(arrayCounts); n > 0 && arrayCounts[n-1] >= 0 {
+				arrayCounts[n-1]++
+				if arrayCounts[n-1] > maxArrayItems {
+					return fmt.Errorf("array exceeds the maximum of %d items", maxArrayItems)
+				}
+			}
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '[':
+				arrayCounts = append(arrayCounts, 0)
+			case '{':
+				arrayCounts = append(arrayCounts, -1)
+			case ']', '}':
+				arrayCounts = arrayCounts[:len(arrayCounts)-1]
+			}
+			if len(arrayCounts) > maxDepth {
+				return fmt.Errorf("JSON nesting exceeds the maximum depth of %d", maxDepth)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,17 +14,24 @@ type Config struct {
 	Server         ServerConfig
 	Database       DatabaseConfig // SeedsMetrics database (read-write)
 	DjangoDatabase DatabaseConfig // Django database (read-only)
+	ReportingDB    DatabaseConfig // Optional reporting/OLAP database (read-only)
 	Redis          RedisConfig
 	CORS           CORSConfig
 	Logging        LoggingConfig
 	ETL            ETLConfig
 	Metrics        MetricsConfig
+	Dashboard      DashboardConfig
 }
 
 type ServerConfig struct {
 	Port    string
 	Host    string
 	GinMode string
+
+	// CompressionEnabled gzips /api/v1 responses for clients that accept it;
+	// responses smaller than CompressionMinBytes are sent uncompressed.
+	CompressionEnabled  bool
+	CompressionMinBytes int
 }
 
 type DatabaseConfig struct {
@@ -60,6 +68,28 @@ type LoggingConfig struct {
 type ETLConfig struct {
 	BatchSize      int
 	WorkerInterval time.Duration
+
+	// ETL payload limits. Load rejects values below 1, which would refuse
+	// every request body.
+	MaxBodyBytes  int // Maximum request body size accepted by the ETL endpoints
+	MaxJSONDepth  int // Maximum nesting depth of ETL JSON payloads
+	MaxArrayItems int // Maximum number of elements in any single JSON array
+
+	// RepaymentSyncBatchSize is how many repayments the incremental
+	// repayment sync fetches and commits per chunk.
+	RepaymentSyncBatchSize int
+
+	// SyncRetryAttempts is how many times a sync writes a record before
+	// recording it in sync_errors, when the failure is transient (connection
+	// drop, deadlock, serialization failure). Other failures are recorded on
+	// the first attempt. Retries wait SyncRetryBackoff times the attempt.
+	SyncRetryAttempts int
+	SyncRetryBackoff  time.Duration
+
+	// SyncErrorMaxAttempts is the cumulative attempts, across the original
+	// sync and POST /sync/retry-errors runs, after which a failing record is
+	// marked dead and no longer retried.
+	SyncErrorMaxAttempts int
 }
 
 type MetricsConfig struct {
@@ -67,15 +97,162 @@ type MetricsConfig struct {
 	CacheEnabled        bool
 }
 
+// DashboardConfig holds tunables for the dashboard repository and handlers
+type DashboardConfig struct {
+	// NormalizeOutstanding enables the second step of RecalculateAllLoanFields,
+	// which overwrites total_outstanding/actual_outstanding to enforce business
+	// invariants. Disable it where Django is authoritative for those values.
+	NormalizeOutstanding bool
+
+	// Repayment health bands on repayment_delay_rate (0-100). Loans at or above
+	// DelayRateExcellentThreshold are "excellent", at or above
+	// DelayRateOkayThreshold are "okay", and the rest "critical". The
+	// delay_type=risky loan filter uses DelayRateRiskyThreshold as its cutoff.
+	DelayRateExcellentThreshold float64
+	DelayRateOkayThreshold      float64
+	DelayRateRiskyThreshold     float64
+
+	// IncludeNullUserType controls whether officers with no user_type are
+	// counted alongside the allowed user types in dashboard queries.
+	IncludeNullUserType bool
+
+	// DailyRepaymentFallback derives the expected daily repayment as
+	// repayment_amount / loan_term_days for loans synced without a
+	// daily_repayment_amount, instead of treating them as due nothing.
+	DailyRepaymentFallback bool
+
+	// MaxPaginationOffset caps (page-1)*limit on paginated list queries so
+	// deep pages can't make Postgres scan and discard millions of rows.
+	// 0 disables the cap.
+	MaxPaginationOffset int
+
+	// OpenDjangoStatuses are the django_status values that count as an open
+	// (active) loan for officer aggregation, repayment watch, vertical lead
+	// daily targets and the FIMR drilldown default.
+	OpenDjangoStatuses []string
+
+	// FIMRDefaultDjangoStatus is the comma-separated django_status scope the
+	// FIMR loans drilldown applies when the caller doesn't pass django_status.
+	// Defaults to OpenDjangoStatuses.
+	FIMRDefaultDjangoStatus string
+
+	// FIMRGracePeriodDays is how many days past first_payment_due_date the
+	// first payment may arrive before POST /loans/recompute-fimr tags a loan
+	// as FIMR.
+	FIMRGracePeriodDays int
+
+	// FIMRUpcomingDays is how many days ahead GET /fimr/upcoming looks for
+	// unpaid first installments (1 = due today or tomorrow); the days query
+	// parameter overrides it.
+	FIMRUpcomingDays int
+
+	// A loan counts as fimr_recovered when its first payment arrived after
+	// first_payment_due_date but it has repaid within the last
+	// FIMRRecoveredRecentDays days (the default 5 is the complement of the
+	// 6-day quiet_loans cutoff) and is at most FIMRRecoveredMaxDPD days past due.
+	FIMRRecoveredRecentDays int
+	FIMRRecoveredMaxDPD     int
+
+	// MaxAuditHistoryLimit caps the limit parameter of
+	// GET /officers/:officer_id/audit-history; larger values are clamped.
+	// Load rejects values below 1.
+	MaxAuditHistoryLimit int
+
+	// MaxCustomerPageLimit caps the limit parameter of GET /customers;
+	// larger values are clamped. GET /customers/export streams the full list.
+	MaxCustomerPageLimit int
+
+	// ExcludedOfficerIDs lists officers (test accounts, internal staff) whose
+	// loans are left out of every dashboard metric, total and leaderboard.
+	ExcludedOfficerIDs []string
+
+	// StaleDataAfterHours is how long after the last completed sync run
+	// GET /data-freshness reports the data as stale. 0 disables the flag.
+	StaleDataAfterHours int
+
+	// FeeAllocationMethod decides how officer metrics split each loan's
+	// collected repayments into fees and interest collected: "pro_rata"
+	// (default) in proportion to fee_amount and loan_amount*interest_rate
+	// within the total repayable, or "waterfall" to fees first, then
+	// interest, then principal.
+	FeeAllocationMethod string
+
+	// LeaderboardMinLoans and LeaderboardMinPortfolio are the default
+	// thresholds an officer must meet (loan count, portfolio_total) to appear
+	// on the officer collections leaderboard; the min_loans/min_portfolio
+	// query parameters override them. 0 includes everyone.
+	LeaderboardMinLoans     int
+	LeaderboardMinPortfolio float64
+
+	// RatioPrecision is the number of decimal places 0-1 ratio fields
+	// (today_rate, npl_ratio, collection_rate_today, ...) are rounded to in
+	// responses. 0-100 percentage fields (percentage_of_due_collected, ...)
+	// are rounded to two fewer places, so both carry the same digits.
+	RatioPrecision int
+
+	// LogQueryTiming logs the duration of each GetLoansSummaryMetrics
+	// sub-query. Defaults to on when LOG_LEVEL is debug.
+	LogQueryTiming bool
+
+	// SlowQueryThreshold is the duration at which a dashboard sub-query is
+	// logged as slow; with ExplainSlowQueries its EXPLAIN plan is logged too.
+	// 0 disables slow query logging.
+	SlowQueryThreshold time.Duration
+	ExplainSlowQueries bool
+
+	// ExplainTimeout bounds the EXPLAIN run for a slow query, which happens
+	// in the request path.
+	ExplainTimeout time.Duration
+
+	// FilterOptionsCacheTTL is how long GET /filters/:type results are cached
+	// in memory. The cache is also cleared whenever a sync completes. 0
+	// disables caching.
+	FilterOptionsCacheTTL time.Duration
+
+	// CollectionsDefaultPeriod is the period used by the collections daily
+	// chart, progress, by-channel and non-business-day repayments endpoints
+	// when the request has no period param. It must be one of
+	// CollectionsPeriods.
+	CollectionsDefaultPeriod string
+
+	// RecentRepaymentDays is the recent-repayment window of the active-loan
+	// classification: a loan with a balance above 2000 is active when its
+	// last repayment was fewer than this many days ago, otherwise inactive.
+	RecentRepaymentDays int
+
+	// AtRiskOfficerRule decides which officers count towards the portfolio
+	// at-risk officers figures: "activity" (default) for average days since
+	// last repayment above 10 with average loan age above 14, "risk_band" for
+	// officers whose risk band is in AtRiskOfficerBands, or "par15" for
+	// officers whose PAR15 (overdue_15d / total portfolio) is above
+	// AtRiskOfficerPAR15Threshold.
+	AtRiskOfficerRule           string
+	AtRiskOfficerBands          []string
+	AtRiskOfficerPAR15Threshold float64
+
+	// LoanTermBucketEdges are the upper bounds, in days, of the loan_term_days
+	// buckets reported by GET /metrics/term-distribution. Edges 30,90 give
+	// <=30d, 31-90d and 91d+.
+	LoanTermBucketEdges []int
+}
+
+// CollectionsPeriods lists the named periods the collections endpoints accept.
+var CollectionsPeriods = []string{"today", "yesterday", "this_week", "last_week", "this_month", "last_month", "last_7_days"}
+
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
+	openDjangoStatuses := getEnvAsSlice("DASHBOARD_OPEN_DJANGO_STATUSES", []string{"OPEN", "PAST_MATURITY"})
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:    getEnv("SERVER_PORT", "8080"),
 			Host:    getEnv("SERVER_HOST", "0.0.0.0"),
 			GinMode: getEnv("GIN_MODE", "release"),
+
+			CompressionEnabled:  getEnvAsBool("SERVER_COMPRESSION_ENABLED", true),
+			CompressionMinBytes: getEnvAsInt("SERVER_COMPRESSION_MIN_BYTES", 1024),
 		},
 		Database: DatabaseConfig{
 			Host:               getEnv("DB_HOST", "localhost"),
@@ -99,6 +276,17 @@ func Load() (*Config, error) {
 			MaxIdleConnections: getEnvAsInt("DJANGO_DB_MAX_IDLE_CONNECTIONS", 2),
 			ConnMaxLifetime:    getEnvAsDuration("DJANGO_DB_CONNECTION_MAX_LIFETIME", 5*time.Minute),
 		},
+		ReportingDB: DatabaseConfig{
+			Host:               getEnv("REPORTING_DB_HOST", ""),
+			Port:               getEnv("REPORTING_DB_PORT", "5432"),
+			User:               getEnv("REPORTING_DB_USER", ""),
+			Password:           getEnv("REPORTING_DB_PASSWORD", ""),
+			DBName:             getEnv("REPORTING_DB_NAME", ""),
+			SSLMode:            getEnv("REPORTING_DB_SSLMODE", "require"),
+			MaxConnections:     getEnvAsInt("REPORTING_DB_MAX_CONNECTIONS", 10),
+			MaxIdleConnections: getEnvAsInt("REPORTING_DB_MAX_IDLE_CONNECTIONS", 2),
+			ConnMaxLifetime:    getEnvAsDuration("REPORTING_DB_CONNECTION_MAX_LIFETIME", 5*time.Minute),
+		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
 			Port:     getEnv("REDIS_PORT", "6379"),
@@ -108,8 +296,11 @@ func Load() (*Config, error) {
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
-			AllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+			AllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{
+				"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization",
+				"Accept", "Origin", "Cache-Control", "X-Requested-With", "Idempotency-Key", "X-Request-ID",
+			}),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -118,16 +309,90 @@ func Load() (*Config, error) {
 		ETL: ETLConfig{
 			BatchSize:      getEnvAsInt("ETL_BATCH_SIZE", 1000),
 			WorkerInterval: getEnvAsDuration("ETL_WORKER_INTERVAL", 15*time.Minute),
+			MaxBodyBytes:   getEnvAsInt("ETL_MAX_BODY_BYTES", 10<<20), // 10MB
+			MaxJSONDepth:   getEnvAsInt("ETL_MAX_JSON_DEPTH", 10),
+			MaxArrayItems:  getEnvAsInt("ETL_MAX_ARRAY_ITEMS", 5000),
+
+			RepaymentSyncBatchSize: getEnvAsInt("ETL_REPAYMENT_SYNC_BATCH_SIZE", 5000),
+			SyncRetryAttempts:      getEnvAsInt("ETL_SYNC_RETRY_ATTEMPTS", 3),
+			SyncRetryBackoff:       getEnvAsDuration("ETL_SYNC_RETRY_BACKOFF", 500*time.Millisecond),
+			SyncErrorMaxAttempts:   getEnvAsInt("ETL_SYNC_ERROR_MAX_ATTEMPTS", 10),
 		},
 		Metrics: MetricsConfig{
 			CalculationInterval: getEnvAsDuration("METRICS_CALCULATION_INTERVAL", 30*time.Minute),
 			CacheEnabled:        getEnvAsBool("METRICS_CACHE_ENABLED", true),
 		},
+		Dashboard: DashboardConfig{
+			NormalizeOutstanding:        getEnvAsBool("DASHBOARD_NORMALIZE_OUTSTANDING", true),
+			DelayRateExcellentThreshold: getEnvAsFloat("DASHBOARD_DELAY_RATE_EXCELLENT_THRESHOLD", 80),
+			DelayRateOkayThreshold:      getEnvAsFloat("DASHBOARD_DELAY_RATE_OKAY_THRESHOLD", 40),
+			DelayRateRiskyThreshold:     getEnvAsFloat("DASHBOARD_DELAY_RATE_RISKY_THRESHOLD", 60),
+			IncludeNullUserType:         getEnvAsBool("DASHBOARD_INCLUDE_NULL_USER_TYPE", true),
+			DailyRepaymentFallback:      getEnvAsBool("DASHBOARD_DAILY_REPAYMENT_FALLBACK", false),
+			MaxPaginationOffset:         getEnvAsInt("DASHBOARD_MAX_PAGINATION_OFFSET", 50000),
+			OpenDjangoStatuses:          openDjangoStatuses,
+			FIMRDefaultDjangoStatus:     getEnv("DASHBOARD_FIMR_DEFAULT_DJANGO_STATUS", strings.Join(openDjangoStatuses, ",")),
+			FIMRGracePeriodDays:         getEnvAsInt("DASHBOARD_FIMR_GRACE_PERIOD_DAYS", 0),
+			FIMRUpcomingDays:            getEnvAsInt("DASHBOARD_FIMR_UPCOMING_DAYS", 1),
+			FIMRRecoveredRecentDays:     getEnvAsInt("DASHBOARD_FIMR_RECOVERED_RECENT_DAYS", 5),
+			FIMRRecoveredMaxDPD:         getEnvAsInt("DASHBOARD_FIMR_RECOVERED_MAX_DPD", 7),
+			MaxAuditHistoryLimit:        getEnvAsInt("DASHBOARD_MAX_AUDIT_HISTORY_LIMIT", 100),
+			MaxCustomerPageLimit:        getEnvAsInt("DASHBOARD_MAX_CUSTOMER_PAGE_LIMIT", 500),
+			ExcludedOfficerIDs:          getEnvAsSlice("DASHBOARD_EXCLUDED_OFFICER_IDS", nil),
+			StaleDataAfterHours:         getEnvAsInt("DASHBOARD_STALE_DATA_AFTER_HOURS", 24),
+			FeeAllocationMethod:         getEnv("DASHBOARD_FEE_ALLOCATION_METHOD", "pro_rata"),
+			LeaderboardMinLoans:         getEnvAsInt("DASHBOARD_LEADERBOARD_MIN_LOANS", 0),
+			LeaderboardMinPortfolio:     getEnvAsFloat("DASHBOARD_LEADERBOARD_MIN_PORTFOLIO", 0),
+			RatioPrecision:              getEnvAsInt("DASHBOARD_RATIO_PRECISION", 4),
+			LogQueryTiming:              getEnvAsBool("DASHBOARD_LOG_QUERY_TIMING", getEnv("LOG_LEVEL", "info") == "debug"),
+			SlowQueryThreshold:          getEnvAsDuration("DASHBOARD_SLOW_QUERY_THRESHOLD", 0),
+			ExplainSlowQueries:          getEnvAsBool("DASHBOARD_EXPLAIN_SLOW_QUERIES", false),
+			ExplainTimeout:              getEnvAsDuration("DASHBOARD_EXPLAIN_TIMEOUT", 5*time.Second),
+			FilterOptionsCacheTTL:       getEnvAsDuration("DASHBOARD_FILTER_OPTIONS_CACHE_TTL", 5*time.Minute),
+			CollectionsDefaultPeriod:    getEnv("DASHBOARD_COLLECTIONS_DEFAULT_PERIOD", "today"),
+			RecentRepaymentDays:         getEnvAsInt("DASHBOARD_RECENT_REPAYMENT_DAYS", 6),
+			LoanTermBucketEdges:         getEnvAsIntSlice("DASHBOARD_LOAN_TERM_BUCKET_EDGES", []int{30, 90}),
+			AtRiskOfficerRule:           getEnv("DASHBOARD_AT_RISK_OFFICER_RULE", "activity"),
+			AtRiskOfficerBands:          getEnvAsSlice("DASHBOARD_AT_RISK_OFFICER_BANDS", []string{"Red", "Amber"}),
+			AtRiskOfficerPAR15Threshold: getEnvAsFloat("DASHBOARD_AT_RISK_OFFICER_PAR15_THRESHOLD", 0.10),
+		},
+	}
+
+	for _, limit := range []struct {
+		env   string
+		value int
+	}{
+		{"ETL_MAX_BODY_BYTES", config.ETL.MaxBodyBytes},
+		{"ETL_MAX_JSON_DEPTH", config.ETL.MaxJSONDepth},
+		{"ETL_MAX_ARRAY_ITEMS", config.ETL.MaxArrayItems},
+	} {
+		if limit.value < 1 {
+			return nil, fmt.Errorf("%s must be at least 1, got %d", limit.env, limit.value)
+		}
+	}
+	if config.Dashboard.MaxAuditHistoryLimit < 1 {
+		return nil, fmt.Errorf("DASHBOARD_MAX_AUDIT_HISTORY_LIMIT must be at least 1, got %d", config.Dashboard.MaxAuditHistoryLimit)
+	}
+	if config.Dashboard.ExplainSlowQueries && config.Dashboard.ExplainTimeout <= 0 {
+		return nil, fmt.Errorf("DASHBOARD_EXPLAIN_TIMEOUT must be positive, got %s", config.Dashboard.ExplainTimeout)
+	}
+	if !isCollectionsPeriod(config.Dashboard.CollectionsDefaultPeriod) {
+		return nil, fmt.Errorf("DASHBOARD_COLLECTIONS_DEFAULT_PERIOD must be one of %s, got %q",
+			strings.Join(CollectionsPeriods, ", "), config.Dashboard.CollectionsDefaultPeriod)
 	}
 
 	return config, nil
 }
 
+func isCollectionsPeriod(period string) bool {
+	for _, name := range CollectionsPeriods {
+		if period == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *DatabaseConfig) ConnectionString() string {
 	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -135,6 +400,12 @@ func (c *DatabaseConfig) ConnectionString() string {
 	)
 }
 
+// IsConfigured reports whether a host has been set for this database. Used for
+// optional connections such as the reporting database.
+func (c *DatabaseConfig) IsConfigured() bool {
+	return c.Host != ""
+}
+
 func (c *RedisConfig) Address() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
@@ -154,6 +425,14 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.ParseBool(valueStr); err == nil {
@@ -183,6 +462,25 @@ func getEnvAsSlice(key string, defaultValue []string) []string {
 	return result
 }
 
+// getEnvAsIntSlice parses a comma-separated list of integers, falling back
+// to defaultValue if the variable is unset or any entry is not an integer.
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	values := getEnvAsSlice(key, nil)
+	if len(values) == 0 {
+		return defaultValue
+	}
+
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		value, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return defaultValue
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
 func splitString(s, sep string) []string {
 	var result []string
 	current := ""
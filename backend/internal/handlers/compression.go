@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipCompression gzips responses for clients whose Accept-Encoding allows
+// gzip (see acceptsGzip).
+// Bodies are buffered until they reach minSize bytes, so small responses go
+// out uncompressed; anything larger is compressed as it is written. A Flush
+// before the threshold is reached (e.g. the streaming CSV exports) switches
+// to compression straight away and flushes the compressed stream through to
+// the client, so streaming keeps working.
+func GzipCompression(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == "HEAD" || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		gw := &gzipResponseWriter{ResponseWriter: original, minSize: minSize}
+		c.Writer = gw
+		c.Header("Vary", "Accept-Encoding")
+
+		c.Next()
+
+		if err := gw.finish(); err != nil {
+			log.Printf("❌ Failed to finish compressed response for %s: %v", c.Request.URL.Path, err)
+		}
+		c.Writer = original
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip: gzip, or
+// failing that *, must be listed with a non-zero q-value. "gzip;q=0" opts out,
+// and a q-value that does not parse counts as 0.
+func acceptsGzip(acceptEncoding string) bool {
+	gzipQ, wildcardQ := -1.0, -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip":
+			gzipQ = q
+		case "*":
+			wildcardQ = q
+		}
+	}
+
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return wildcardQ > 0
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the body is large enough to compress, then either gzips it or passes it
+// through unchanged.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// WriteString is overridden so io.WriteString doesn't bypass Write via the
+// embedded gin.ResponseWriter.
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush commits to compression if nothing has been decided yet, then pushes
+// everything written so far to the client.
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			log.Printf("❌ Failed to flush compressed response: %v", err)
+			return
+		}
+	}
+	if w.gz != nil {
+		if err := w.gz.Flush(); err != nil {
+			log.Printf("❌ Failed to flush compressed response: %v", err)
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide starts compressed or plain output and writes any buffered bytes.
+// A response that already carries a Content-Encoding is never re-encoded.
+func (w *gzipResponseWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.ResponseWriter.Header()
+	if compress && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buffered := w.buf
+	w.buf = nil
+	_, err := w.Write(buffered)
+	return err
+}
+
+// finish sends a response that stayed below the threshold as is, or closes
+// the gzip stream of a compressed one.
+func (w *gzipResponseWriter) finish() error {
+	if !w.decided {
+		return w.decide(false)
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
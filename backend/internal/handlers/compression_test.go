@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"gzip, deflate, br", true},
+		{"GZIP", true},
+		{"deflate, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0", false},
+		{"gzip;q=abc", false},
+		{"identity", false},
+		{"*", true},
+		{"*;q=0", false},
+		{"gzip;q=0, *", false},
+		{"*;q=0, gzip", true},
+		{"x-gzipped", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, acceptsGzip(tt.header), "Accept-Encoding: %q", tt.header)
+	}
+}
+
+// newCompressionRouter serves /small and /large bodies either side of the
+// 100-byte threshold, and /flush, which flushes before reaching it.
+func newCompressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GzipCompression(100))
+	router.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "small body")
+	})
+	router.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("x", 1000))
+	})
+	router.GET("/flush", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteString("first,")
+		c.Writer.Flush()
+		c.Writer.WriteString("second")
+	})
+	return router
+}
+
+func serveCompression(t *testing.T, path, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	newCompressionRouter().ServeHTTP(w, req)
+	return w
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+	zr, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGzipCompressionSmallBodyStaysPlain(t *testing.T) {
+	w := serveCompression(t, "/small", "gzip")
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "small body", w.Body.String())
+}
+
+func TestGzipCompressionLargeBodyIsCompressed(t *testing.T) {
+	w := serveCompression(t, "/large", "gzip")
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, strings.Repeat("x", 1000), gunzip(t, w.Body))
+}
+
+func TestGzipCompressionFlushBeforeThresholdCompresses(t *testing.T) {
+	w := serveCompression(t, "/flush", "gzip")
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "first,second", gunzip(t, w.Body))
+}
+
+func TestGzipCompressionHonoursOptOut(t *testing.T) {
+	for _, acceptEncoding := range []string{"", "gzip;q=0", "identity"} {
+		w := serveCompression(t, "/large", acceptEncoding)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"), "Accept-Encoding: %q", acceptEncoding)
+		assert.Equal(t, strings.Repeat("x", 1000), w.Body.String(), "Accept-Encoding: %q", acceptEncoding)
+	}
+}
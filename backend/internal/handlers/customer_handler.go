@@ -1,20 +1,30 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/models"
 	"github.com/seeds-metrics/analytics-backend/internal/repository"
 )
 
 type CustomerHandler struct {
-	customerRepo *repository.CustomerRepository
+	customerRepo  *repository.CustomerRepository
+	repaymentRepo *repository.RepaymentRepository
+	cfg           config.DashboardConfig
 }
 
-func NewCustomerHandler(customerRepo *repository.CustomerRepository) *CustomerHandler {
+func NewCustomerHandler(customerRepo *repository.CustomerRepository, repaymentRepo *repository.RepaymentRepository, cfg config.DashboardConfig) *CustomerHandler {
 	return &CustomerHandler{
-		customerRepo: customerRepo,
+		customerRepo:  customerRepo,
+		repaymentRepo: repaymentRepo,
+		cfg:           cfg,
 	}
 }
 
@@ -32,7 +42,7 @@ func NewCustomerHandler(customerRepo *repository.CustomerRepository) *CustomerHa
 func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
 	var input models.CustomerInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "VALIDATION_ERROR",
@@ -45,13 +55,11 @@ func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
 
 	// Create customer
 	if err := h.customerRepo.Create(c.Request.Context(), &input); err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Status: "error",
-			Error: &models.APIError{
-				Code:    "DATABASE_ERROR",
-				Message: "Failed to create customer",
-				Details: map[string]interface{}{"error": err.Error()},
-			},
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create customer",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -66,30 +74,246 @@ func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
 }
 
 // GetCustomers handles GET /api/v1/customers
-// @Summary Get all customers
-// @Description Retrieve a list of all customers
+// @Summary List customers
+// @Description Retrieve one page of customers ordered by name, with the total number of matching customers. search narrows the list to customers whose name or phone contains it, ignoring case. limit is capped at DASHBOARD_MAX_CUSTOMER_PAGE_LIMIT; use /customers/export for the full list.
 // @Tags Customers
 // @Produce json
+// @Param search query string false "Substring of the customer name or phone"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Customers per page (max DASHBOARD_MAX_CUSTOMER_PAGE_LIMIT)" default(50)
 // @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /customers [get]
 func (h *CustomerHandler) GetCustomers(c *gin.Context) {
-	customers, err := h.customerRepo.List(c.Request.Context())
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if maxLimit := h.cfg.MaxCustomerPageLimit; maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+	offset, ok := h.pageOffset(c, page, limit, "narrow the search or use /customers/export")
+	if !ok {
+		return
+	}
+
+	customers, total, err := h.customerRepo.List(c.Request.Context(), c.Query("search"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Status: "error",
-			Error: &models.APIError{
-				Code:    "DATABASE_ERROR",
-				Message: "Failed to retrieve customers",
-				Details: map[string]interface{}{"error": err.Error()},
-			},
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve customers",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"customers":  customers,
+			"pagination": newPagination(page, limit, total),
+		},
+	})
+}
+
+// pageOffset returns the row offset of page, or responds 400 and returns false
+// when it lies past DASHBOARD_MAX_PAGINATION_OFFSET rows. hint tells the
+// caller how to reach the data instead.
+func (h *CustomerHandler) pageOffset(c *gin.Context, page, limit int, hint string) (int, bool) {
+	offset := (page - 1) * limit
+	if maxOffset := h.cfg.MaxPaginationOffset; maxOffset > 0 && offset > maxOffset {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Page out of range",
+			Error: newAPIError(models.ErrCodeValidation, fmt.Sprintf(
+				"page %d with limit %d exceeds the maximum offset of %d rows; %s", page, limit, maxOffset, hint)),
+		})
+		return 0, false
+	}
+	return offset, true
+}
+
+var customerExportHeader = []string{
+	"customer_id", "customer_name", "customer_phone", "customer_email",
+	"date_of_birth", "gender", "state", "lga", "address",
+	"kyc_status", "kyc_verified_date", "created_at", "updated_at",
+}
+
+func customerExportRow(cu *models.Customer) []string {
+	str := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+	date := func(v *time.Time) string {
+		if v == nil {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	}
+	return []string{
+		cu.CustomerID, cu.CustomerName, str(cu.CustomerPhone), str(cu.CustomerEmail),
+		date(cu.DateOfBirth), str(cu.Gender), str(cu.State), str(cu.LGA), str(cu.Address),
+		str(cu.KYCStatus), date(cu.KYCVerifiedDate),
+		cu.CreatedAt.Format(time.RFC3339), cu.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ExportCustomers handles GET /api/v1/customers/export
+// @Summary Export customers as CSV
+// @Description Streams every customer matching search (no pagination) as CSV, ordered by name. Rows are written as they are read from the database, so the full customer base is never held in memory.
+// @Tags Customers
+// @Produce text/csv
+// @Param search query string false "Substring of the customer name or phone"
+// @Success 200 {file} file
+// @Failure 500 {object} models.APIResponse
+// @Router /customers/export [get]
+func (h *CustomerHandler) ExportCustomers(c *gin.Context) {
+	// The header is only written with the first row, so a query that fails
+	// up front still gets a JSON error response.
+	var w *csv.Writer
+	start := func() error {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=customers_%s.csv", time.Now().Format("20060102")))
+		c.Status(http.StatusOK)
+		w = csv.NewWriter(c.Writer)
+		return w.Write(customerExportHeader)
+	}
+
+	rowCount := 0
+	err := h.customerRepo.Stream(c.Request.Context(), c.Query("search"), func(customer *models.Customer) error {
+		if w == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := w.Write(customerExportRow(customer)); err != nil {
+			return err
+		}
+		rowCount++
+		// Flush periodically so rows reach the client while the query runs
+		if rowCount%500 == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+		return w.Error()
+	})
+	if err != nil && w == nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to export customers",
+			Error:   apiErr,
+		})
+		return
+	}
+	if err != nil {
+		// Headers are already sent; the truncated file is all we can do.
+		log.Printf("❌ Customer export aborted after %d rows: %v", rowCount, err)
+		return
+	}
+
+	if w == nil {
+		if err := start(); err != nil {
+			log.Printf("❌ Failed to write customer export header: %v", err)
+			return
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("❌ Failed to write customer export: %v", err)
+		return
+	}
+	log.Printf("📤 Exported %d customers as CSV", rowCount)
+}
+
+// GetDuplicateCustomers handles GET /api/v1/customers/duplicates
+// @Summary Find phone numbers shared by multiple customers
+// @Description Lists phone numbers that appear on loans under more than one distinct customer_id (possible duplicates or fraud), with the loans and officers involved
+// @Tags Customers
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Phone numbers per page (max 200)" default(50)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /customers/duplicates [get]
+func (h *CustomerHandler) GetDuplicateCustomers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	offset, ok := h.pageOffset(c, page, limit, "use a larger limit (max 200)")
+	if !ok {
+		return
+	}
+
+	groups, total, err := h.customerRepo.FindDuplicatePhones(c.Request.Context(), limit, offset)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve duplicate customers",
+			Error:   apiErr,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
-		Data:   customers,
+		Data: map[string]interface{}{
+			"duplicates": groups,
+			"pagination": newPagination(page, limit, total),
+		},
 	})
 }
 
+// GetCustomerRepayments handles GET /api/v1/customers/:customer_id/repayments
+// @Summary Get a customer's repayment timeline
+// @Description Retrieve all non-reversed repayments across the customer's loans in chronological order, with each loan's resulting balance. include_reversed=true also lists reversed repayments, flagged by is_reversed; they don't reduce the balance.
+// @Tags Customers
+// @Produce json
+// @Param customer_id path string true "Customer ID"
+// @Param include_reversed query bool false "Also list reversed repayments" default(false)
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /customers/{customer_id}/repayments [get]
+func (h *CustomerHandler) GetCustomerRepayments(c *gin.Context) {
+	customerID := c.Param("customer_id")
+	includeReversed, _ := strconv.ParseBool(c.Query("include_reversed"))
+
+	timeline, err := h.repaymentRepo.GetTimelineByCustomerID(c.Request.Context(), customerID, includeReversed)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve customer repayments",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"customer_id": customerID,
+			"repayments":  timeline,
+			"pagination":  newPagination(1, len(timeline), len(timeline)),
+		},
+	})
+}
@@ -1,13 +1,24 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/models"
 	"github.com/seeds-metrics/analytics-backend/internal/repository"
 	"github.com/seeds-metrics/analytics-backend/internal/services"
@@ -19,15 +30,35 @@ type DashboardHandler struct {
 	repaymentRepo  *repository.RepaymentRepository
 	metricsService *services.MetricsService
 	syncService    *services.SyncService
+	syncRepo       *repository.SyncRepository
+	cfg            config.DashboardConfig
 }
 
 // NewDashboardHandler creates a new dashboard handler
-func NewDashboardHandler(dashboardRepo *repository.DashboardRepository, repaymentRepo *repository.RepaymentRepository, metricsService *services.MetricsService, syncService *services.SyncService) *DashboardHandler {
+func NewDashboardHandler(dashboardRepo *repository.DashboardRepository, repaymentRepo *repository.RepaymentRepository, metricsService *services.MetricsService, syncService *services.SyncService, syncRepo *repository.SyncRepository, cfg config.DashboardConfig) *DashboardHandler {
 	return &DashboardHandler{
 		dashboardRepo:  dashboardRepo,
 		repaymentRepo:  repaymentRepo,
 		metricsService: metricsService,
 		syncService:    syncService,
+		syncRepo:       syncRepo,
+		cfg:            cfg,
+	}
+}
+
+// newPagination builds the standard pagination block embedded in every list
+// response. Endpoints that return their full result set use page 1 with
+// limit equal to the number of rows.
+func newPagination(page, limit, total int) *models.DashboardPagination {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return &models.DashboardPagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
 	}
 }
 
@@ -39,12 +70,32 @@ func newAPIError(code, message string) *models.APIError {
 	}
 }
 
+// classifyError maps a repository/service error onto an HTTP status and a
+// typed API error code so clients can tell timeouts, bad filters and missing
+// records apart from genuine internal failures.
+func classifyError(err error) (int, *models.APIError) {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &pqErr) && pqErr.Code == "57014": // query_canceled (statement_timeout)
+		return http.StatusGatewayTimeout, newAPIError(models.ErrCodeDBTimeout, err.Error())
+	case errors.Is(err, repository.ErrInvalidFilter):
+		return http.StatusBadRequest, newAPIError(models.ErrCodeInvalidFilter, err.Error())
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound, newAPIError(models.ErrCodeNotFound, err.Error())
+	default:
+		return http.StatusInternalServerError, newAPIError(models.ErrCodeInternal, err.Error())
+	}
+}
+
 // GetPortfolioMetrics handles GET /api/v1/metrics/portfolio
 // @Summary Get portfolio metrics
 // @Description Get aggregated portfolio-level metrics including total overdue, DQI, AYR, and risk scores
 // @Tags Metrics
 // @Accept json
 // @Produce json
+// @Param wave query string false "Filter by wave"
+// @Param include_closed query bool false "Include closed/completed loans in officer-derived metrics" default(false)
 // @Success 200 {object} models.APIResponse{data=models.PortfolioMetrics}
 // @Failure 500 {object} models.APIResponse
 // @Router /metrics/portfolio [get]
@@ -54,17 +105,21 @@ func (h *DashboardHandler) GetPortfolioMetrics(c *gin.Context) {
 	if wave := c.Query("wave"); wave != "" {
 		filters["wave"] = wave
 	}
+	if includeClosed, err := strconv.ParseBool(c.Query("include_closed")); err == nil {
+		filters["include_closed"] = includeClosed
+	}
 
 	// Set high limit to fetch all officers for portfolio-level aggregation
 	filters["limit"] = 100000
 
 	// Get all officers with metrics
-	officers, err := h.dashboardRepo.GetOfficers(filters)
+	officers, _, err := h.dashboardRepo.GetOfficers(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve portfolio metrics",
-			Error:   newAPIError("PORTFOLIO_METRICS_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -81,10 +136,11 @@ func (h *DashboardHandler) GetPortfolioMetrics(c *gin.Context) {
 	// Get loan-level metrics for new portfolio cards
 	loanMetrics, err := h.dashboardRepo.GetPortfolioLoanMetrics(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve loan metrics",
-			Error:   newAPIError("LOAN_METRICS_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -98,16 +154,26 @@ func (h *DashboardHandler) GetPortfolioMetrics(c *gin.Context) {
 	portfolio.EarlyROTVolume = loanMetrics.EarlyROTVolume
 	portfolio.LateROTCount = loanMetrics.LateROTCount
 	portfolio.LateROTVolume = loanMetrics.LateROTVolume
+	portfolio.UnknownAgeROTCount = loanMetrics.UnknownAgeROTCount
+	portfolio.UnknownAgeROTVolume = loanMetrics.UnknownAgeROTVolume
+	portfolio.MissingDisbursementDateCount = loanMetrics.MissingDisbursementDateCount
 	portfolio.AvgDaysPastDue = loanMetrics.AvgDaysPastDue
+	portfolio.WeightedAvgDPD = loanMetrics.WeightedAvgDPD
 	portfolio.AvgTimelinessScore = loanMetrics.AvgTimelinessScore
+	portfolio.AvgRepaymentHealth = loanMetrics.AvgRepaymentHealth
+	portfolio.WeightedAvgTimelinessScore = loanMetrics.WeightedAvgTimelinessScore
+	portfolio.WeightedAvgRepaymentHealth = loanMetrics.WeightedAvgRepaymentHealth
+	portfolio.MissingTimelinessScoreCount = loanMetrics.MissingTimelinessScoreCount
+	portfolio.MissingRepaymentHealthCount = loanMetrics.MissingRepaymentHealthCount
 
 	// Get actual overdue amount (only installments due to date)
 	actualOverdue15d, err := h.dashboardRepo.GetActualOverdue15d(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve actual overdue amount",
-			Error:   newAPIError("ACTUAL_OVERDUE_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -116,22 +182,78 @@ func (h *DashboardHandler) GetPortfolioMetrics(c *gin.Context) {
 	// Get total DPD loans count and actual outstanding
 	totalDPDLoansCount, totalDPDActualOutstanding, err := h.dashboardRepo.GetTotalDPDLoans(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve total DPD loans",
-			Error:   newAPIError("TOTAL_DPD_LOANS_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
 	portfolio.TotalDPDLoansCount = totalDPDLoansCount
 	portfolio.TotalDPDActualOutstanding = totalDPDActualOutstanding
 
+	excludedOfficers, err := h.dashboardRepo.CountExcludedOfficers()
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to count excluded officers",
+			Error:   apiErr,
+		})
+		return
+	}
+	portfolio.ExcludedOfficerCount = excludedOfficers
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data:   portfolio,
 	})
 }
 
+// officerComputedSortFields maps the sort_by values that refer to metrics
+// computed by MetricsService (rather than DB columns) to their accessor.
+var officerComputedSortFields = map[string]func(*models.CalculatedMetrics) float64{
+	"risk_score":         func(m *models.CalculatedMetrics) float64 { return float64(m.RiskScore) },
+	"risk_score_norm":    func(m *models.CalculatedMetrics) float64 { return m.RiskScoreNorm },
+	"ayr":                func(m *models.CalculatedMetrics) float64 { return m.AYR },
+	"fimr":               func(m *models.CalculatedMetrics) float64 { return m.FIMR },
+	"dqi":                func(m *models.CalculatedMetrics) float64 { return float64(m.DQI) },
+	"slippage":           func(m *models.CalculatedMetrics) float64 { return m.Slippage },
+	"roll":               func(m *models.CalculatedMetrics) float64 { return m.Roll },
+	"frr":                func(m *models.CalculatedMetrics) float64 { return m.FRR },
+	"yield":              func(m *models.CalculatedMetrics) float64 { return m.Yield },
+	"porr":               func(m *models.CalculatedMetrics) float64 { return m.PORR },
+	"on_time_rate":       func(m *models.CalculatedMetrics) float64 { return m.OnTimeRate },
+	"channel_purity":     func(m *models.CalculatedMetrics) float64 { return m.ChannelPurity },
+	"overdue_15d_volume": func(m *models.CalculatedMetrics) float64 { return m.Overdue15dVolume },
+}
+
+// sortAndPageOfficers sorts officers by a computed metric (ties broken by
+// name) and returns the requested page.
+func sortAndPageOfficers(officers []*models.DashboardOfficerMetrics, metric func(*models.CalculatedMetrics) float64, desc bool, page, limit int) []*models.DashboardOfficerMetrics {
+	sort.SliceStable(officers, func(i, j int) bool {
+		a, b := metric(officers[i].CalculatedMetrics), metric(officers[j].CalculatedMetrics)
+		if a != b {
+			if desc {
+				return a > b
+			}
+			return a < b
+		}
+		return officers[i].Name < officers[j].Name
+	})
+
+	start := (page - 1) * limit
+	if start >= len(officers) {
+		return []*models.DashboardOfficerMetrics{}
+	}
+	end := start + limit
+	if end > len(officers) {
+		end = len(officers)
+	}
+	return officers[start:end]
+}
+
 // GetOfficers handles GET /api/v1/officers
 // @Summary Get all officers
 // @Description Get list of loan officers with their performance metrics and calculated scores
@@ -142,8 +264,13 @@ func (h *DashboardHandler) GetPortfolioMetrics(c *gin.Context) {
 // @Param region query string false "Filter by region"
 // @Param channel query string false "Filter by channel"
 // @Param user_type query string false "Filter by user type"
-// @Param officer_email query string false "Filter by officer email (partial match)"
-// @Param sort_by query string false "Sort field (e.g., risk_score, total_portfolio)"
+// @Param officer_email query string false "Filter by officer email or name"
+// @Param match query string false "How officer_email is matched: contains (substring) or exact (case-insensitive equality)" default(contains)
+// @Param tenure_bucket query string false "Filter by tenure since hire_date: <3mo, 3-6mo, 6-12mo, 1y+ or unknown (comma-separated for multi-select)"
+// @Param loan_type query string false "Only count loans of these types in officer metrics (comma-separated for multi-select)"
+// @Param verification_status query string false "Only count loans with these verification statuses in officer metrics (comma-separated for multi-select)"
+// @Param include_closed query bool false "Include closed/completed loans in officer metrics" default(false)
+// @Param sort_by query string false "Sort field: a DB column (e.g. total_portfolio) or a computed metric (risk_score, risk_score_norm, ayr, fimr, dqi, slippage, roll, frr, yield, porr, on_time_rate, channel_purity, overdue_15d_volume)"
 // @Param sort_dir query string false "Sort direction (asc/desc)"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(50)
@@ -152,6 +279,80 @@ func (h *DashboardHandler) GetPortfolioMetrics(c *gin.Context) {
 // @Router /officers [get]
 func (h *DashboardHandler) GetOfficers(c *gin.Context) {
 	// Parse filters from query parameters
+	filters := parseOfficerFilters(c)
+
+	// Metrics computed by MetricsService can't be sorted in SQL, so for those
+	// the full officer set is fetched, sorted in memory and paginated here.
+	sortBy := c.Query("sort_by")
+	sortMetric, sortOnComputed := officerComputedSortFields[sortBy]
+	if sortBy != "" && !sortOnComputed {
+		filters["sort_by"] = sortBy
+	}
+	if sortDir := c.Query("sort_dir"); sortDir != "" {
+		filters["sort_dir"] = sortDir
+	}
+
+	// Parse pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	filters["page"] = page
+	filters["limit"] = limit
+	if sortOnComputed {
+		filters["unpaginated"] = true
+	}
+
+	// Get officers
+	officers, total, err := h.dashboardRepo.GetOfficers(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve officers",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	// Calculate metrics for each officer
+	for _, officer := range officers {
+		officer.CalculatedMetrics = h.metricsService.CalculateOfficerMetrics(officer.RawMetrics)
+		officer.RiskBand = models.GetRiskBand(officer.CalculatedMetrics.RiskScore)
+	}
+
+	if sortOnComputed {
+		officers = sortAndPageOfficers(officers, sortMetric, strings.EqualFold(c.Query("sort_dir"), "desc"), page, limit)
+	}
+
+	excludedOfficers, err := h.dashboardRepo.CountExcludedOfficers()
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to count excluded officers",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"officers":               officers,
+			"pagination":             newPagination(page, limit, total),
+			"excluded_officer_count": excludedOfficers,
+		},
+	})
+}
+
+// parseOfficerFilters reads the officer list filters shared by GET /officers
+// and GET /officers/export.
+func parseOfficerFilters(c *gin.Context) map[string]interface{} {
 	filters := make(map[string]interface{})
 
 	if branch := c.Query("branch"); branch != "" {
@@ -173,47 +374,167 @@ func (h *DashboardHandler) GetOfficers(c *gin.Context) {
 	if officerEmail := c.Query("officer_email"); officerEmail != "" {
 		filters["officer_email"] = officerEmail
 	}
+	if match := c.Query("match"); match != "" {
+		filters["match"] = match
+	}
+	if tenureBucket := c.Query("tenure_bucket"); tenureBucket != "" {
+		filters["tenure_bucket"] = tenureBucket
+	}
+	// loan_type and verification_status narrow the loans feeding each
+	// officer's metrics; they don't drop officers from the list
+	if loanType := c.Query("loan_type"); loanType != "" {
+		filters["loan_type"] = loanType
+	}
+	if verificationStatus := c.Query("verification_status"); verificationStatus != "" {
+		filters["verification_status"] = verificationStatus
+	}
+	// include_closed=true aggregates over all loans, not just active ones
+	if includeClosed, err := strconv.ParseBool(c.Query("include_closed")); err == nil {
+		filters["include_closed"] = includeClosed
+	}
+
+	return filters
+}
+
+// officerExportHeader is the column order of GET /officers/export.
+var officerExportHeader = []string{
+	"officer_id", "name", "email", "region", "branch", "channel", "user_type", "tenure_bucket",
+	"supervisor_email", "vertical_lead_email", "vertical_lead_name",
+	// Raw metrics
+	"first_miss", "disbursed", "dpd1to6_bal", "amount_due_7d", "moved_to_7to30", "prev_dpd1to6_bal",
+	"fees_collected", "fees_due", "interest_collected", "overdue_15d", "total_portfolio", "par15_mid_month",
+	"waivers", "backdated", "entries", "reversals", "had_float_gap", "active_loans_count",
+	// Calculated metrics
+	"fimr", "slippage", "roll", "frr", "ayr", "dqi", "risk_score", "risk_score_norm", "yield",
+	"overdue_15d_volume", "on_time_rate", "channel_purity", "porr", "avg_timeliness_score",
+	"avg_repayment_health", "avg_days_since_last_repayment", "avg_loan_age", "repayment_delay_rate",
+	"risk_band",
+}
+
+// officerExportRow formats an officer with calculated metrics as a CSV row in
+// officerExportHeader order.
+func officerExportRow(o *models.DashboardOfficerMetrics) []string {
+	str := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	raw, calc := o.RawMetrics, o.CalculatedMetrics
+
+	return []string{
+		o.OfficerID, o.Name, o.Email, o.Region, o.Branch, o.Channel, str(o.UserType), o.TenureBucket,
+		str(o.SupervisorEmail), str(o.VerticalLeadEmail), str(o.VerticalLeadName),
+		strconv.Itoa(raw.FirstMiss), strconv.Itoa(raw.Disbursed), num(raw.Dpd1to6Bal), num(raw.AmountDue7d),
+		num(raw.MovedTo7to30), num(raw.PrevDpd1to6Bal), num(raw.FeesCollected), num(raw.FeesDue),
+		num(raw.InterestCollected), num(raw.Overdue15d), num(raw.TotalPortfolio), num(raw.Par15MidMonth),
+		num(raw.Waivers), strconv.Itoa(raw.Backdated), strconv.Itoa(raw.Entries), strconv.Itoa(raw.Reversals),
+		strconv.FormatBool(raw.HadFloatGap), strconv.Itoa(raw.ActiveLoansCount),
+		num(calc.FIMR), num(calc.Slippage), num(calc.Roll), num(calc.FRR), num(calc.AYR),
+		strconv.Itoa(calc.DQI), strconv.Itoa(calc.RiskScore), num(calc.RiskScoreNorm), num(calc.Yield),
+		num(calc.Overdue15dVolume), num(calc.OnTimeRate), num(calc.ChannelPurity), num(calc.PORR),
+		num(calc.AvgTimelinessScore), num(calc.AvgRepaymentHealth), num(calc.AvgDaysSinceLastRepayment),
+		num(calc.AvgLoanAge), num(calc.RepaymentDelayRate),
+		o.RiskBand,
+	}
+}
+
+// ExportOfficers handles GET /api/v1/officers/export
+// @Summary Export officer metrics as CSV
+// @Description Streams every officer matching the filters (no pagination) as CSV with raw metrics, calculated metrics (FIMR, AYR, DQI, risk score, ...) and risk band. Rows are written as they are read from the database, so the full officer set is never held in memory. Sorting by computed metrics is not supported; sort_by accepts DB columns only.
+// @Tags Officers
+// @Produce text/csv
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param officer_email query string false "Filter by officer email or name"
+// @Param tenure_bucket query string false "Filter by tenure since hire_date (comma-separated for multi-select)"
+// @Param loan_type query string false "Only count loans of these types in officer metrics (comma-separated for multi-select)"
+// @Param verification_status query string false "Only count loans with these verification statuses in officer metrics (comma-separated for multi-select)"
+// @Param include_closed query bool false "Include closed/completed loans in officer metrics" default(false)
+// @Param sort_by query string false "Sort field (DB column, e.g. total_portfolio)"
+// @Param sort_dir query string false "Sort direction (asc/desc)"
+// @Success 200 {file} file
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /officers/export [get]
+func (h *DashboardHandler) ExportOfficers(c *gin.Context) {
+	filters := parseOfficerFilters(c)
 	if sortBy := c.Query("sort_by"); sortBy != "" {
+		if _, computed := officerComputedSortFields[sortBy]; computed {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid sort_by",
+				Error:   newAPIError(models.ErrCodeValidation, "the export can only be sorted by DB columns, not by computed metrics"),
+			})
+			return
+		}
 		filters["sort_by"] = sortBy
 	}
 	if sortDir := c.Query("sort_dir"); sortDir != "" {
 		filters["sort_dir"] = sortDir
 	}
 
-	// Parse pagination
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	filters["page"] = page
-	filters["limit"] = limit
+	// The header is only written with the first row, so a query that fails
+	// up front still gets a JSON error response.
+	var w *csv.Writer
+	start := func() error {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=officer_metrics_%s.csv", time.Now().Format("20060102")))
+		c.Status(http.StatusOK)
+		w = csv.NewWriter(c.Writer)
+		return w.Write(officerExportHeader)
+	}
 
-	// Get officers
-	officers, err := h.dashboardRepo.GetOfficers(filters)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+	rowCount := 0
+	err := h.dashboardRepo.StreamOfficers(filters, func(officer *models.DashboardOfficerMetrics) error {
+		if w == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		officer.CalculatedMetrics = h.metricsService.CalculateOfficerMetrics(officer.RawMetrics)
+		officer.RiskBand = models.GetRiskBand(officer.CalculatedMetrics.RiskScore)
+		if err := w.Write(officerExportRow(officer)); err != nil {
+			return err
+		}
+		rowCount++
+		// Flush periodically so rows reach the client while the query runs
+		if rowCount%500 == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+		return w.Error()
+	})
+	if err != nil && w == nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve officers",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to export officers",
+			Error:   apiErr,
 		})
 		return
 	}
-
-	// Calculate metrics for each officer
-	for _, officer := range officers {
-		officer.CalculatedMetrics = h.metricsService.CalculateOfficerMetrics(officer.RawMetrics)
-		officer.RiskBand = models.GetRiskBand(officer.CalculatedMetrics.RiskScore)
+	if err != nil {
+		// Headers are already sent; the truncated file is all we can do.
+		log.Printf("❌ Officer export aborted after %d rows: %v", rowCount, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, models.APIResponse{
-		Status: "success",
-		Data: map[string]interface{}{
-			"officers": officers,
-			"pagination": map[string]interface{}{
-				"page":  page,
-				"limit": limit,
-				"total": len(officers),
-			},
-		},
-	})
+	if w == nil {
+		if err := start(); err != nil {
+			log.Printf("❌ Failed to write officer export header: %v", err)
+			return
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("❌ Failed to write officer export: %v", err)
+		return
+	}
+	log.Printf("📤 Exported %d officers as CSV", rowCount)
 }
 
 // GetOfficerByID handles GET /api/v1/officers/:officer_id
@@ -231,10 +552,15 @@ func (h *DashboardHandler) GetOfficerByID(c *gin.Context) {
 
 	officer, err := h.dashboardRepo.GetOfficerByID(officerID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		message := "Failed to retrieve officer"
+		if statusCode == http.StatusNotFound {
+			message = "Officer not found"
+		}
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Officer not found",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: message,
+			Error:   apiErr,
 		})
 		return
 	}
@@ -249,7 +575,124 @@ func (h *DashboardHandler) GetOfficerByID(c *gin.Context) {
 	})
 }
 
+// GetOfficerMetricsBreakdown handles GET /api/v1/officers/:officer_id/metrics-breakdown
+// @Summary Get how an officer's metrics were calculated
+// @Description Get each calculated metric (FIMR, AYR, risk score, DQI, ...) for the officer with its formula, the raw inputs it used and intermediate terms such as the individual risk score penalties, so a risk band can be explained.
+// @Tags Officers
+// @Accept json
+// @Produce json
+// @Param officer_id path string true "Officer ID"
+// @Success 200 {object} models.APIResponse{data=models.OfficerMetricsBreakdown}
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /officers/{officer_id}/metrics-breakdown [get]
+func (h *DashboardHandler) GetOfficerMetricsBreakdown(c *gin.Context) {
+	officerID := c.Param("officer_id")
+
+	officer, err := h.dashboardRepo.GetOfficerByID(officerID)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		message := "Failed to retrieve officer"
+		if statusCode == http.StatusNotFound {
+			message = "Officer not found"
+		}
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: message,
+			Error:   apiErr,
+		})
+		return
+	}
+
+	calculated, breakdown := h.metricsService.CalculateOfficerMetricsBreakdown(officer.RawMetrics)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: &models.OfficerMetricsBreakdown{
+			OfficerID:         officer.OfficerID,
+			Name:              officer.Name,
+			RiskBand:          models.GetRiskBand(calculated.RiskScore),
+			RawMetrics:        officer.RawMetrics,
+			CalculatedMetrics: calculated,
+			Breakdown:         breakdown,
+		},
+	})
+}
+
+// SimulateOfficerRisk handles POST /api/v1/officers/:officer_id/simulate-risk
+// @Summary Simulate an officer's risk band under proposed weights
+// @Description Recalculates the officer's metrics, risk score and band with the given risk score penalty weights and returns them next to the current ones, for risk policy what-if analysis. Omitted weights (or an empty body) keep the defaults. Nothing is persisted.
+// @Tags Officers
+// @Accept json
+// @Produce json
+// @Param officer_id path string true "Officer ID"
+// @Param weights body models.RiskWeightsOverride false "Risk weight overrides, each between 0 and 1"
+// @Success 200 {object} models.APIResponse{data=models.OfficerRiskSimulation}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /officers/{officer_id}/simulate-risk [post]
+func (h *DashboardHandler) SimulateOfficerRisk(c *gin.Context) {
+	var override models.RiskWeightsOverride
+	if err := c.ShouldBindJSON(&override); err != nil && !errors.Is(err, io.EOF) {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid request body",
+			Error:   newAPIError(models.ErrCodeValidation, err.Error()),
+		})
+		return
+	}
+	weights, err := services.ResolveRiskWeights(&override)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid risk weights",
+			Error:   newAPIError(models.ErrCodeValidation, err.Error()),
+		})
+		return
+	}
+
+	officer, err := h.dashboardRepo.GetOfficerByID(c.Param("officer_id"))
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		message := "Failed to retrieve officer"
+		if statusCode == http.StatusNotFound {
+			message = "Officer not found"
+		}
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: message,
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   h.metricsService.SimulateOfficerRisk(officer, weights),
+	})
+}
+
 // GetFIMRLoans handles GET /api/v1/fimr/loans
+// @Summary Get FIMR loans
+// @Description Get loans that missed their first installment. Without an explicit django_status the list is scoped to DASHBOARD_FIMR_DEFAULT_DJANGO_STATUS (default: the open statuses in DASHBOARD_OPEN_DJANGO_STATUSES, OPEN,PAST_MATURITY). Each loan carries fimr_recovered: the first payment arrived after first_payment_due_date, but the loan has repaid within DASHBOARD_FIMR_RECOVERED_RECENT_DAYS days and is at most DASHBOARD_FIMR_RECOVERED_MAX_DPD days past due.
+// @Tags FIMR
+// @Accept json
+// @Produce json
+// @Param officer_id query string false "Filter by officer ID"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region"
+// @Param channel query string false "Filter by channel"
+// @Param status query string false "Filter by status"
+// @Param django_status query string false "Filter by raw Django status (comma-separated); defaults to DASHBOARD_FIMR_DEFAULT_DJANGO_STATUS (open statuses)"
+// @Param wave query string false "Filter by wave"
+// @Param fimr_recovered query bool false "true for recovered FIMR loans only, false for un-recovered ones"
+// @Param sort_by query string false "Sort field"
+// @Param sort_dir query string false "Sort direction (asc/desc)"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /fimr/loans [get]
 func (h *DashboardHandler) GetFIMRLoans(c *gin.Context) {
 	// Parse filters
 	filters := make(map[string]interface{})
@@ -270,16 +713,28 @@ func (h *DashboardHandler) GetFIMRLoans(c *gin.Context) {
 		filters["status"] = status
 	}
 
-	// Default django_status filter for FIMR drilldown:
-	// if caller does not specify django_status explicitly, restrict to OPEN and PAST_MATURITY loans.
+	// Default django_status filter for FIMR drilldown: if the caller does not
+	// specify django_status explicitly, restrict to the configured statuses.
 	if djangoStatus := c.Query("django_status"); djangoStatus != "" {
 		filters["django_status"] = djangoStatus
-	} else {
-		filters["django_status"] = "OPEN,PAST_MATURITY"
+	} else if h.cfg.FIMRDefaultDjangoStatus != "" {
+		filters["django_status"] = h.cfg.FIMRDefaultDjangoStatus
 	}
 	if wave := c.Query("wave"); wave != "" {
 		filters["wave"] = wave
 	}
+	if raw := c.Query("fimr_recovered"); raw != "" {
+		recovered, err := strconv.ParseBool(raw)
+		if err != nil {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "fimr_recovered must be true or false",
+				Error:   newAPIError(models.ErrCodeValidation, "invalid fimr_recovered"),
+			})
+			return
+		}
+		filters["fimr_recovered"] = recovered
+	}
 	if sortBy := c.Query("sort_by"); sortBy != "" {
 		filters["sort_by"] = sortBy
 	}
@@ -289,10 +744,75 @@ func (h *DashboardHandler) GetFIMRLoans(c *gin.Context) {
 
 	loans, err := h.dashboardRepo.GetFIMRLoans(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve FIMR loans",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"loans":      loans,
+			"pagination": newPagination(1, len(loans), len(loans)),
+		},
+	})
+}
+
+// GetUpcomingFIMRLoans handles GET /api/v1/fimr/upcoming
+// @Summary List loans approaching FIMR
+// @Description Open loans whose first installment has not been received and is due within the next days days, or is already due but still within the FIMR grace period, soonest first, with customer and officer contact details so collections can call before the loan becomes a first installment miss
+// @Tags FIMR
+// @Produce json
+// @Param days query int false "Days ahead to look (defaults to DASHBOARD_FIMR_UPCOMING_DAYS; 0 = due today)"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /fimr/upcoming [get]
+func (h *DashboardHandler) GetUpcomingFIMRLoans(c *gin.Context) {
+	days := h.cfg.FIMRUpcomingDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > maxFIMRUpcomingDays {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid days parameter",
+				Error:   newAPIError(models.ErrCodeValidation, fmt.Sprintf("days must be an integer between 0 and %d", maxFIMRUpcomingDays)),
+			})
+			return
+		}
+		days = parsed
+	}
+
+	filters := parseLoanFilters(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	filters["page"] = page
+	filters["limit"] = limit
+
+	loans, total, err := h.dashboardRepo.GetUpcomingFIMRLoans(filters, days)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve upcoming FIMR loans",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -300,12 +820,17 @@ func (h *DashboardHandler) GetFIMRLoans(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"loans": loans,
-			"total": len(loans),
+			"days":              days,
+			"grace_period_days": h.cfg.FIMRGracePeriodDays,
+			"loans":             loans,
+			"pagination":        newPagination(page, limit, total),
 		},
 	})
 }
 
+// maxFIMRUpcomingDays bounds the days parameter of GET /fimr/upcoming.
+const maxFIMRUpcomingDays = 30
+
 // GetFIMRSummary handles GET /api/v1/fimr/summary
 func (h *DashboardHandler) GetFIMRSummary(c *gin.Context) {
 	// Parse filters
@@ -326,10 +851,11 @@ func (h *DashboardHandler) GetFIMRSummary(c *gin.Context) {
 
 	loans, err := h.dashboardRepo.GetFIMRLoans(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve FIMR summary",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -337,15 +863,20 @@ func (h *DashboardHandler) GetFIMRSummary(c *gin.Context) {
 	// Calculate summary statistics
 	var totalAmount float64
 	var totalOutstanding float64
+	recoveredLoans := 0
 	for _, loan := range loans {
 		totalAmount += loan.LoanAmount
 		totalOutstanding += loan.OutstandingBalance
+		if loan.FIMRRecovered {
+			recoveredLoans++
+		}
 	}
 
 	summary := map[string]interface{}{
 		"total_loans":       len(loans),
 		"total_amount":      totalAmount,
 		"total_outstanding": totalOutstanding,
+		"recovered_loans":   recoveredLoans,
 		"avg_dpd":           0,
 	}
 
@@ -395,10 +926,11 @@ func (h *DashboardHandler) GetEarlyIndicatorLoans(c *gin.Context) {
 
 	loans, err := h.dashboardRepo.GetEarlyIndicatorLoans(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve early indicator loans",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -406,8 +938,8 @@ func (h *DashboardHandler) GetEarlyIndicatorLoans(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"loans": loans,
-			"total": len(loans),
+			"loans":      loans,
+			"pagination": newPagination(1, len(loans), len(loans)),
 		},
 	})
 }
@@ -430,73 +962,26 @@ func (h *DashboardHandler) GetEarlyIndicatorSummary(c *gin.Context) {
 		filters["wave"] = wave
 	}
 
-	loans, err := h.dashboardRepo.GetEarlyIndicatorLoans(filters)
+	summary, err := h.dashboardRepo.GetEarlyIndicatorSummaryMetrics(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve early indicator summary",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
 
-	// Calculate summary statistics
-	var totalAmount float64
-	var totalOutstanding float64
-	var worsening, stable, improving int
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   summary,
+	})
+}
 
-	for _, loan := range loans {
-		totalAmount += loan.LoanAmount
-		totalOutstanding += loan.OutstandingBalance
-
-		switch loan.RollDirection {
-		case "Worsening":
-			worsening++
-		case "Stable":
-			stable++
-		case "Improving":
-			improving++
-		}
-	}
-
-	summary := map[string]interface{}{
-		"total_loans":       len(loans),
-		"total_amount":      totalAmount,
-		"total_outstanding": totalOutstanding,
-		"worsening":         worsening,
-		"stable":            stable,
-		"improving":         improving,
-	}
-
-	c.JSON(http.StatusOK, models.APIResponse{
-		Status: "success",
-		Data:   summary,
-	})
-}
-
-// GetAllLoans handles GET /api/v1/loans
-// @Summary Get all loans
-// @Description Get list of all loans with filtering, sorting, and pagination
-// @Tags Loans
-// @Accept json
-// @Produce json
-// @Param officer_id query string false "Filter by officer ID"
-// @Param branch query string false "Filter by branch"
-// @Param region query string false "Filter by region"
-// @Param channel query string false "Filter by channel"
-// @Param status query string false "Filter by normalized status"
-// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
-// @Param quiet_loans query bool false "When true, only loans with 6+ days since last repayment or no repayments"
-// @Param customer_phone query string false "Filter by customer phone (partial match)"
-// @Param sort_by query string false "Sort field"
-// @Param sort_dir query string false "Sort direction (asc/desc)"
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(50)
-// @Success 200 {object} models.APIResponse
-// @Failure 500 {object} models.APIResponse
-// @Router /loans [get]
-func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
-	// Parse filters
+// parseAllLoansFilters reads the All Loans filter set (everything except
+// sorting and pagination) shared by GetAllLoans and GetLoansCount.
+func (h *DashboardHandler) parseAllLoansFilters(c *gin.Context) map[string]interface{} {
 	filters := make(map[string]interface{})
 
 	if officerID := c.Query("officer_id"); officerID != "" {
@@ -529,17 +1014,21 @@ func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
 	if verticalLeadEmail := c.Query("vertical_lead_email"); verticalLeadEmail != "" {
 		filters["vertical_lead_email"] = verticalLeadEmail
 	}
+	if verticalLeadName := c.Query("vertical_lead_name"); verticalLeadName != "" {
+		filters["vertical_lead_name"] = verticalLeadName
+	}
+	if supervisorEmail := c.Query("supervisor_email"); supervisorEmail != "" {
+		filters["supervisor_email"] = supervisorEmail
+	}
 	if loanType := c.Query("loan_type"); loanType != "" {
 		filters["loan_type"] = loanType
 	}
 	if verificationStatus := c.Query("verification_status"); verificationStatus != "" {
 		filters["verification_status"] = verificationStatus
 	}
-	// Optional period filter used primarily by Collections Control Centre.
+	// Period filter used primarily by Collections Control Centre.
 	// Supported values (for now): today, this_week, this_month, last_month.
-	if period := c.Query("period"); period != "" {
-		filters["period"] = period
-	}
+	filters["period"] = h.collectionsPeriod(c)
 	// Behavior-based filters used by All Loans UI (implemented server-side so
 	// dashboard totals and CSV exports stay consistent)
 	if behaviorLoanType := c.Query("behavior_loan_type"); behaviorLoanType != "" {
@@ -569,6 +1058,72 @@ func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
 			filters["quiet_loans"] = true
 		}
 	}
+	if reviewFlagged, err := strconv.ParseBool(c.Query("review_flagged")); err == nil && reviewFlagged {
+		filters["review_flagged"] = true
+	}
+
+	return filters
+}
+
+// GetLoansCount handles GET /api/v1/loans/count
+// @Summary Count loans matching filters
+// @Description Returns only the number of loans matching the All Loans filters, without rows or summary metrics, for instant feedback while filters are being built. Accepts the same filters as GET /loans.
+// @Tags Loans
+// @Produce json
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/count [get]
+func (h *DashboardHandler) GetLoansCount(c *gin.Context) {
+	filters := h.parseAllLoansFilters(c)
+
+	total, err := h.dashboardRepo.CountLoans(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to count loans",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"count": total,
+		},
+	})
+}
+
+// GetAllLoans handles GET /api/v1/loans
+// @Summary Get all loans
+// @Description Get list of all loans with filtering, sorting, and pagination. summary_metrics.partial is true when some summary sub-metrics could not be computed; their fields are null and they are listed in summary_metrics.unavailable_metrics
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param officer_id query string false "Filter by officer ID (supports comma-separated multi-select)"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region"
+// @Param channel query string false "Filter by channel"
+// @Param status query string false "Filter by normalized status"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Param quiet_loans query bool false "When true, only loans with 6+ days since last repayment or no repayments"
+// @Param review_flagged query bool false "When true, only loans on the manual review worklist (see /loans/review-flags)"
+// @Param customer_phone query string false "Filter by customer phone (partial match)"
+// @Param sort_by query string false "Sort field"
+// @Param sort_dir query string false "Sort direction (asc/desc)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Param cursor query string false "Keyset pagination: pass empty for the first page, then each response's next_cursor. Orders by disbursement_date then loan_id (newest first); page and sort_by are ignored/rejected"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans [get]
+func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
+	// Parse filters
+	filters := h.parseAllLoansFilters(c)
+
 	if sortBy := c.Query("sort_by"); sortBy != "" {
 		filters["sort_by"] = sortBy
 	}
@@ -591,13 +1146,20 @@ func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
 	}
 	filters["page"] = page
 	filters["limit"] = limit
+	// Keyset pagination for deep scrolling: start with an empty cursor, then
+	// pass each page's next_cursor
+	cursor, keyset := c.GetQuery("cursor")
+	if keyset {
+		filters["cursor"] = cursor
+	}
 
 	loans, total, err := h.dashboardRepo.GetAllLoans(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve loans",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -605,24 +1167,34 @@ func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
 	// Calculate summary metrics for all filtered loans (not just current page)
 	summaryMetrics, err := h.dashboardRepo.GetLoansSummaryMetrics(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to calculate summary metrics",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
 
+	data := map[string]interface{}{
+		"loans":           loans,
+		"pagination":      newPagination(page, limit, total),
+		"summary_metrics": summaryMetrics,
+	}
+	// next_cursor resumes after the last loan of this page; empty once the
+	// last page has been reached.
+	if keyset {
+		nextCursor := ""
+		if len(loans) == limit {
+			last := loans[len(loans)-1]
+			nextCursor = repository.EncodeLoanCursor(last.DisbursementDate, last.LoanID)
+		}
+		data["next_cursor"] = nextCursor
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
-		Data: map[string]interface{}{
-			"loans":           loans,
-			"total":           total,
-			"page":            page,
-			"limit":           limit,
-			"pages":           (total + limit - 1) / limit,
-			"summary_metrics": summaryMetrics,
-		},
+		Data:   data,
 	})
 }
 
@@ -632,7 +1204,7 @@ func (h *DashboardHandler) GetAllLoans(c *gin.Context) {
 // today, collection rates and a simple NPL proxy with status banding.
 //
 // @Summary Get branch collections leaderboard
-// @Description Get per-branch collections metrics for the Branch Leaderboard table
+// @Description Get per-branch collections metrics for the Branch Leaderboard table. summary.no_data is true (and summary.collection_rate_today null) when no branch matches the filters.
 // @Tags Collections
 // @Accept json
 // @Produce json
@@ -669,10 +1241,11 @@ func (h *DashboardHandler) GetBranchCollectionsLeaderboard(c *gin.Context) {
 
 	branches, err := h.dashboardRepo.GetBranchCollectionsLeaderboard(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve branch collections leaderboard",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -686,33 +1259,44 @@ func (h *DashboardHandler) GetBranchCollectionsLeaderboard(c *gin.Context) {
 		totalMissedToday += b.MissedToday
 	}
 
-	var collectionRate float64
-	if totalDueToday > 0 {
-		collectionRate = totalCollectedToday / totalDueToday
-	}
-
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"branches": branches,
-			"summary": map[string]interface{}{
-				"total_branches":        len(branches),
-				"total_portfolio":       totalPortfolio,
-				"total_due_today":       totalDueToday,
-				"total_collected_today": totalCollectedToday,
-				"total_missed_today":    totalMissedToday,
-				"collection_rate_today": collectionRate,
-			},
+			"branches":   branches,
+			"pagination": newPagination(1, len(branches), len(branches)),
+			"summary": h.collectionsLeaderboardSummary("total_branches", len(branches),
+				totalPortfolio, totalDueToday, totalCollectedToday, totalMissedToday),
 		},
 	})
 }
 
+// collectionsLeaderboardSummary builds the summary card of a collections
+// leaderboard with rows entries, counted under countKey. When the filters
+// matched nothing, no_data is set and collection_rate_today is null rather
+// than a 0 that reads as "nothing collected".
+func (h *DashboardHandler) collectionsLeaderboardSummary(countKey string, rows int, portfolio, dueToday, collectedToday, missedToday float64) map[string]interface{} {
+	var collectionRate interface{}
+	if rows > 0 {
+		collectionRate = services.Round(services.SafeDivide(collectedToday, dueToday), h.cfg.RatioPrecision)
+	}
+
+	return map[string]interface{}{
+		countKey:                rows,
+		"no_data":               rows == 0,
+		"total_portfolio":       portfolio,
+		"total_due_today":       dueToday,
+		"total_collected_today": collectedToday,
+		"total_missed_today":    missedToday,
+		"collection_rate_today": collectionRate,
+	}
+}
+
 // GetOfficerCollectionsLeaderboard handles GET /api/v1/collections/officers
 // It provides per-officer collections metrics (portfolio, due today, collections
 // today, collection rates and NPL proxy) for Agent/Officer Leaderboard views.
 //
 // @Summary Get officer collections leaderboard
-// @Description Get per-officer collections metrics for the Agent Leaderboard table
+// @Description Get per-officer collections metrics for the Agent Leaderboard table. summary.no_data is true (and summary.collection_rate_today null) when no officer matches the filters.
 // @Tags Collections
 // @Accept json
 // @Produce json
@@ -721,7 +1305,11 @@ func (h *DashboardHandler) GetBranchCollectionsLeaderboard(c *gin.Context) {
 // @Param channel query string false "Filter by channel"
 // @Param wave query string false "Filter by wave"
 // @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Param rank_by query string false "Metric to rank by: collected_today, due_today, today_rate, mtd_rate, progress_rate, portfolio_total, missed_today, overdue_15d or npl_ratio" default(collected_today)
+// @Param min_loans query int false "Leave out officers with fewer loans (defaults to DASHBOARD_LEADERBOARD_MIN_LOANS)"
+// @Param min_portfolio query number false "Leave out officers with a smaller portfolio_total (defaults to DASHBOARD_LEADERBOARD_MIN_PORTFOLIO)"
 // @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /collections/officers [get]
 func (h *DashboardHandler) GetOfficerCollectionsLeaderboard(c *gin.Context) {
@@ -745,13 +1333,45 @@ func (h *DashboardHandler) GetOfficerCollectionsLeaderboard(c *gin.Context) {
 	if djangoStatus := c.Query("django_status"); djangoStatus != "" {
 		filters["django_status"] = djangoStatus
 	}
+	rankBy := c.DefaultQuery("rank_by", repository.DefaultLeaderboardRankBy)
+	filters["rank_by"] = rankBy
+
+	minLoans := h.cfg.LeaderboardMinLoans
+	if minLoansStr := c.Query("min_loans"); minLoansStr != "" {
+		parsed, err := strconv.Atoi(minLoansStr)
+		if err != nil || parsed < 0 {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid min_loans",
+				Error:   newAPIError(models.ErrCodeValidation, "min_loans must be a non-negative integer"),
+			})
+			return
+		}
+		minLoans = parsed
+	}
+	minPortfolio := h.cfg.LeaderboardMinPortfolio
+	if minPortfolioStr := c.Query("min_portfolio"); minPortfolioStr != "" {
+		parsed, err := strconv.ParseFloat(minPortfolioStr, 64)
+		if err != nil || parsed < 0 {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid min_portfolio",
+				Error:   newAPIError(models.ErrCodeValidation, "min_portfolio must be a non-negative number"),
+			})
+			return
+		}
+		minPortfolio = parsed
+	}
+	filters["min_loans"] = minLoans
+	filters["min_portfolio"] = minPortfolio
 
 	officers, err := h.dashboardRepo.GetOfficerCollectionsLeaderboard(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve officer collections leaderboard",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -764,23 +1384,16 @@ func (h *DashboardHandler) GetOfficerCollectionsLeaderboard(c *gin.Context) {
 		totalMissedToday += o.MissedToday
 	}
 
-	var collectionRate float64
-	if totalDueToday > 0 {
-		collectionRate = totalCollectedToday / totalDueToday
-	}
-
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"officers": officers,
-			"summary": map[string]interface{}{
-				"total_officers":        len(officers),
-				"total_portfolio":       totalPortfolio,
-				"total_due_today":       totalDueToday,
-				"total_collected_today": totalCollectedToday,
-				"total_missed_today":    totalMissedToday,
-				"collection_rate_today": collectionRate,
-			},
+			"officers":      officers,
+			"rank_by":       rankBy,
+			"min_loans":     minLoans,
+			"min_portfolio": minPortfolio,
+			"pagination":    newPagination(1, len(officers), len(officers)),
+			"summary": h.collectionsLeaderboardSummary("total_officers", len(officers),
+				totalPortfolio, totalDueToday, totalCollectedToday, totalMissedToday),
 		},
 	})
 }
@@ -789,8 +1402,8 @@ func (h *DashboardHandler) GetOfficerCollectionsLeaderboard(c *gin.Context) {
 // It returns per-officer Wave 2 repayment performance for the Repayment Watch
 // view in the Collections Control Centre.
 //
-// The endpoint focuses on Wave 2 loans that are currently OPEN or PAST_MATURITY
-// and counts non-reversed repayments made today. It respects the same
+// The endpoint focuses on Wave 2 loans that are currently open (see
+// DASHBOARD_OPEN_DJANGO_STATUSES) and counts non-reversed repayments made today. It respects the same
 // branch/region/channel/wave/loan_type filters used elsewhere in Collections.
 //
 // @Summary Get Repayment Watch metrics per officer
@@ -827,10 +1440,11 @@ func (h *DashboardHandler) GetRepaymentWatch(c *gin.Context) {
 
 	officers, err := h.dashboardRepo.GetRepaymentWatchOfficers(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve Repayment Watch metrics",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -838,7 +1452,8 @@ func (h *DashboardHandler) GetRepaymentWatch(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"officers": officers,
+			"officers":   officers,
+			"pagination": newPagination(1, len(officers), len(officers)),
 		},
 	})
 }
@@ -866,7 +1481,7 @@ func (h *DashboardHandler) GetRepaymentWatch(c *gin.Context) {
 func (h *DashboardHandler) GetAgentActivityDetail(c *gin.Context) {
 	category := c.Query("category")
 	if category == "" {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status:  "error",
 			Message: "Missing required category parameter",
 			Error:   newAPIError("BAD_REQUEST", "category query parameter is required"),
@@ -893,18 +1508,12 @@ func (h *DashboardHandler) GetAgentActivityDetail(c *gin.Context) {
 
 	rows, err := h.dashboardRepo.GetAgentActivityDetail(filters, category)
 	if err != nil {
-		// Distinguish between bad category and internal errors by error type/message.
-		statusCode := http.StatusInternalServerError
-		apiErrCode := "INTERNAL_ERROR"
-		if strings.Contains(err.Error(), "unknown agent activity category") {
-			statusCode = http.StatusBadRequest
-			apiErrCode = "BAD_REQUEST"
-		}
-
-		c.JSON(statusCode, models.APIResponse{
+		// An unknown category maps to INVALID_FILTER / 400
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve Agent Activity detail",
-			Error:   newAPIError(apiErrCode, err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -912,7 +1521,8 @@ func (h *DashboardHandler) GetAgentActivityDetail(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"officers": rows,
+			"officers":   rows,
+			"pagination": newPagination(1, len(rows), len(rows)),
 		},
 	})
 }
@@ -955,10 +1565,11 @@ func (h *DashboardHandler) GetAgentActivity(c *gin.Context) {
 
 	summary, err := h.dashboardRepo.GetAgentActivitySummary(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve Agent Activity metrics",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -969,16 +1580,15 @@ func (h *DashboardHandler) GetAgentActivity(c *gin.Context) {
 	})
 }
 
-// GetDailyCollections handles GET /api/v1/collections/daily
-// It returns a per-day time series of collections amounts suitable for the
-// Collections Control Centre daily chart.
+// GetAgentActivityStartedToday handles GET /api/v1/collections/agent-activity/started-today
+// It returns only the started_today Agent Activity count, for badges that
+// don't need the full 7-day category summary.
 //
-// @Summary Get daily collections time series
-// @Description Get per-day collections amounts for the selected period and filters
+// @Summary Get count of agents who started collecting today
+// @Description Get the number of officers with at least one collection today. Same population and definition as started_today_count in GET /collections/agent-activity, without the 7-day categories.
 // @Tags Collections
 // @Accept json
 // @Produce json
-// @Param period query string false "Period (today, this_week, this_month, last_month)"
 // @Param branch query string false "Filter by branch"
 // @Param region query string false "Filter by region (supports comma-separated multi-select)"
 // @Param channel query string false "Filter by channel"
@@ -986,22 +1596,16 @@ func (h *DashboardHandler) GetAgentActivity(c *gin.Context) {
 // @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
 // @Success 200 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
-// @Router /collections/daily [get]
-func (h *DashboardHandler) GetDailyCollections(c *gin.Context) {
+// @Router /collections/agent-activity/started-today [get]
+func (h *DashboardHandler) GetAgentActivityStartedToday(c *gin.Context) {
 	filters := make(map[string]interface{})
 
-	if period := c.Query("period"); period != "" {
-		filters["period"] = period
-	}
 	if branch := c.Query("branch"); branch != "" {
 		filters["branch"] = branch
 	}
 	if region := c.Query("region"); region != "" {
 		filters["region"] = region
 	}
-	if officerID := c.Query("officer_id"); officerID != "" {
-		filters["officer_id"] = officerID
-	}
 	if channel := c.Query("channel"); channel != "" {
 		filters["channel"] = channel
 	}
@@ -1012,12 +1616,13 @@ func (h *DashboardHandler) GetDailyCollections(c *gin.Context) {
 		filters["loan_type"] = loanType
 	}
 
-	points, err := h.dashboardRepo.GetDailyCollections(filters)
+	count, err := h.dashboardRepo.GetStartedTodayCount(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve daily collections",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to retrieve started today count",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1025,129 +1630,124 @@ func (h *DashboardHandler) GetDailyCollections(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"points": points,
+			"started_today_count": count,
 		},
 	})
 }
 
-// GetBranches handles GET /api/v1/branches
-// @Summary Get all branches
-// @Description Get list of branches with their portfolio metrics and PAR15 ratios
-// @Tags Branches
+// collectionsPeriod returns the request's period param, falling back to the
+// configured CollectionsDefaultPeriod (itself "today" when unset).
+func (h *DashboardHandler) collectionsPeriod(c *gin.Context) string {
+	if period := c.Query("period"); period != "" {
+		return period
+	}
+	if h.cfg.CollectionsDefaultPeriod != "" {
+		return h.cfg.CollectionsDefaultPeriod
+	}
+	return "today"
+}
+
+// GetDailyCollections handles GET /api/v1/collections/daily
+// It returns a per-day time series of collections amounts suitable for the
+// Collections Control Centre daily chart.
+//
+// @Summary Get daily collections time series
+// @Description Get per-day collected, due and missed amounts for the selected period and filters, with the repayment count and the distinct loans and customers it came from. include_reversed=true makes collected amounts gross of later reversals and adds reversed_amount and reversed_count per day; missed amounts stay net.
+// @Tags Collections
 // @Accept json
 // @Produce json
+// @Param period query string false "Period (today, this_week, this_month, last_month, last_7_days); defaults to DASHBOARD_COLLECTIONS_DEFAULT_PERIOD"
+// @Param include_reversed query bool false "Include reversed repayments (gross collections)" default(false)
 // @Param branch query string false "Filter by branch"
-// @Param region query string false "Filter by region"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
 // @Param channel query string false "Filter by channel"
-// @Param user_type query string false "Filter by user type"
 // @Param wave query string false "Filter by wave"
-// @Param sort_by query string false "Sort field"
-// @Param sort_dir query string false "Sort direction (asc/desc)"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
 // @Success 200 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
-// @Router /branches [get]
-func (h *DashboardHandler) GetBranches(c *gin.Context) {
-	// Parse filters
+// @Router /collections/daily [get]
+func (h *DashboardHandler) GetDailyCollections(c *gin.Context) {
 	filters := make(map[string]interface{})
 
+	filters["period"] = h.collectionsPeriod(c)
 	if branch := c.Query("branch"); branch != "" {
 		filters["branch"] = branch
 	}
 	if region := c.Query("region"); region != "" {
 		filters["region"] = region
 	}
+	if officerID := c.Query("officer_id"); officerID != "" {
+		filters["officer_id"] = officerID
+	}
 	if channel := c.Query("channel"); channel != "" {
 		filters["channel"] = channel
 	}
-	if userType := c.Query("user_type"); userType != "" {
-		filters["user_type"] = userType
-	}
 	if wave := c.Query("wave"); wave != "" {
 		filters["wave"] = wave
 	}
-	if sortBy := c.Query("sort_by"); sortBy != "" {
-		filters["sort_by"] = sortBy
+	if loanType := c.Query("loan_type"); loanType != "" {
+		filters["loan_type"] = loanType
 	}
-	if sortDir := c.Query("sort_dir"); sortDir != "" {
-		filters["sort_dir"] = sortDir
+	if includeReversed, err := strconv.ParseBool(c.Query("include_reversed")); err == nil {
+		filters["include_reversed"] = includeReversed
 	}
 
-	branches, err := h.dashboardRepo.GetBranches(filters)
+	points, err := h.dashboardRepo.GetDailyCollections(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve branches",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to retrieve daily collections",
+			Error:   apiErr,
 		})
 		return
 	}
 
-	// Calculate summary
-	var totalPortfolio, totalOverdue15d float64
-	for _, branch := range branches {
-		totalPortfolio += branch.PortfolioTotal
-		totalOverdue15d += branch.Overdue15d
-	}
-
-	avgPar15 := 0.0
-	if totalPortfolio > 0 {
-		avgPar15 = totalOverdue15d / totalPortfolio
-	}
-
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"branches": branches,
-			"summary": map[string]interface{}{
-				"total_branches":    len(branches),
-				"total_portfolio":   totalPortfolio,
-				"total_overdue_15d": totalOverdue15d,
-				"avg_par15_ratio":   avgPar15,
-			},
+			"period": filters["period"],
+			"points": points,
 		},
 	})
 }
 
-// GetVerticalLeadMetrics handles GET /api/v1/vertical-leads/metrics
-// @Summary Get aggregated vertical lead metrics
-// @Description Get aggregated loan metrics grouped by vertical lead name for the Credit Health by Branch "By Vertical Lead" view
-// @Tags VerticalLeads
+// GetCollectionHeatmap handles GET /api/v1/collections/heatmap
+// @Summary Get day-of-week x week collections heatmap
+// @Description Get collected amounts per day over the last N ISO weeks, tagged with week start and ISO day of week. Weekends are always present. The current week is included up to today only; its cells have partial_week=true.
+// @Tags Collections
 // @Accept json
 // @Produce json
+// @Param weeks query int false "Number of weeks including the current one (max 52)" default(8)
 // @Param branch query string false "Filter by branch"
-// @Param region query string false "Filter by region"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID (supports comma-separated multi-select)"
 // @Param channel query string false "Filter by channel"
-// @Param user_type query string false "Filter by user type"
 // @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
 // @Success 200 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
-// @Router /vertical-leads/metrics [get]
-func (h *DashboardHandler) GetVerticalLeadMetrics(c *gin.Context) {
-	filters := make(map[string]interface{})
-
-	if branch := c.Query("branch"); branch != "" {
-		filters["branch"] = branch
-	}
-	if region := c.Query("region"); region != "" {
-		filters["region"] = region
-	}
-	if channel := c.Query("channel"); channel != "" {
-		filters["channel"] = channel
-	}
-	if userType := c.Query("user_type"); userType != "" {
-		filters["user_type"] = userType
+// @Router /collections/heatmap [get]
+func (h *DashboardHandler) GetCollectionHeatmap(c *gin.Context) {
+	filters := parseLoanFilters(c)
+
+	weeks := 8
+	if weeksStr := c.Query("weeks"); weeksStr != "" {
+		if w, err := strconv.Atoi(weeksStr); err == nil && w > 0 {
+			weeks = w
+		}
 	}
-	if wave := c.Query("wave"); wave != "" {
-		filters["wave"] = wave
+	if weeks > 52 {
+		weeks = 52
 	}
 
-	metrics, err := h.dashboardRepo.GetVerticalLeadMetrics(filters)
+	cells, err := h.dashboardRepo.GetCollectionHeatmap(filters, weeks)
 	if err != nil {
-		log.Printf("failed to retrieve vertical lead metrics: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve vertical lead metrics",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to retrieve collection heatmap",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1155,28 +1755,40 @@ func (h *DashboardHandler) GetVerticalLeadMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"vertical_leads": metrics,
+			"weeks": weeks,
+			"cells": cells,
 		},
 	})
 }
 
-// GetVerticalLeadsList handles GET /api/v1/vertical-leads/list
-// @Summary Get list of vertical leads
-// @Description Get distinct vertical lead names from loans, including unassigned loans
-// @Tags VerticalLeads
+// GetCollectionsByChannel handles GET /api/v1/collections/by-channel
+// @Summary Get collections broken down by loan channel
+// @Description Get the collected amount, repayment count and share of collections for the period grouped by each loan's origination channel (e.g. AGENT vs MERCHANT). Loans without a channel are grouped under __MISSING__.
+// @Tags Collections
 // @Accept json
 // @Produce json
+// @Param period query string false "today, yesterday, this_week, last_week, this_month, last_month, last_7_days or YYYY-MM-DD..YYYY-MM-DD; defaults to DASHBOARD_COLLECTIONS_DEFAULT_PERIOD"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
 // @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
-// @Router /vertical-leads/list [get]
-func (h *DashboardHandler) GetVerticalLeadsList(c *gin.Context) {
-	verticalLeads, err := h.dashboardRepo.GetVerticalLeadNames()
+// @Router /collections/by-channel [get]
+func (h *DashboardHandler) GetCollectionsByChannel(c *gin.Context) {
+	filters := parseLoanFilters(c)
+	period := h.collectionsPeriod(c)
+
+	channels, err := h.dashboardRepo.GetCollectionsByChannel(filters, period)
 	if err != nil {
-		log.Printf("failed to retrieve vertical leads: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve vertical leads",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to retrieve collections by channel",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1184,30 +1796,40 @@ func (h *DashboardHandler) GetVerticalLeadsList(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"vertical_leads": verticalLeads,
+			"period":   period,
+			"channels": channels,
 		},
 	})
 }
 
-// GetFilterOptions handles GET /api/v1/filters/:type
-func (h *DashboardHandler) GetFilterOptions(c *gin.Context) {
-	filterType := c.Param("type")
-
-	// Parse additional filters
-	filters := make(map[string]interface{})
-	if region := c.Query("region"); region != "" {
-		filters["region"] = region
-	}
-	if branch := c.Query("branch"); branch != "" {
-		filters["branch"] = branch
-	}
+// GetCollectionsByUserType handles GET /api/v1/collections/by-user-type
+// @Summary Get today's collections performance by officer user type
+// @Description Get portfolio, due today, collected today, today's collection rate and the PAR15 NPL proxy grouped by the booking officer's user_type (AGENT, MERCHANT, AJO_AGENT, ...). Only dashboard user types are included; officers without a user_type are grouped under __MISSING__ when DASHBOARD_INCLUDE_NULL_USER_TYPE is set.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Param django_status query string false "Filter by django status (supports comma-separated multi-select)"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /collections/by-user-type [get]
+func (h *DashboardHandler) GetCollectionsByUserType(c *gin.Context) {
+	filters := parseLoanFilters(c)
 
-	options, err := h.dashboardRepo.GetFilterOptions(filterType, filters)
+	userTypes, err := h.dashboardRepo.GetCollectionsByUserType(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve filter options",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to retrieve collections by user type",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1215,19 +1837,149 @@ func (h *DashboardHandler) GetFilterOptions(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			filterType: options,
+			"user_types": userTypes,
 		},
 	})
 }
 
-// GetTeamMembers handles GET /api/v1/team-members
-func (h *DashboardHandler) GetTeamMembers(c *gin.Context) {
-	members, err := h.dashboardRepo.GetTeamMembers()
+// GetCollectionsProgress handles GET /api/v1/collections/progress
+// @Summary Get collections progress against expected for a period
+// @Description Get total collected vs total expected across the filtered loans for the period. Expected is each loan's daily repayment times the business days (Mon-Fri, excluding holidays) it was due within the period, bounded by first_payment_due_date, maturity_date and closed_date.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param period query string false "today, yesterday, this_week, last_week, this_month, last_month, last_7_days or YYYY-MM-DD..YYYY-MM-DD; defaults to DASHBOARD_COLLECTIONS_DEFAULT_PERIOD"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse "Unknown period"
+// @Failure 500 {object} models.APIResponse
+// @Router /collections/progress [get]
+func (h *DashboardHandler) GetCollectionsProgress(c *gin.Context) {
+	filters := parseLoanFilters(c)
+	period := h.collectionsPeriod(c)
+
+	progress, err := h.dashboardRepo.GetCollectionsProgress(filters, period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve team members",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to retrieve collections progress",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   progress,
+	})
+}
+
+// ComparePeriods handles GET /api/v1/metrics/compare
+// @Summary Compare two periods side by side
+// @Description Get collections, disbursements and disbursement-cohort PAR15 for two periods, plus the change from period_b to period_a. A period is a name (today, yesterday, this_week, last_week, this_month, last_month, last_7_days) or an explicit YYYY-MM-DD..YYYY-MM-DD range.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param period_a query string true "Period being compared, e.g. this_week"
+// @Param period_b query string true "Baseline period, e.g. last_week"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /metrics/compare [get]
+func (h *DashboardHandler) ComparePeriods(c *gin.Context) {
+	periodA := c.Query("period_a")
+	periodB := c.Query("period_b")
+	if periodA == "" || periodB == "" {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "period_a and period_b are required",
+			Error:   newAPIError(models.ErrCodeValidation, "period_a and period_b are required"),
+		})
+		return
+	}
+
+	filters := parseLoanFilters(c)
+
+	a, err := h.dashboardRepo.GetPeriodAggregates(filters, periodA)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve period_a aggregates",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	b, err := h.dashboardRepo.GetPeriodAggregates(filters, periodB)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve period_b aggregates",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: &models.PeriodComparison{
+			PeriodA: a,
+			PeriodB: b,
+			Deltas: map[string]models.MetricDelta{
+				"collected_amount": metricDelta(a.CollectedAmount, b.CollectedAmount),
+				"repayments_count": metricDelta(float64(a.RepaymentsCount), float64(b.RepaymentsCount)),
+				"disbursed_amount": metricDelta(a.DisbursedAmount, b.DisbursedAmount),
+				"disbursed_count":  metricDelta(float64(a.DisbursedCount), float64(b.DisbursedCount)),
+				"par15_ratio":      metricDelta(a.PAR15Ratio, b.PAR15Ratio),
+			},
+		},
+	})
+}
+
+// GetLoanTermDistribution handles GET /api/v1/metrics/term-distribution
+// @Summary Get the loan tenor distribution
+// @Description Get loan count, principal outstanding, overdue 15+ days and PAR15 per loan_term_days bucket. Buckets are mutually exclusive and set by DASHBOARD_LOAN_TERM_BUCKET_EDGES (default <=30d, 31-90d, 91d+); loans with no term, or a term of zero or less, are reported under __MISSING__. Only open loans (open django_status) are counted unless include_closed is set.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Param include_closed query bool false "Include closed loans" default(false)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /metrics/term-distribution [get]
+func (h *DashboardHandler) GetLoanTermDistribution(c *gin.Context) {
+	filters := parseLoanFilters(c)
+	if includeClosed, err := strconv.ParseBool(c.Query("include_closed")); err == nil {
+		filters["include_closed"] = includeClosed
+	}
+
+	buckets, err := h.dashboardRepo.GetLoanTermDistribution(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve loan term distribution",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1235,97 +1987,1294 @@ func (h *DashboardHandler) GetTeamMembers(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"team_members": members,
+			"buckets": buckets,
 		},
 	})
 }
 
-// UpdateOfficerAudit handles PUT /api/v1/officers/:officer_id/audit
-func (h *DashboardHandler) UpdateOfficerAudit(c *gin.Context) {
-	officerID := c.Param("officer_id")
+// maxDelayHistogramBins caps the bins parameter of GET /metrics/delay-histogram.
+const maxDelayHistogramBins = 100
 
-	var update models.AuditUpdate
-	if err := c.ShouldBindJSON(&update); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+// GetRepaymentDelayHistogram handles GET /api/v1/metrics/delay-histogram
+// @Summary Get the repayment delay rate histogram
+// @Description Get the number of loans per repayment_delay_rate bin, splitting 0-100 into equal-width bins (default 10: 0-10, 10-20, ..., 90-100). Shows the shape behind the excellent/okay/critical bands of the summary. Loans with no delay rate are left out; rates below 0 or above 100 are counted in below_range_count and above_range_count.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param bins query int false "Number of equal-width bins (max 100)" default(10)
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Success 200 {object} models.APIResponse{data=models.RepaymentDelayHistogram}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /metrics/delay-histogram [get]
+func (h *DashboardHandler) GetRepaymentDelayHistogram(c *gin.Context) {
+	bins, err := strconv.Atoi(c.DefaultQuery("bins", "10"))
+	if err != nil || bins < 1 || bins > maxDelayHistogramBins {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("bins must be an integer between 1 and %d", maxDelayHistogramBins),
+			Error:   newAPIError(models.ErrCodeValidation, "invalid bins"),
+		})
+		return
+	}
+
+	filters := parseLoanFilters(c)
+
+	histogram, err := h.dashboardRepo.GetRepaymentDelayHistogram(filters, bins)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve repayment delay histogram",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   histogram,
+	})
+}
+
+// GetPortfolioConcentration handles GET /api/v1/metrics/concentration
+// @Summary Get portfolio concentration
+// @Description Get the top N officers or branches by actual outstanding, their combined share of the filtered portfolio, and the Herfindahl-Hirschman index (sum of squared shares, 0-1) across all of them
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param by query string false "officer or branch" default(officer)
+// @Param top query int false "Number of entities to return" default(10)
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /metrics/concentration [get]
+func (h *DashboardHandler) GetPortfolioConcentration(c *gin.Context) {
+	filters := parseLoanFilters(c)
+	by := c.DefaultQuery("by", "officer")
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid top",
+			Error:   newAPIError(models.ErrCodeValidation, "top must be an integer"),
+		})
+		return
+	}
+
+	concentration, err := h.dashboardRepo.GetPortfolioConcentration(filters, by, top)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve portfolio concentration",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   concentration,
+	})
+}
+
+// GetMetricsRollup handles GET /api/v1/metrics/rollup
+// @Summary Get portfolio metrics grouped by a dimension
+// @Description Get active loans, portfolio total, overdue 15+ days, PAR15 and today's collection rate for each value of the chosen dimension across the filtered open loans (open django_status); collections cover today's repayments on any of the group's loans. Loans without a value are grouped under __MISSING__.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param dimension query string true "branch, region, channel, wave, vertical_lead or officer"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param wave query string false "Filter by wave"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Success 200 {object} models.APIResponse{data=models.MetricsRollup}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /metrics/rollup [get]
+func (h *DashboardHandler) GetMetricsRollup(c *gin.Context) {
+	dimension := c.Query("dimension")
+	if dimension == "" {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Missing dimension",
+			Error:   newAPIError(models.ErrCodeValidation, "dimension is required (one of "+strings.Join(repository.RollupDimensions, ", ")+")"),
+		})
+		return
+	}
+
+	rollup, err := h.dashboardRepo.GetMetricsRollup(parseLoanFilters(c), dimension)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve metrics rollup",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   rollup,
+	})
+}
+
+// GetNonBusinessDayRepayments handles GET /api/v1/repayments/non-business-days
+// @Summary List repayments recorded on non-business days
+// @Description Audit list of non-reversed repayments dated on a Saturday or Sunday (and, with include_holidays, on holidays from GET /holidays), which can indicate manual backdating or reconciliation entries. include_reversed=true also lists reversed repayments, flagged by is_reversed. Newest first.
+// @Tags Repayments
+// @Accept json
+// @Produce json
+// @Param period query string false "today, yesterday, this_week, last_week, this_month, last_month, last_7_days or YYYY-MM-DD..YYYY-MM-DD; defaults to DASHBOARD_COLLECTIONS_DEFAULT_PERIOD"
+// @Param include_holidays query bool false "Also include repayments on company-wide holidays" default(false)
+// @Param include_reversed query bool false "Also list reversed repayments" default(false)
+// @Param officer_id query string false "Filter by officer ID"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /repayments/non-business-days [get]
+func (h *DashboardHandler) GetNonBusinessDayRepayments(c *gin.Context) {
+	filters := parseLoanFilters(c)
+	period := h.collectionsPeriod(c)
+	includeHolidays := c.Query("include_holidays") == "true"
+	if includeReversed, err := strconv.ParseBool(c.Query("include_reversed")); err == nil {
+		filters["include_reversed"] = includeReversed
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	filters["page"] = page
+	filters["limit"] = limit
+
+	repayments, total, err := h.dashboardRepo.GetNonBusinessDayRepayments(filters, period, includeHolidays)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve non-business-day repayments",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"period":           period,
+			"include_holidays": includeHolidays,
+			"repayments":       repayments,
+			"pagination":       newPagination(page, limit, total),
+		},
+	})
+}
+
+// CreateHoliday handles POST /api/v1/holidays
+// @Summary Add or rename a holiday
+// @Description Adds a company-wide holiday to the Django-synced holiday calendar, or renames the one previously added through this endpoint on that date. Weekday holidays are excluded from the business days behind the agent activity repayment rate and collections progress. Added holidays survive the holiday sync.
+// @Tags Holidays
+// @Accept json
+// @Produce json
+// @Param holiday body models.HolidayInput true "Holiday date (YYYY-MM-DD) and name"
+// @Success 200 {object} models.APIResponse{data=models.Holiday}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /holidays [post]
+func (h *DashboardHandler) CreateHoliday(c *gin.Context) {
+	var input models.HolidayInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status:  "error",
 			Message: "Invalid request body",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Error:   newAPIError(models.ErrCodeValidation, err.Error()),
 		})
 		return
 	}
 
-	err := h.dashboardRepo.UpdateOfficerAudit(officerID, &update)
+	holiday, err := h.dashboardRepo.UpsertHoliday(&input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to update audit assignment",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to save holiday",
+			Error:   apiErr,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
-		Message: "Audit assignment updated successfully",
+		Message: "Holiday saved successfully",
+		Data:    holiday,
+	})
+}
+
+// GetHolidays handles GET /api/v1/holidays
+// @Summary List holidays
+// @Description Lists the company-wide holidays excluded from collection business-day counts, in date order. source is django for holidays synced from Django and api for those added through POST /holidays.
+// @Tags Holidays
+// @Accept json
+// @Produce json
+// @Param year query int false "Only holidays in this calendar year"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /holidays [get]
+func (h *DashboardHandler) GetHolidays(c *gin.Context) {
+	year := 0
+	if yearStr := c.Query("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed <= 0 {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid year",
+				Error:   newAPIError(models.ErrCodeValidation, "year must be a positive integer"),
+			})
+			return
+		}
+		year = parsed
+	}
+
+	holidays, err := h.dashboardRepo.GetHolidays(year)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve holidays",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
 		Data: map[string]interface{}{
-			"officer_id":    officerID,
-			"assignee_id":   update.AssigneeID,
-			"assignee_name": update.AssigneeName,
-			"audit_status":  update.AuditStatus,
+			"holidays":   holidays,
+			"pagination": newPagination(1, len(holidays), len(holidays)),
 		},
 	})
 }
 
-// GetOfficerAuditHistory handles GET /api/v1/officers/:officer_id/audit-history
-func (h *DashboardHandler) GetOfficerAuditHistory(c *gin.Context) {
-	officerID := c.Param("officer_id")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+// DeleteHoliday handles DELETE /api/v1/holidays/:date
+// @Summary Delete a holiday
+// @Description Removes a holiday added through POST /holidays so the date counts as a business day again. Holidays synced from Django must be removed in Django.
+// @Tags Holidays
+// @Accept json
+// @Produce json
+// @Param date path string true "Holiday date (YYYY-MM-DD)"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /holidays/{date} [delete]
+func (h *DashboardHandler) DeleteHoliday(c *gin.Context) {
+	date := c.Param("date")
+
+	if err := h.dashboardRepo.DeleteHoliday(date); err != nil {
+		statusCode, apiErr := classifyError(err)
+		message := "Failed to delete holiday"
+		if statusCode == http.StatusNotFound {
+			message = "Holiday not found"
+		}
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: message,
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Message: "Holiday deleted successfully",
+		Data: map[string]interface{}{
+			"date": date,
+		},
+	})
+}
+
+// metricDelta returns the change from b to a, with no percentage change when
+// b is zero.
+func metricDelta(a, b float64) models.MetricDelta {
+	delta := models.MetricDelta{Delta: a - b}
+	if b != 0 {
+		pct := (a - b) / b * 100
+		delta.PctChange = &pct
+	}
+	return delta
+}
+
+// GetBranches handles GET /api/v1/branches
+// @Summary Get all branches
+// @Description Get list of branches with their portfolio metrics and PAR15 ratios
+// @Tags Branches
+// @Accept json
+// @Produce json
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param wave query string false "Filter by wave"
+// @Param sort_by query string false "Sort field"
+// @Param sort_dir query string false "Sort direction (asc/desc)"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /branches [get]
+func (h *DashboardHandler) GetBranches(c *gin.Context) {
+	// Parse filters
+	filters := make(map[string]interface{})
+
+	if branch := c.Query("branch"); branch != "" {
+		filters["branch"] = branch
+	}
+	if region := c.Query("region"); region != "" {
+		filters["region"] = region
+	}
+	if channel := c.Query("channel"); channel != "" {
+		filters["channel"] = channel
+	}
+	if userType := c.Query("user_type"); userType != "" {
+		filters["user_type"] = userType
+	}
+	if wave := c.Query("wave"); wave != "" {
+		filters["wave"] = wave
+	}
+	if sortBy := c.Query("sort_by"); sortBy != "" {
+		filters["sort_by"] = sortBy
+	}
+	if sortDir := c.Query("sort_dir"); sortDir != "" {
+		filters["sort_dir"] = sortDir
+	}
+
+	branches, err := h.dashboardRepo.GetBranches(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve branches",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	// Calculate summary
+	var totalPortfolio, totalOverdue15d float64
+	for _, branch := range branches {
+		totalPortfolio += branch.PortfolioTotal
+		totalOverdue15d += branch.Overdue15d
+	}
+
+	avgPar15 := services.SafeDivide(totalOverdue15d, totalPortfolio)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"branches":   branches,
+			"pagination": newPagination(1, len(branches), len(branches)),
+			"summary": map[string]interface{}{
+				"total_branches":    len(branches),
+				"total_portfolio":   totalPortfolio,
+				"total_overdue_15d": totalOverdue15d,
+				"avg_par15_ratio":   avgPar15,
+			},
+		},
+	})
+}
+
+// GetBranchDPDMatrix handles GET /api/v1/branches/dpd-matrix
+// @Summary Get branch x DPD bucket aging matrix
+// @Description Get, for each branch, the loan count and outstanding balance in each DPD bucket, plus a grand total row. Loans with no current_dpd are counted as current, so the buckets always add up to the total
+// @Tags Branches
+// @Accept json
+// @Produce json
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param wave query string false "Filter by wave"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /branches/dpd-matrix [get]
+func (h *DashboardHandler) GetBranchDPDMatrix(c *gin.Context) {
+	filters := make(map[string]interface{})
+
+	if branch := c.Query("branch"); branch != "" {
+		filters["branch"] = branch
+	}
+	if region := c.Query("region"); region != "" {
+		filters["region"] = region
+	}
+	if officerID := c.Query("officer_id"); officerID != "" {
+		filters["officer_id"] = officerID
+	}
+	if channel := c.Query("channel"); channel != "" {
+		filters["channel"] = channel
+	}
+	if userType := c.Query("user_type"); userType != "" {
+		filters["user_type"] = userType
+	}
+	if wave := c.Query("wave"); wave != "" {
+		filters["wave"] = wave
+	}
+
+	branches, err := h.dashboardRepo.GetBranchDPDMatrix(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve branch DPD matrix",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	totals := &models.BranchDPDMatrixRow{Branch: "Total"}
+	for _, branch := range branches {
+		totals.Add(branch)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"branches":   branches,
+			"totals":     totals,
+			"pagination": newPagination(1, len(branches), len(branches)),
+		},
+	})
+}
+
+// GetVerticalLeadMetrics handles GET /api/v1/vertical-leads/metrics
+// @Summary Get aggregated vertical lead metrics
+// @Description Get aggregated loan metrics grouped by vertical lead name for the Credit Health by Branch "By Vertical Lead" view
+// @Tags VerticalLeads
+// @Accept json
+// @Produce json
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param wave query string false "Filter by wave"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /vertical-leads/metrics [get]
+func (h *DashboardHandler) GetVerticalLeadMetrics(c *gin.Context) {
+	filters := make(map[string]interface{})
+
+	if branch := c.Query("branch"); branch != "" {
+		filters["branch"] = branch
+	}
+	if region := c.Query("region"); region != "" {
+		filters["region"] = region
+	}
+	if channel := c.Query("channel"); channel != "" {
+		filters["channel"] = channel
+	}
+	if userType := c.Query("user_type"); userType != "" {
+		filters["user_type"] = userType
+	}
+	if wave := c.Query("wave"); wave != "" {
+		filters["wave"] = wave
+	}
+
+	metrics, err := h.dashboardRepo.GetVerticalLeadMetrics(filters)
+	if err != nil {
+		log.Printf("failed to retrieve vertical lead metrics: %v", err)
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve vertical lead metrics",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"vertical_leads": metrics,
+			"pagination":     newPagination(1, len(metrics), len(metrics)),
+		},
+	})
+}
+
+// GetVerticalLeadsList handles GET /api/v1/vertical-leads/list
+// @Summary Get list of vertical leads
+// @Description Get distinct vertical lead names from loans, including unassigned loans
+// @Tags VerticalLeads
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /vertical-leads/list [get]
+func (h *DashboardHandler) GetVerticalLeadsList(c *gin.Context) {
+	verticalLeads, err := h.dashboardRepo.GetVerticalLeadNames()
+	if err != nil {
+		log.Printf("failed to retrieve vertical leads: %v", err)
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve vertical leads",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"vertical_leads": verticalLeads,
+		},
+	})
+}
+
+// GetFilterOptions handles GET /api/v1/filters/:type
+// Options are served from a short-lived cache that is cleared after each
+// sync; pass no_cache=true to read straight from the database.
+func (h *DashboardHandler) GetFilterOptions(c *gin.Context) {
+	filterType := c.Param("type")
+
+	// Parse additional filters
+	filters := make(map[string]interface{})
+	if region := c.Query("region"); region != "" {
+		filters["region"] = region
+	}
+	if branch := c.Query("branch"); branch != "" {
+		filters["branch"] = branch
+	}
+	if c.Query("no_cache") == "true" {
+		filters["bypass_cache"] = true
+	}
+
+	options, err := h.dashboardRepo.GetFilterOptions(filterType, filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve filter options",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			filterType: options,
+		},
+	})
+}
+
+// GetTeamMembers handles GET /api/v1/team-members
+func (h *DashboardHandler) GetTeamMembers(c *gin.Context) {
+	members, err := h.dashboardRepo.GetTeamMembers()
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve team members",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"team_members": members,
+		},
+	})
+}
+
+// UpdateOfficerAudit handles PUT /api/v1/officers/:officer_id/audit
+func (h *DashboardHandler) UpdateOfficerAudit(c *gin.Context) {
+	officerID := c.Param("officer_id")
+
+	var update models.AuditUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid request body",
+			Error:   newAPIError(models.ErrCodeValidation, err.Error()),
+		})
+		return
+	}
+
+	if !models.IsValidAuditStatus(update.AuditStatus) {
+		apiErr := newAPIError(models.ErrCodeValidation, fmt.Sprintf("invalid audit_status %q", update.AuditStatus))
+		apiErr.Details = map[string]interface{}{"allowed": models.AuditStatuses}
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid audit_status",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	err := h.dashboardRepo.UpdateOfficerAudit(officerID, &update)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to update audit assignment",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Message: "Audit assignment updated successfully",
+		Data: map[string]interface{}{
+			"officer_id":    officerID,
+			"assignee_id":   update.AssigneeID,
+			"assignee_name": update.AssigneeName,
+			"audit_status":  update.AuditStatus,
+		},
+	})
+}
+
+// GetOfficerSnapshot handles GET /api/v1/officers/:officer_id/snapshot
+// @Summary Get an officer's metrics as of a date
+// @Description Get the officer's portfolio, overdue and collection figures as captured on the given date. Snapshots are taken daily by the scheduled POST /officers/snapshots and after each loan field recalculation; the last capture of a day is kept.
+// @Tags Officers
+// @Accept json
+// @Produce json
+// @Param officer_id path string true "Officer ID"
+// @Param date query string true "Snapshot date (YYYY-MM-DD)"
+// @Success 200 {object} models.APIResponse{data=models.OfficerSnapshot}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /officers/{officer_id}/snapshot [get]
+func (h *DashboardHandler) GetOfficerSnapshot(c *gin.Context) {
+	officerID := c.Param("officer_id")
+	date := c.Query("date")
+	if date == "" {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "date is required",
+			Error:   newAPIError(models.ErrCodeValidation, "date query parameter is required (YYYY-MM-DD)"),
+		})
+		return
+	}
+
+	snapshot, err := h.dashboardRepo.GetOfficerMetricsAsOf(officerID, date)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		message := "Failed to retrieve officer snapshot"
+		if statusCode == http.StatusNotFound {
+			message = "No snapshot found for this officer and date"
+		}
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: message,
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   snapshot,
+	})
+}
+
+// CaptureOfficerSnapshots handles POST /api/v1/officers/snapshots
+// @Summary Capture today's officer snapshots
+// @Description Records today's portfolio, overdue and collection figures for every dashboard officer. Run daily by the scheduler; capturing again on the same day replaces that day's snapshots, so retries are safe.
+// @Tags Officers
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /officers/snapshots [post]
+func (h *DashboardHandler) CaptureOfficerSnapshots(c *gin.Context) {
+	captured, err := h.dashboardRepo.CaptureOfficerSnapshots()
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to capture officer snapshots",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	log.Printf("📸 Captured officer snapshots for %d officers", captured)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Captured snapshots for %d officers", captured),
+		Data: map[string]interface{}{
+			"officers_captured": captured,
+		},
+	})
+}
+
+// GetOfficerCollectionMethods handles GET /api/v1/officers/:officer_id/collection-methods
+// @Summary Get officer collections by payment method
+// @Description Get how an officer's collections for the period split across agent debit, transfer, escrow debit and other payment methods
+// @Tags Officers
+// @Accept json
+// @Produce json
+// @Param officer_id path string true "Officer ID"
+// @Param period query string false "Period (today, this_week, this_month, last_month, last_7_days)"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /officers/{officer_id}/collection-methods [get]
+func (h *DashboardHandler) GetOfficerCollectionMethods(c *gin.Context) {
+	officerID := c.Param("officer_id")
+	period := h.collectionsPeriod(c)
+
+	methods, err := h.dashboardRepo.GetOfficerRepaymentMethods(officerID, period)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve officer collection methods",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	totalCollected := 0.0
+	for _, m := range methods {
+		totalCollected += m.Amount
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"officer_id":      officerID,
+			"period":          period,
+			"total_collected": totalCollected,
+			"methods":         methods,
+		},
+	})
+}
+
+// GetOfficerAuditHistory handles GET /api/v1/officers/:officer_id/audit-history
+func (h *DashboardHandler) GetOfficerAuditHistory(c *gin.Context) {
+	officerID := c.Param("officer_id")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid limit",
+			Error:   newAPIError(models.ErrCodeValidation, "limit must be a positive integer"),
+		})
+		return
+	}
+	// Larger limits are clamped rather than rejected so existing callers
+	// asking for "everything" keep working.
+	if maxLimit := h.cfg.MaxAuditHistoryLimit; maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+
+	history, err := h.dashboardRepo.GetOfficerAuditHistory(officerID, limit)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve audit history",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"audit_history": history,
+			"pagination":    newPagination(1, len(history), len(history)),
+		},
+	})
+}
+
+// GetTopRiskLoans handles GET /api/v1/officers/:officer_id/top-risk-loans
+func (h *DashboardHandler) GetTopRiskLoans(c *gin.Context) {
+	officerID := c.Param("officer_id")
+	if officerID == "" {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Officer ID is required",
+			Error:   newAPIError("INVALID_OFFICER_ID", "Officer ID parameter is missing"),
+		})
+		return
+	}
+
+	// Parse limit parameter (default to 20)
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	// Fetch top risk loans from repository
+	loans, err := h.dashboardRepo.GetTopRiskLoans(officerID, limit)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve top risk loans",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"officer_id": officerID,
+			"loans":      loans,
+			"pagination": newPagination(1, limit, len(loans)),
+		},
+	})
+}
+
+// parseLoanFilters reads the standard loan filter query parameters understood
+// by the repository's shared loan filter builder.
+func parseLoanFilters(c *gin.Context) map[string]interface{} {
+	filters := make(map[string]interface{})
+
+	for _, key := range []string{
+		"officer_id", "branch", "region", "channel", "user_type", "status",
+		"django_status", "performance_status", "wave", "customer_phone",
+		"vertical_lead_email", "vertical_lead_name", "supervisor_email", "loan_type",
+		"verification_status",
+	} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+	if dpdMin := c.Query("dpd_min"); dpdMin != "" {
+		if min, err := strconv.Atoi(dpdMin); err == nil {
+			filters["dpd_min"] = min
+		}
+	}
+	if dpdMax := c.Query("dpd_max"); dpdMax != "" {
+		if max, err := strconv.Atoi(dpdMax); err == nil {
+			filters["dpd_max"] = max
+		}
+	}
+
+	return filters
+}
+
+// GetLoanStatusBreakdown handles GET /api/v1/loans/status-breakdown
+// @Summary Get loan counts by status
+// @Description Get loan counts and outstanding balances grouped by normalized status and by raw Django status, for the status donut charts
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param officer_id query string false "Filter by officer ID (supports comma-separated multi-select)"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param status query string false "Filter by normalized status"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/status-breakdown [get]
+func (h *DashboardHandler) GetLoanStatusBreakdown(c *gin.Context) {
+	filters := parseLoanFilters(c)
+
+	byStatus, byDjangoStatus, err := h.dashboardRepo.GetLoanStatusCounts(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve loan status breakdown",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"by_status":        byStatus,
+			"by_django_status": byDjangoStatus,
+		},
+	})
+}
+
+// GetVerificationStatusBreakdown handles GET /api/v1/loans/verification-breakdown
+// @Summary Get loan counts by verification status
+// @Description Get loan counts and outstanding balances grouped by verification_status. Loans without a verification status are grouped under __MISSING__.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param officer_id query string false "Filter by officer ID (supports comma-separated multi-select)"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param user_type query string false "Filter by user type"
+// @Param status query string false "Filter by normalized status"
+// @Param django_status query string false "Filter by raw Django status (comma-separated list; use __MISSING__ for missing)"
+// @Param wave query string false "Filter by wave"
+// @Param loan_type query string false "Filter by loan type (supports comma-separated multi-select)"
+// @Param verification_status query string false "Filter by verification status (comma-separated list; use __MISSING__ for missing)"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/verification-breakdown [get]
+func (h *DashboardHandler) GetVerificationStatusBreakdown(c *gin.Context) {
+	filters := parseLoanFilters(c)
+
+	byVerificationStatus, err := h.dashboardRepo.GetVerificationStatusBreakdown(filters)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve verification status breakdown",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"by_verification_status": byVerificationStatus,
+		},
+	})
+}
+
+// GetLoanSortableFields handles GET /api/v1/loans/sortable-fields
+// @Summary List sortable loan fields
+// @Description Returns the sort_by values accepted by GET /loans
+// @Tags Loans
+// @Produce json
+// @Success 200 {object} models.APIResponse
+// @Router /loans/sortable-fields [get]
+func (h *DashboardHandler) GetLoanSortableFields(c *gin.Context) {
+	respondSortableFields(c, repository.SortableFields(repository.SortEntityLoans))
+}
+
+// GetOfficerSortableFields handles GET /api/v1/officers/sortable-fields
+// @Summary List sortable officer fields
+// @Description Returns the sort_by values accepted by GET /officers, including metrics computed outside SQL
+// @Tags Officers
+// @Produce json
+// @Success 200 {object} models.APIResponse
+// @Router /officers/sortable-fields [get]
+func (h *DashboardHandler) GetOfficerSortableFields(c *gin.Context) {
+	fields := repository.SortableFields(repository.SortEntityOfficers)
+	for field := range officerComputedSortFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	respondSortableFields(c, fields)
+}
+
+// GetBranchSortableFields handles GET /api/v1/branches/sortable-fields
+// @Summary List sortable branch fields
+// @Description Returns the sort_by values accepted by GET /branches
+// @Tags Branches
+// @Produce json
+// @Success 200 {object} models.APIResponse
+// @Router /branches/sortable-fields [get]
+func (h *DashboardHandler) GetBranchSortableFields(c *gin.Context) {
+	respondSortableFields(c, repository.SortableFields(repository.SortEntityBranches))
+}
+
+func respondSortableFields(c *gin.Context, fields []string) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"fields":    fields,
+			"sort_dirs": []string{"asc", "desc"},
+		},
+	})
+}
+
+// GetPortfolioTopRiskLoans handles GET /api/v1/loans/top-risk
+// @Summary Get top risk loans across the portfolio
+// @Description Scores all delinquent or FIMR-tagged active loans matching the filters and returns the N highest-risk loans
+// @Tags Loans
+// @Produce json
+// @Param branch query string false "Branch"
+// @Param region query string false "Region (comma-separated for multi-select)"
+// @Param channel query string false "Channel"
+// @Param wave query string false "Wave"
+// @Param officer_id query string false "Officer ID"
+// @Param limit query int false "Number of loans to return (default 20, max 100)"
+// @Success 200 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/top-risk [get]
+func (h *DashboardHandler) GetPortfolioTopRiskLoans(c *gin.Context) {
+	filters := make(map[string]interface{})
+
+	if branch := c.Query("branch"); branch != "" {
+		filters["branch"] = branch
+	}
+	if region := c.Query("region"); region != "" {
+		filters["region"] = region
+	}
+	if channel := c.Query("channel"); channel != "" {
+		filters["channel"] = channel
+	}
+	if wave := c.Query("wave"); wave != "" {
+		filters["wave"] = wave
+	}
+	if officerID := c.Query("officer_id"); officerID != "" {
+		filters["officer_id"] = officerID
+	}
+
+	// Parse limit parameter (default to 20)
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	loans, err := h.dashboardRepo.GetPortfolioTopRiskLoans(filters, limit)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve top risk loans",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"loans":      loans,
+			"pagination": newPagination(1, limit, len(loans)),
+		},
+	})
+}
+
+// GetMultiFlaggedLoans handles GET /api/v1/loans/multi-flagged
+// @Summary List loans flagged by several risk signals at once
+// @Description Collections triage list of open loans carrying every selected risk signal (combined with AND), worst first. At least one signal is required. quiet means no repayment in the last 6 days; past_maturity means past maturity_date with a balance still owed.
+// @Tags Loans
+// @Produce json
+// @Param fimr query bool false "Require the loan to be FIMR-tagged"
+// @Param dpd_over query int false "Require current_dpd above this value"
+// @Param quiet query bool false "Require no repayment in the last 6 days"
+// @Param past_maturity query bool false "Require the loan to be past maturity with a balance owed"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param channel query string false "Filter by channel"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/multi-flagged [get]
+func (h *DashboardHandler) GetMultiFlaggedLoans(c *gin.Context) {
+	var signals models.MultiFlagSignals
+	for key, dst := range map[string]*bool{
+		"fimr":          &signals.FIMR,
+		"quiet":         &signals.Quiet,
+		"past_maturity": &signals.PastMaturity,
+	} {
+		raw := c.Query(key)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid " + key + " parameter",
+				Error:   newAPIError(models.ErrCodeValidation, key+" must be true or false"),
+			})
+			return
+		}
+		*dst = value
+	}
+	if raw := c.Query("dpd_over"); raw != "" {
+		dpdOver, err := strconv.Atoi(raw)
+		if err != nil || dpdOver < 0 {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid dpd_over parameter",
+				Error:   newAPIError(models.ErrCodeValidation, "dpd_over must be a non-negative integer"),
+			})
+			return
+		}
+		signals.DPDOver = &dpdOver
+	}
+
+	filters := parseLoanFilters(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	filters["page"] = page
+	filters["limit"] = limit
+
+	loans, total, err := h.dashboardRepo.GetMultiFlaggedLoans(filters, signals)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve multi-flagged loans",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"signals":    signals,
+			"loans":      loans,
+			"pagination": newPagination(page, limit, total),
+		},
+	})
+}
+
+// GetLoanCollectionSummary handles GET /api/v1/loans/:loan_id/collection-summary
+// @Summary Get a loan's collections by payment method
+// @Description Get the total collected on a loan to date and how it splits across agent debit, transfer, escrow debit and other payment methods, from non-reversed repayments
+// @Tags Loans
+// @Produce json
+// @Param loan_id path string true "Loan ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/{loan_id}/collection-summary [get]
+func (h *DashboardHandler) GetLoanCollectionSummary(c *gin.Context) {
+	summary, err := h.dashboardRepo.GetLoanCollectionSummary(c.Param("loan_id"))
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve loan collection summary",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   summary,
+	})
+}
+
+// FlagLoansForReview handles POST /api/v1/loans/review-flags
+// @Summary Flag loans for manual review
+// @Description Adds loans to the manual review worklist with a note and assignee. Loans already flagged have their note and assignee replaced. Flags are kept separately from synced loan data, so they survive syncs. Unknown loan IDs are reported back, not flagged.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param flags body models.LoanReviewFlagInput true "Loan IDs (up to 500), note and assignee"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/review-flags [post]
+func (h *DashboardHandler) FlagLoansForReview(c *gin.Context) {
+	var input models.LoanReviewFlagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid request body",
+			Error:   newAPIError(models.ErrCodeValidation, err.Error()),
+		})
+		return
+	}
 
-	history, err := h.dashboardRepo.GetOfficerAuditHistory(officerID, limit)
+	flagged, unknown, err := h.dashboardRepo.FlagLoansForReview(&input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve audit history",
-			Error:   newAPIError("INTERNAL_ERROR", err.Error()),
+			Message: "Failed to flag loans for review",
+			Error:   apiErr,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
-		Status: "success",
+		Status:  "success",
+		Message: fmt.Sprintf("%d loan(s) flagged for review", len(flagged)),
 		Data: map[string]interface{}{
-			"audit_history": history,
+			"flagged_loan_ids": flagged,
+			"unknown_loan_ids": unknown,
 		},
 	})
 }
 
-// GetTopRiskLoans handles GET /api/v1/officers/:officer_id/top-risk-loans
-func (h *DashboardHandler) GetTopRiskLoans(c *gin.Context) {
-	officerID := c.Param("officer_id")
-	if officerID == "" {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Status:  "error",
-			Message: "Officer ID is required",
-			Error:   newAPIError("INVALID_OFFICER_ID", "Officer ID parameter is missing"),
-		})
-		return
+// GetLoanReviewFlags handles GET /api/v1/loans/review-flags
+// @Summary List loans flagged for manual review
+// @Description Lists the manual review worklist, most recently flagged first, with the note, assignee and loan details
+// @Tags Loans
+// @Produce json
+// @Param assignee query string false "Only loans assigned to this assignee"
+// @Param officer_id query string false "Filter by officer ID"
+// @Param branch query string false "Filter by branch"
+// @Param region query string false "Filter by region (supports comma-separated multi-select)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/review-flags [get]
+func (h *DashboardHandler) GetLoanReviewFlags(c *gin.Context) {
+	filters := parseLoanFilters(c)
+	if assignee := c.Query("assignee"); assignee != "" {
+		filters["assignee"] = assignee
 	}
-
-	// Parse limit parameter (default to 20)
-	limit := 20
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
 	}
+	if limit < 1 {
+		limit = 50
+	}
+	filters["page"] = page
+	filters["limit"] = limit
 
-	// Fetch top risk loans from repository
-	loans, err := h.dashboardRepo.GetTopRiskLoans(officerID, limit)
+	flags, total, err := h.dashboardRepo.GetLoanReviewFlags(filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
-			Message: "Failed to retrieve top risk loans",
-			Error:   newAPIError("TOP_RISK_LOANS_ERROR", err.Error()),
+			Message: "Failed to retrieve loan review flags",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1333,10 +3282,8 @@ func (h *DashboardHandler) GetTopRiskLoans(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status: "success",
 		Data: map[string]interface{}{
-			"officer_id": officerID,
-			"limit":      limit,
-			"count":      len(loans),
-			"loans":      loans,
+			"loans":      flags,
+			"pagination": newPagination(page, limit, total),
 		},
 	})
 }
@@ -1348,10 +3295,11 @@ func (h *DashboardHandler) GetLoanRepayments(c *gin.Context) {
 	// Fetch repayments for the loan
 	repayments, err := h.repaymentRepo.GetByLoanID(c.Request.Context(), loanID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to retrieve loan repayments",
-			Error:   newAPIError("LOAN_REPAYMENTS_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1380,15 +3328,15 @@ func (h *DashboardHandler) GetLoanRepayments(c *gin.Context) {
 		Status: "success",
 		Data: map[string]interface{}{
 			"loan_id":    loanID,
-			"count":      len(repayments),
 			"repayments": repayments,
+			"pagination": newPagination(1, len(repayments), len(repayments)),
 		},
 	})
 }
 
 // RecalculateAllLoanFields handles POST /api/v1/loans/recalculate-fields
 // @Summary Recalculate all loan computed fields
-// @Description Manually trigger recalculation of all computed fields (actual_outstanding, total_outstanding, current_dpd, etc.) for all loans. This operation runs asynchronously.
+// @Description Starts a background job that recalculates all computed fields (actual_outstanding, total_outstanding, current_dpd, etc.) for all loans, re-derives FIMR tags with the configured grace period, normalises outstanding balances and captures today's officer snapshots. Returns a job_id; poll GET /sync/jobs/{job_id} for the status and the rows affected by each step (result).
 // @Tags Loans
 // @Accept json
 // @Produce json
@@ -1396,27 +3344,86 @@ func (h *DashboardHandler) GetLoanRepayments(c *gin.Context) {
 // @Failure 500 {object} models.APIResponse
 // @Router /loans/recalculate-fields [post]
 func (h *DashboardHandler) RecalculateAllLoanFields(c *gin.Context) {
-	// Run recalculation asynchronously to avoid timeout
-	go func() {
-		log.Println("🔄 Starting loan fields recalculation...")
-		rowsAffected, err := h.dashboardRepo.RecalculateAllLoanFields()
-		if err != nil {
-			log.Printf("❌ Failed to recalculate loan fields: %v", err)
-			return
-		}
-		log.Printf("✅ Successfully recalculated %d loans", rowsAffected)
-	}()
+	jobID := uuid.New().String()
+	if err := h.syncRepo.CreateJob(c.Request.Context(), jobID, "loan_recalculation", 0); err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create recalculation job",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	// The recalculation can take several minutes, longer than the request
+	// may stay open, so it runs on its own context.
+	go h.runRecalculationJob(context.Background(), jobID)
 
-	// Return immediately with 202 Accepted
 	c.JSON(http.StatusAccepted, models.APIResponse{
 		Status:  "success",
-		Message: "Loan field recalculation started. This process will run in the background and may take several minutes to complete.",
+		Message: "Loan field recalculation started. Poll the job for progress.",
 		Data: map[string]interface{}{
-			"status": "processing",
+			"job_id": jobID,
+			"status": repository.SyncJobRunning,
 		},
 	})
 }
 
+// runRecalculationJob recalculates loan fields and captures officer snapshots,
+// storing the rows affected by each step in the job's result as it goes.
+func (h *DashboardHandler) runRecalculationJob(ctx context.Context, jobID string) {
+	job := &models.SyncJob{JobID: jobID, Status: repository.SyncJobRunning}
+	result := &models.LoanRecalculationResult{}
+	finish := func(status string, err error) {
+		job.Status = status
+		if err != nil {
+			msg := err.Error()
+			job.ErrorMessage = &msg
+		}
+		if raw, jsonErr := json.Marshal(result); jsonErr == nil {
+			job.Result = raw
+		}
+		if updateErr := h.syncRepo.UpdateJob(ctx, job); updateErr != nil {
+			log.Printf("⚠️  Failed to update recalculation job %s: %v", jobID, updateErr)
+		}
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("❌ Recalculation job %s panicked: %v", jobID, rec)
+			finish(repository.SyncJobFailed, fmt.Errorf("job aborted: %v", rec))
+		}
+	}()
+
+	log.Printf("🔄 Recalculation job %s started", jobID)
+	recalculated, err := h.dashboardRepo.RecalculateAllLoanFields()
+	if err != nil {
+		log.Printf("❌ Recalculation job %s failed: %v", jobID, err)
+		finish(repository.SyncJobFailed, err)
+		return
+	}
+	result = recalculated
+	log.Printf("✅ Step 1 (recalculate_all_loan_fields): recalculated %d loans", result.LoansRecalculated)
+	log.Printf("✅ Step 2 (FIMR recompute, %d-day grace): changed %d tags", h.cfg.FIMRGracePeriodDays, result.FIMRTagsChanged)
+	if result.NormalizationRan {
+		log.Printf("✅ Step 3 (outstanding normalisation): normalised %d loans", result.LoansNormalized)
+	} else {
+		log.Println("⏭️  Step 3 (outstanding normalisation): skipped (disabled by config)")
+	}
+	finish(repository.SyncJobRunning, nil)
+
+	captured, err := h.dashboardRepo.CaptureOfficerSnapshots()
+	if err != nil {
+		log.Printf("❌ Step 4 (officer snapshots): %v", err)
+		finish(repository.SyncJobFailed, err)
+		return
+	}
+	result.SnapshotsRan = true
+	result.OfficersCaptured = captured
+	log.Printf("✅ Step 4 (officer snapshots): captured %d officers", captured)
+	finish(repository.SyncJobDone, nil)
+}
+
 // SyncLoanRepayments handles POST /api/v1/loans/:loan_id/sync-repayments
 // @Summary Sync repayments for a specific loan
 // @Description Syncs repayment data for a single loan from Django source database to SeedsMetrics
@@ -1433,7 +3440,7 @@ func (h *DashboardHandler) SyncLoanRepayments(c *gin.Context) {
 	loanID := c.Param("loan_id")
 
 	if loanID == "" {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status:  "error",
 			Message: "Loan ID is required",
 			Error:   newAPIError("INVALID_REQUEST", "loan_id parameter is missing"),
@@ -1448,7 +3455,7 @@ func (h *DashboardHandler) SyncLoanRepayments(c *gin.Context) {
 	if err != nil {
 		// Check if it's a "not found" error
 		if err.Error() == "loan "+loanID+" not found" {
-			c.JSON(http.StatusNotFound, models.APIResponse{
+			RespondError(c, http.StatusNotFound, models.APIResponse{
 				Status:  "error",
 				Message: fmt.Sprintf("Loan %s not found", loanID),
 				Error:   newAPIError("LOAN_NOT_FOUND", err.Error()),
@@ -1457,20 +3464,23 @@ func (h *DashboardHandler) SyncLoanRepayments(c *gin.Context) {
 		}
 
 		log.Printf("❌ Failed to sync repayments for loan %s: %v", loanID, err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to sync repayments",
-			Error:   newAPIError("SYNC_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
 
 	log.Printf("✅ Successfully synced repayments for loan %s: %d synced, %d errors", loanID, result.TotalSynced, result.TotalErrors)
+	h.dashboardRepo.InvalidateFilterOptions()
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Message: result.Message,
 		Data: map[string]interface{}{
+			"run_id":       result.RunID,
 			"loan_id":      result.LoanID,
 			"total_synced": result.TotalSynced,
 			"total_errors": result.TotalErrors,
@@ -1481,23 +3491,33 @@ func (h *DashboardHandler) SyncLoanRepayments(c *gin.Context) {
 
 // UpdatePastMaturityStatus handles POST /api/v1/loans/update-past-maturity
 // @Summary Update past maturity loan statuses
-// @Description Updates django_status to 'PAST_MATURITY' for all loans where current date exceeds maturity_date
+// @Description Updates django_status to 'PAST_MATURITY' (and status to 'Defaulted') for OPEN loans where current date exceeds maturity_date. branch and region scope the update to those loans only; without them every eligible loan is updated.
 // @Tags Loans
 // @Accept json
 // @Produce json
+// @Param branch query string false "Only update loans in this branch"
+// @Param region query string false "Only update loans in these regions (comma-separated)"
 // @Success 200 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /loans/update-past-maturity [post]
 func (h *DashboardHandler) UpdatePastMaturityStatus(c *gin.Context) {
-	log.Println("📅 Updating past maturity loan statuses...")
+	scope := make(map[string]interface{})
+	if branch := c.Query("branch"); branch != "" {
+		scope["branch"] = branch
+	}
+	if region := c.Query("region"); region != "" {
+		scope["region"] = region
+	}
+	log.Printf("📅 Updating past maturity loan statuses (scope: %v)...", scope)
 
-	rowsUpdated, err := h.dashboardRepo.UpdatePastMaturityStatus()
+	rowsUpdated, err := h.dashboardRepo.UpdatePastMaturityStatus(scope)
 	if err != nil {
 		log.Printf("❌ Error updating past maturity status: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to update past maturity statuses",
-			Error:   newAPIError("UPDATE_FAILED", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
@@ -1509,13 +3529,65 @@ func (h *DashboardHandler) UpdatePastMaturityStatus(c *gin.Context) {
 		Message: fmt.Sprintf("Updated %d loans to PAST_MATURITY status", rowsUpdated),
 		Data: map[string]interface{}{
 			"loans_updated": rowsUpdated,
+			"scope":         scope,
+		},
+	})
+}
+
+// RecomputeFIMRTags handles POST /api/v1/loans/recompute-fimr
+// @Summary Recompute FIMR tags with a grace period
+// @Description Re-derives fimr_tagged for all loans from first_payment_due_date and first_payment_received_date. A loan is tagged when its first payment is more than grace_period_days late (or still missing after that). Defaults to DASHBOARD_FIMR_GRACE_PERIOD_DAYS.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param grace_period_days query int false "Days the first payment may be late before the loan is tagged FIMR"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /loans/recompute-fimr [post]
+func (h *DashboardHandler) RecomputeFIMRTags(c *gin.Context) {
+	gracePeriodDays := h.cfg.FIMRGracePeriodDays
+	if raw := c.Query("grace_period_days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid grace_period_days",
+				Error:   newAPIError(models.ErrCodeValidation, "grace_period_days must be a non-negative integer"),
+			})
+			return
+		}
+		gracePeriodDays = days
+	}
+
+	log.Printf("🏷️  Recomputing FIMR tags with a %d-day grace period...", gracePeriodDays)
+
+	rowsUpdated, err := h.dashboardRepo.RecomputeFIMRTags(gracePeriodDays)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to recompute FIMR tags",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	log.Printf("✅ Recomputed FIMR tags: %d loans changed", rowsUpdated)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Recomputed FIMR tags; %d loans changed", rowsUpdated),
+		Data: map[string]interface{}{
+			"loans_updated":     rowsUpdated,
+			"grace_period_days": gracePeriodDays,
 		},
 	})
 }
 
 // SyncNewRepayments handles POST /api/v1/sync/repayments
 // @Summary Sync new repayments incrementally
-// @Description Syncs only new repayments from Django (where ID > max existing ID). Much faster than full sync.
+// @Description Syncs only new repayments from Django (where ID > max existing ID). Much faster than full sync. Repayments are committed in chunks of ETL_REPAYMENT_SYNC_BATCH_SIZE, so a failure part way through keeps the chunks already written.
 // @Tags Sync
 // @Accept json
 // @Produce json
@@ -1528,24 +3600,319 @@ func (h *DashboardHandler) SyncNewRepayments(c *gin.Context) {
 	result, err := h.syncService.SyncNewRepayments(c.Request.Context())
 	if err != nil {
 		log.Printf("❌ Error syncing new repayments: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
 			Status:  "error",
 			Message: "Failed to sync new repayments",
-			Error:   newAPIError("SYNC_ERROR", err.Error()),
+			Error:   apiErr,
 		})
 		return
 	}
 
 	log.Printf("✅ Incremental sync complete: %d synced, %d errors", result.TotalSynced, result.TotalErrors)
+	h.dashboardRepo.InvalidateFilterOptions()
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Message: result.Message,
 		Data: map[string]interface{}{
+			"run_id":          result.RunID,
 			"total_synced":    result.TotalSynced,
 			"total_errors":    result.TotalErrors,
 			"last_id_synced":  result.LastIDSynced,
 			"previous_max_id": result.PreviousMaxID,
+			"batches":         result.Batches,
+			"batch_size":      result.BatchSize,
+		},
+	})
+}
+
+// ResyncStaleLoans handles POST /api/v1/sync/loans/stale
+// @Summary Re-sync stale loans from Django
+// @Description Re-fetches from Django every loan not synced within the last older_than_days days, picking up changes earlier syncs missed
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param older_than_days query int false "Re-sync loans last synced more than this many days ago" default(7)
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /sync/loans/stale [post]
+func (h *DashboardHandler) ResyncStaleLoans(c *gin.Context) {
+	olderThanDays, err := strconv.Atoi(c.DefaultQuery("older_than_days", "7"))
+	if err != nil || olderThanDays < 1 {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "older_than_days must be a positive integer",
+			Error:   newAPIError(models.ErrCodeValidation, "invalid older_than_days"),
+		})
+		return
+	}
+
+	result, err := h.syncService.ResyncStaleLoans(c.Request.Context(), olderThanDays)
+	if err != nil {
+		log.Printf("❌ Error resyncing stale loans: %v", err)
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to resync stale loans",
+			Error:   apiErr,
+		})
+		return
+	}
+	h.dashboardRepo.InvalidateFilterOptions()
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Message: result.Message,
+		Data:    result,
+	})
+}
+
+// maxRetrySyncErrorsLimit caps how many dead-letter records a single
+// POST /sync/retry-errors call reprocesses.
+const maxRetrySyncErrorsLimit = 5000
+
+// RetrySyncErrors handles POST /api/v1/sync/retry-errors
+// @Summary Retry failed sync records
+// @Description Starts a background job that reprocesses loans and repayments left in sync_errors by earlier syncs after their automatic retries (ETL_SYNC_RETRY_ATTEMPTS) were used up. Each record is refetched from Django and written again; records that still fail stay pending for the next retry with their cumulative attempts, until those reach ETL_SYNC_ERROR_MAX_ATTEMPTS and the record is marked dead. Returns a job_id; poll GET /sync/jobs/{job_id} for the status and the retry counts (result).
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param limit query int false "Maximum failed records to retry (max 5000)" default(500)
+// @Success 202 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /sync/retry-errors [post]
+func (h *DashboardHandler) RetrySyncErrors(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if err != nil || limit < 1 || limit > maxRetrySyncErrorsLimit {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("limit must be an integer between 1 and %d", maxRetrySyncErrorsLimit),
+			Error:   newAPIError(models.ErrCodeValidation, "invalid limit"),
+		})
+		return
+	}
+
+	jobID := uuid.New().String()
+	if err := h.syncRepo.CreateJob(c.Request.Context(), jobID, "sync_error_retry", 0); err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create sync error retry job",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	// Refetching each record from Django can outlast the request, so the
+	// retry runs on its own context.
+	go h.runRetrySyncErrorsJob(context.Background(), jobID, limit)
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Status:  "success",
+		Message: "Sync error retry started. Poll the job for progress.",
+		Data: map[string]interface{}{
+			"job_id": jobID,
+			"status": repository.SyncJobRunning,
+		},
+	})
+}
+
+// runRetrySyncErrorsJob retries up to limit dead-letter records and stores
+// the retry counts in the job's result.
+func (h *DashboardHandler) runRetrySyncErrorsJob(ctx context.Context, jobID string, limit int) {
+	job := &models.SyncJob{JobID: jobID, Status: repository.SyncJobRunning}
+	finish := func(status string, err error) {
+		job.Status = status
+		if err != nil {
+			msg := err.Error()
+			job.ErrorMessage = &msg
+		}
+		if updateErr := h.syncRepo.UpdateJob(ctx, job); updateErr != nil {
+			log.Printf("⚠️  Failed to update sync error retry job %s: %v", jobID, updateErr)
+		}
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("❌ Sync error retry job %s panicked: %v", jobID, rec)
+			finish(repository.SyncJobFailed, fmt.Errorf("job aborted: %v", rec))
+		}
+	}()
+
+	log.Printf("🔄 Sync error retry job %s started", jobID)
+	result, err := h.syncService.RetrySyncErrors(ctx, limit)
+	if err != nil {
+		log.Printf("❌ Sync error retry job %s failed: %v", jobID, err)
+		finish(repository.SyncJobFailed, err)
+		return
+	}
+	if result.Resolved > 0 {
+		h.dashboardRepo.InvalidateFilterOptions()
+	}
+
+	job.ProcessedRecords = result.Pending
+	if raw, jsonErr := json.Marshal(result); jsonErr == nil {
+		job.Result = raw
+	}
+	finish(repository.SyncJobDone, nil)
+}
+
+// GetDataFreshness handles GET /api/v1/data-freshness
+// @Summary Get data freshness
+// @Description Returns the latest repayment payment_date, the highest repayment_id, the most recent sync run and when a sync last completed, so the dashboard can show "data as of" and prompt a sync once is_stale is set (no completed sync within DASHBOARD_STALE_DATA_AFTER_HOURS).
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.APIResponse{data=models.DataFreshness}
+// @Failure 500 {object} models.APIResponse
+// @Router /data-freshness [get]
+func (h *DashboardHandler) GetDataFreshness(c *gin.Context) {
+	freshness, err := h.dashboardRepo.GetDataFreshness()
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve data freshness",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   freshness,
+	})
+}
+
+// GetOrphans handles GET /api/v1/integrity/orphans
+// @Summary Find orphaned loans and repayments
+// @Description Counts and samples loans whose officer_id has no officer and repayments whose loan_id has no loan. Metric queries INNER JOIN these tables, so such rows silently drop out of every dashboard total.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param sample_limit query int false "Examples to return of each kind (max 100)" default(20)
+// @Success 200 {object} models.APIResponse{data=models.OrphanReport}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /integrity/orphans [get]
+func (h *DashboardHandler) GetOrphans(c *gin.Context) {
+	sampleLimit, err := strconv.Atoi(c.DefaultQuery("sample_limit", "20"))
+	if err != nil || sampleLimit < 0 || sampleLimit > 100 {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid sample_limit",
+			Error:   newAPIError(models.ErrCodeValidation, "sample_limit must be an integer between 0 and 100"),
+		})
+		return
+	}
+
+	report, err := h.dashboardRepo.GetOrphans(sampleLimit)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to check for orphaned records",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   report,
+	})
+}
+
+// GetStatusMismatches handles GET /api/v1/integrity/status-mismatch
+// @Summary Find loans whose status disagrees with django_status
+// @Description Counts and samples loans whose normalized status is inconsistent with their raw django_status under the documented mapping (returned as mapping). unmapped_django_status loans carry a django_status the mapping doesn't know; status_mismatch loans have a status other than the mapped one. Counts are broken down per mismatch type and status combination.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param sample_limit query int false "Example loans to return (max 100)" default(20)
+// @Success 200 {object} models.APIResponse{data=models.StatusMismatchReport}
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /integrity/status-mismatch [get]
+func (h *DashboardHandler) GetStatusMismatches(c *gin.Context) {
+	sampleLimit, err := strconv.Atoi(c.DefaultQuery("sample_limit", "20"))
+	if err != nil || sampleLimit < 0 || sampleLimit > 100 {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid sample_limit",
+			Error:   newAPIError(models.ErrCodeValidation, "sample_limit must be an integer between 0 and 100"),
+		})
+		return
+	}
+
+	report, err := h.dashboardRepo.GetStatusMismatches(sampleLimit)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to check for status mismatches",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   report,
+	})
+}
+
+// GetSyncErrors handles GET /api/v1/sync/errors
+// @Summary Get failed records for a sync run
+// @Description Returns the records that failed during a sync run along with the error message for each. Defaults to the most recent run when run_id is omitted.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param run_id query int false "Sync run ID (defaults to the latest run)"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /sync/errors [get]
+func (h *DashboardHandler) GetSyncErrors(c *gin.Context) {
+	var runID int64
+	if runIDStr := c.Query("run_id"); runIDStr != "" {
+		parsed, err := strconv.ParseInt(runIDStr, 10, 64)
+		if err != nil || parsed <= 0 {
+			RespondError(c, http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Message: "Invalid run_id",
+				Error:   newAPIError(models.ErrCodeValidation, "run_id must be a positive integer"),
+			})
+			return
+		}
+		runID = parsed
+	}
+
+	run, syncErrors, err := h.syncService.GetSyncErrors(c.Request.Context(), runID)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		message := "Failed to retrieve sync errors"
+		if statusCode == http.StatusNotFound {
+			message = "Sync run not found"
+		}
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: message,
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data: map[string]interface{}{
+			"run":        run,
+			"errors":     syncErrors,
+			"pagination": newPagination(1, len(syncErrors), len(syncErrors)),
 		},
 	})
 }
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
@@ -16,13 +18,31 @@ type ETLHandler struct {
 	loanRepo      *repository.LoanRepository
 	repaymentRepo *repository.RepaymentRepository
 	officerRepo   *repository.OfficerRepository
+	syncRepo      *repository.SyncRepository
+
+	// onSyncComplete, when set, runs after each batch sync finishes
+	onSyncComplete func()
 }
 
-func NewETLHandler(loanRepo *repository.LoanRepository, repaymentRepo *repository.RepaymentRepository, officerRepo *repository.OfficerRepository) *ETLHandler {
+func NewETLHandler(loanRepo *repository.LoanRepository, repaymentRepo *repository.RepaymentRepository, officerRepo *repository.OfficerRepository, syncRepo *repository.SyncRepository) *ETLHandler {
 	return &ETLHandler{
 		loanRepo:      loanRepo,
 		repaymentRepo: repaymentRepo,
 		officerRepo:   officerRepo,
+		syncRepo:      syncRepo,
+	}
+}
+
+// SetOnSyncComplete registers fn to run after each batch sync (direct or
+// background job) finishes, e.g. to invalidate cached filter options.
+func (h *ETLHandler) SetOnSyncComplete(fn func()) {
+	h.onSyncComplete = fn
+}
+
+// syncCompleted runs the onSyncComplete hook, if any.
+func (h *ETLHandler) syncCompleted() {
+	if h.onSyncComplete != nil {
+		h.onSyncComplete()
 	}
 }
 
@@ -41,7 +61,7 @@ func NewETLHandler(loanRepo *repository.LoanRepository, repaymentRepo *repositor
 func (h *ETLHandler) CreateLoan(c *gin.Context) {
 	var input models.LoanInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "VALIDATION_ERROR",
@@ -55,7 +75,7 @@ func (h *ETLHandler) CreateLoan(c *gin.Context) {
 	// Check if loan already exists
 	existingLoan, err := h.loanRepo.GetByID(c.Request.Context(), input.LoanID)
 	if err == nil && existingLoan != nil {
-		c.JSON(http.StatusConflict, models.APIResponse{
+		RespondError(c, http.StatusConflict, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "DUPLICATE_LOAN_ID",
@@ -78,13 +98,11 @@ func (h *ETLHandler) CreateLoan(c *gin.Context) {
 
 	// Create loan
 	if err := h.loanRepo.Create(c.Request.Context(), &input); err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Status: "error",
-			Error: &models.APIError{
-				Code:    "DATABASE_ERROR",
-				Message: "Failed to create loan",
-				Details: map[string]interface{}{"error": err.Error()},
-			},
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create loan",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -113,7 +131,7 @@ func (h *ETLHandler) CreateLoan(c *gin.Context) {
 func (h *ETLHandler) CreateRepayment(c *gin.Context) {
 	var input models.RepaymentInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "VALIDATION_ERROR",
@@ -127,18 +145,16 @@ func (h *ETLHandler) CreateRepayment(c *gin.Context) {
 	// Verify loan exists
 	loan, err := h.loanRepo.GetByID(c.Request.Context(), input.LoanID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Status: "error",
-			Error: &models.APIError{
-				Code:    "DATABASE_ERROR",
-				Message: "Failed to verify loan",
-				Details: map[string]interface{}{"error": err.Error()},
-			},
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to verify loan",
+			Error:   apiErr,
 		})
 		return
 	}
 	if loan == nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "LOAN_NOT_FOUND",
@@ -150,13 +166,11 @@ func (h *ETLHandler) CreateRepayment(c *gin.Context) {
 
 	// Create repayment
 	if err := h.repaymentRepo.Create(c.Request.Context(), &input); err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Status: "error",
-			Error: &models.APIError{
-				Code:    "DATABASE_ERROR",
-				Message: "Failed to create repayment",
-				Details: map[string]interface{}{"error": err.Error()},
-			},
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create repayment",
+			Error:   apiErr,
 		})
 		return
 	}
@@ -175,7 +189,7 @@ func (h *ETLHandler) CreateRepayment(c *gin.Context) {
 func (h *ETLHandler) BatchSync(c *gin.Context) {
 	var request models.ETLSyncRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "VALIDATION_ERROR",
@@ -189,15 +203,72 @@ func (h *ETLHandler) BatchSync(c *gin.Context) {
 	startTime := time.Now()
 	syncID := uuid.New().String()
 
+	results, errors := h.processBatch(c.Request.Context(), &request, nil)
+	h.syncCompleted()
+
+	computationTime := time.Since(startTime).Milliseconds()
+
+	// Determine status
+	status := "success"
+	if results.Loans.Failed > 0 || results.Repayments.Failed > 0 {
+		if results.Loans.Inserted > 0 || results.Repayments.Inserted > 0 {
+			status = "partial_success"
+		} else {
+			status = "error"
+		}
+	}
+
+	// Calculate next sync time (15 minutes from now)
+	nextSync := time.Now().Add(15 * time.Minute)
+
+	response := models.ETLSyncResponse{
+		Status:    status,
+		SyncID:    syncID,
+		Timestamp: time.Now(),
+		Results:   results,
+		ComputedFieldsUpdated: models.ComputedFieldsUpdate{
+			LoansAffected:     results.Repayments.Inserted,
+			ComputationTimeMs: int(computationTime),
+		},
+		NextSyncRecommended: nextSync,
+		Errors:              errors,
+	}
+
+	statusCode := http.StatusOK
+	if status == "partial_success" {
+		statusCode = http.StatusMultiStatus
+	} else if status == "error" {
+		statusCode = http.StatusBadRequest
+	}
+
+	c.JSON(statusCode, response)
+}
+
+// syncJobProgressInterval is how many records a background batch sync
+// processes between progress updates to sync_jobs.
+const syncJobProgressInterval = 100
+
+// processBatch inserts the loans and then the repayments of a batch sync
+// request. When onProgress is set it is called every
+// syncJobProgressInterval records with the number processed so far.
+func (h *ETLHandler) processBatch(ctx context.Context, request *models.ETLSyncRequest, onProgress func(processed int, results models.ETLSyncResults, errors []models.ETLSyncError)) (models.ETLSyncResults, []models.ETLSyncError) {
 	results := models.ETLSyncResults{
 		Loans:      models.ETLEntityResult{},
 		Repayments: models.ETLEntityResult{},
 	}
 	var errors []models.ETLSyncError
+	processed := 0
+
+	progress := func() {
+		processed++
+		if onProgress != nil && processed%syncJobProgressInterval == 0 {
+			onProgress(processed, results, errors)
+		}
+	}
 
 	// Process loans
 	for _, loanInput := range request.Data.Loans {
-		err := h.loanRepo.Create(c.Request.Context(), &loanInput)
+		err := h.loanRepo.Create(ctx, &loanInput)
 		if err != nil {
 			results.Loans.Failed++
 			errors = append(errors, models.ETLSyncError{
@@ -209,11 +280,12 @@ func (h *ETLHandler) BatchSync(c *gin.Context) {
 		} else {
 			results.Loans.Inserted++
 		}
+		progress()
 	}
 
 	// Process repayments
 	for _, repaymentInput := range request.Data.Repayments {
-		err := h.repaymentRepo.Create(c.Request.Context(), &repaymentInput)
+		err := h.repaymentRepo.Create(ctx, &repaymentInput)
 		if err != nil {
 			results.Repayments.Failed++
 			errors = append(errors, models.ETLSyncError{
@@ -225,44 +297,150 @@ func (h *ETLHandler) BatchSync(c *gin.Context) {
 		} else {
 			results.Repayments.Inserted++
 		}
+		progress()
 	}
 
-	computationTime := time.Since(startTime).Milliseconds()
+	return results, errors
+}
 
-	// Determine status
-	status := "success"
-	if results.Loans.Failed > 0 || results.Repayments.Failed > 0 {
-		if results.Loans.Inserted > 0 || results.Repayments.Inserted > 0 {
-			status = "partial_success"
-		} else {
-			status = "error"
-		}
+// StartBatchSyncJob handles POST /api/v1/etl/sync/jobs
+// @Summary Start a background batch sync
+// @Description Accepts the same payload as POST /etl/sync but processes it in the background. Returns a job_id immediately; poll GET /sync/jobs/{job_id} for progress and final counts.
+// @Tags ETL
+// @Accept json
+// @Produce json
+// @Param request body models.ETLSyncRequest true "Batch sync payload"
+// @Success 202 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /etl/sync/jobs [post]
+func (h *ETLHandler) StartBatchSyncJob(c *gin.Context) {
+	var request models.ETLSyncRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status: "error",
+			Error: &models.APIError{
+				Code:    "VALIDATION_ERROR",
+				Message: "Invalid request payload",
+				Details: map[string]interface{}{"error": err.Error()},
+			},
+		})
+		return
 	}
 
-	// Calculate next sync time (15 minutes from now)
-	nextSync := time.Now().Add(15 * time.Minute)
+	jobID := uuid.New().String()
+	total := len(request.Data.Loans) + len(request.Data.Repayments)
+	if err := h.syncRepo.CreateJob(c.Request.Context(), jobID, "etl_batch", total); err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create sync job",
+			Error:   apiErr,
+		})
+		return
+	}
 
-	response := models.ETLSyncResponse{
-		Status:    status,
-		SyncID:    syncID,
-		Timestamp: time.Now(),
-		Results:   results,
-		ComputedFieldsUpdated: models.ComputedFieldsUpdate{
-			LoansAffected:     results.Repayments.Inserted,
-			ComputationTimeMs: int(computationTime),
+	// The request context is cancelled once the 202 is sent, so the job runs
+	// on its own context.
+	go h.runBatchSyncJob(context.Background(), jobID, &request)
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Status:  "success",
+		Message: "Batch sync started. Poll the job for progress.",
+		Data: map[string]interface{}{
+			"job_id":        jobID,
+			"status":        repository.SyncJobRunning,
+			"total_records": total,
 		},
-		NextSyncRecommended: nextSync,
-		Errors:              errors,
+	})
+}
+
+// runBatchSyncJob processes a batch in the background, persisting progress
+// to sync_jobs as it goes. The job fails if nothing could be inserted.
+func (h *ETLHandler) runBatchSyncJob(ctx context.Context, jobID string, request *models.ETLSyncRequest) {
+	job := &models.SyncJob{JobID: jobID, Status: repository.SyncJobRunning}
+	apply := func(processed int, results models.ETLSyncResults, errors []models.ETLSyncError) {
+		job.ProcessedRecords = processed
+		job.LoansInserted = results.Loans.Inserted
+		job.LoansFailed = results.Loans.Failed
+		job.RepaymentsInserted = results.Repayments.Inserted
+		job.RepaymentsFailed = results.Repayments.Failed
+		if job.ErrorMessage == nil && len(errors) > 0 {
+			msg := fmt.Sprintf("%s %s: %s", errors[0].EntityType, errors[0].EntityID, errors[0].ErrorMessage)
+			job.ErrorMessage = &msg
+		}
 	}
 
-	statusCode := http.StatusOK
-	if status == "partial_success" {
-		statusCode = http.StatusMultiStatus
-	} else if status == "error" {
-		statusCode = http.StatusBadRequest
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("❌ Sync job %s panicked: %v", jobID, rec)
+			msg := fmt.Sprintf("job aborted: %v", rec)
+			job.Status = repository.SyncJobFailed
+			job.ErrorMessage = &msg
+			if err := h.syncRepo.UpdateJob(ctx, job); err != nil {
+				log.Printf("⚠️  Failed to record failure of sync job %s: %v", jobID, err)
+			}
+		}
+	}()
+
+	log.Printf("🔄 Sync job %s started", jobID)
+	results, errors := h.processBatch(ctx, request, func(processed int, results models.ETLSyncResults, errors []models.ETLSyncError) {
+		apply(processed, results, errors)
+		if err := h.syncRepo.UpdateJob(ctx, job); err != nil {
+			log.Printf("⚠️  Failed to update progress of sync job %s: %v", jobID, err)
+		}
+	})
+	apply(len(request.Data.Loans)+len(request.Data.Repayments), results, errors)
+	h.syncCompleted()
+
+	job.Status = repository.SyncJobDone
+	if len(errors) > 0 && results.Loans.Inserted == 0 && results.Repayments.Inserted == 0 {
+		job.Status = repository.SyncJobFailed
 	}
+	if err := h.syncRepo.UpdateJob(ctx, job); err != nil {
+		log.Printf("⚠️  Failed to finish sync job %s: %v", jobID, err)
+	}
+	log.Printf("✅ Sync job %s %s: %d loans, %d repayments inserted, %d failed",
+		jobID, job.Status, results.Loans.Inserted, results.Repayments.Inserted, len(errors))
+}
 
-	c.JSON(statusCode, response)
+// GetSyncJob handles GET /api/v1/sync/jobs/:job_id
+// @Summary Get background sync job status
+// @Description Returns the status (running/done/failed), progress (processed_records of total_records) and counts of a background batch sync job, the per-step result of a loan recalculation job, or the retry counts of a sync error retry job
+// @Tags Sync
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /sync/jobs/{job_id} [get]
+func (h *ETLHandler) GetSyncJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Invalid job_id",
+			Error:   newAPIError(models.ErrCodeValidation, "job_id must be a UUID"),
+		})
+		return
+	}
+
+	job, err := h.syncRepo.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to retrieve sync job",
+			Error:   apiErr,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status: "success",
+		Data:   job,
+	})
 }
 
 // CreateOfficer handles POST /api/v1/etl/officers
@@ -279,7 +457,7 @@ func (h *ETLHandler) BatchSync(c *gin.Context) {
 func (h *ETLHandler) CreateOfficer(c *gin.Context) {
 	var input models.OfficerInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
 			Status: "error",
 			Error: &models.APIError{
 				Code:    "VALIDATION_ERROR",
@@ -292,13 +470,11 @@ func (h *ETLHandler) CreateOfficer(c *gin.Context) {
 
 	// Create officer
 	if err := h.officerRepo.Create(c.Request.Context(), &input); err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Status: "error",
-			Error: &models.APIError{
-				Code:    "DATABASE_ERROR",
-				Message: "Failed to create officer",
-				Details: map[string]interface{}{"error": err.Error()},
-			},
+		statusCode, apiErr := classifyError(err)
+		RespondError(c, statusCode, models.APIResponse{
+			Status:  "error",
+			Message: "Failed to create officer",
+			Error:   apiErr,
 		})
 		return
 	}
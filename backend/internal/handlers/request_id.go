@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/seeds-metrics/analytics-backend/internal/models"
+)
+
+// RequestIDHeader carries the request correlation ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key holding the request ID.
+const requestIDKey = "request_id"
+
+type requestIDContextKey struct{}
+
+// maxRequestIDLength bounds client-supplied IDs so they can't bloat logs.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware tags every request with an ID so a failure reported by
+// the frontend can be matched to server logs. An incoming X-Request-ID is
+// reused when it looks sane, otherwise a UUID is generated. The ID is stored
+// in the gin and request contexts and echoed in the response header.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDContextKey{}, id))
+		c.Writer.Header().Set(RequestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the request ID stored by RequestIDMiddleware,
+// or "" when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// RespondError aborts the request with an error response. The request ID is
+// added to the error payload and the failure is logged with it.
+func RespondError(c *gin.Context, statusCode int, response models.APIResponse) {
+	id := c.GetString(requestIDKey)
+	if response.Error == nil {
+		response.Error = newAPIError(models.ErrCodeInternal, response.Message)
+	}
+	response.Error.RequestID = id
+
+	log.Printf("❌ [%s] %s %s -> %d %s: %s", id, c.Request.Method, c.Request.URL.Path,
+		statusCode, response.Error.Code, response.Error.Message)
+
+	c.AbortWithStatusJSON(statusCode, response)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, ch := range id {
+		isAlnum := (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+		if !isAlnum && ch != '-' && ch != '_' && ch != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seeds-metrics/analytics-backend/internal/models"
+)
+
+// StrictParamsQueryKey opts a request in to unknown query parameter checks.
+const StrictParamsQueryKey = "strict_params"
+
+// Query parameters read by parseAllLoansFilters, parseLoanFilters and
+// parseOfficerFilters. Keep these in step with the parsers.
+var (
+	allLoansFilterParams = []string{
+		"officer_id", "branch", "region", "channel", "status", "django_status",
+		"performance_status", "wave", "customer_phone", "vertical_lead_email",
+		"vertical_lead_name", "supervisor_email", "loan_type", "verification_status", "period",
+		"behavior_loan_type", "rot_type", "delay_type", "dpd_min", "dpd_max", "quiet_loans",
+		"review_flagged",
+	}
+	loanFilterParams = []string{
+		"officer_id", "branch", "region", "channel", "user_type", "status",
+		"django_status", "performance_status", "wave", "customer_phone",
+		"vertical_lead_email", "vertical_lead_name", "supervisor_email", "loan_type",
+		"verification_status", "dpd_min", "dpd_max",
+	}
+	officerFilterParams = []string{
+		"branch", "region", "channel", "wave", "user_type", "officer_email",
+		"match", "tenure_bucket", "include_closed", "loan_type", "verification_status",
+	}
+)
+
+// strictQueryParams lists the query parameters each loans/officers route
+// understands, keyed by gin route path. Routes missing from the map are not
+// checked.
+var strictQueryParams = map[string][]string{
+	"/api/v1/loans":                                   withParams(allLoansFilterParams, "page", "limit", "cursor", "sort_by", "sort_dir"),
+	"/api/v1/loans/count":                             allLoansFilterParams,
+	"/api/v1/loans/top-risk":                          {"officer_id", "branch", "region", "channel", "wave", "limit"},
+	"/api/v1/loans/multi-flagged":                     withParams(loanFilterParams, "dpd_over", "page", "limit"),
+	"/api/v1/loans/status-breakdown":                  loanFilterParams,
+	"/api/v1/loans/verification-breakdown":            loanFilterParams,
+	"/api/v1/loans/sortable-fields":                   nil,
+	"/api/v1/loans/review-flags":                      withParams(loanFilterParams, "assignee", "page", "limit"),
+	"/api/v1/loans/:loan_id/repayments":               nil,
+	"/api/v1/loans/:loan_id/collection-summary":       nil,
+	"/api/v1/officers":                                withParams(officerFilterParams, "page", "limit", "sort_by", "sort_dir"),
+	"/api/v1/officers/sortable-fields":                nil,
+	"/api/v1/officers/export":                         withParams(officerFilterParams, "sort_by", "sort_dir"),
+	"/api/v1/officers/:officer_id":                    nil,
+	"/api/v1/officers/:officer_id/metrics-breakdown":  nil,
+	"/api/v1/officers/:officer_id/audit-history":      {"limit"},
+	"/api/v1/officers/:officer_id/collection-methods": {"period"},
+	"/api/v1/officers/:officer_id/snapshot":           {"date"},
+	"/api/v1/officers/:officer_id/top-risk-loans":     {"limit"},
+}
+
+func withParams(base []string, extra ...string) []string {
+	return append(append([]string{}, base...), extra...)
+}
+
+// StrictQueryParams rejects requests carrying query parameters the route
+// doesn't understand, so a typo such as ?regon=Lagos fails with a 400 instead
+// of silently returning the unfiltered dataset. It is opt-in per request via
+// strict_params=true; lenient integrations are unaffected.
+func StrictQueryParams() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		known, ok := strictQueryParams[c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+		query := c.Request.URL.Query()
+		if strict, _ := strconv.ParseBool(query.Get(StrictParamsQueryKey)); !strict {
+			c.Next()
+			return
+		}
+
+		allowed := make(map[string]bool, len(known)+1)
+		allowed[StrictParamsQueryKey] = true
+		for _, key := range known {
+			allowed[key] = true
+		}
+		unknown := []string{}
+		for key := range query {
+			if !allowed[key] {
+				unknown = append(unknown, key)
+			}
+		}
+		if len(unknown) == 0 {
+			c.Next()
+			return
+		}
+
+		sort.Strings(unknown)
+		accepted := append([]string{}, known...)
+		sort.Strings(accepted)
+		apiErr := newAPIError(models.ErrCodeValidation,
+			fmt.Sprintf("unknown query parameter(s): %s", strings.Join(unknown, ", ")))
+		apiErr.Details = map[string]interface{}{
+			"unknown_params":  unknown,
+			"accepted_params": accepted,
+		}
+		RespondError(c, http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Message: "Unknown query parameters",
+			Error:   apiErr,
+		})
+	}
+}
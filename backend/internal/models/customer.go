@@ -34,3 +34,23 @@ type CustomerInput struct {
 	KYCVerifiedDate *string `json:"kyc_verified_date"` // YYYY-MM-DD format
 }
 
+// DuplicatePhoneGroup is a phone number shared by loans belonging to more
+// than one distinct customer_id (possible duplicate customers or fraud).
+type DuplicatePhoneGroup struct {
+	CustomerPhone string                `json:"customer_phone"`
+	CustomerIDs   []string              `json:"customer_ids"`
+	OfficerIDs    []string              `json:"officer_ids"`
+	LoanCount     int                   `json:"loan_count"`
+	Loans         []*DuplicatePhoneLoan `json:"loans"`
+}
+
+// DuplicatePhoneLoan is a loan involved in a DuplicatePhoneGroup
+type DuplicatePhoneLoan struct {
+	LoanID           string  `json:"loan_id"`
+	CustomerID       string  `json:"customer_id"`
+	CustomerName     string  `json:"customer_name"`
+	OfficerID        string  `json:"officer_id"`
+	OfficerName      string  `json:"officer_name"`
+	Status           string  `json:"status"`
+	DisbursementDate *string `json:"disbursement_date"`
+}
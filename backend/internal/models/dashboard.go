@@ -28,19 +28,38 @@ type PortfolioMetrics struct {
 	EarlyROTVolume float64 `json:"earlyROTVolume"`
 	LateROTCount   int     `json:"lateROTCount"`
 	LateROTVolume  float64 `json:"lateROTVolume"`
-
-	// Portfolio Delinquency Risk
+	// Loans with a missing disbursement_date cannot be aged into early/late
+	// ROT, so they are reported separately.
+	UnknownAgeROTCount           int     `json:"unknownAgeROTCount"`
+	UnknownAgeROTVolume          float64 `json:"unknownAgeROTVolume"`
+	MissingDisbursementDateCount int     `json:"missingDisbursementDateCount"`
+
+	// Portfolio Delinquency Risk. AtRiskOfficersRule names the configured
+	// definition of an at-risk officer (activity, risk_band or par15).
 	AtRiskOfficersCount      int     `json:"atRiskOfficersCount"`
 	AtRiskOfficersPercentage float64 `json:"atRiskOfficersPercentage"`
+	AtRiskOfficersRule       string  `json:"atRiskOfficersRule"`
 
 	// Portfolio Repayment Behavior Metrics
 	AvgDaysPastDue        float64 `json:"avgDaysPastDue"`
+	WeightedAvgDPD        float64 `json:"weightedAvgDPD"` // current_dpd weighted by actual_outstanding
 	AvgTimelinessScore    float64 `json:"avgTimelinessScore"`
+	AvgRepaymentHealth    float64 `json:"avgRepaymentHealth"`
 	AvgRepaymentDelayRate float64 `json:"avgRepaymentDelayRate"`
 
+	// Soft scores weighted by actual_outstanding across all active loans, and
+	// how many active loans have no score (i.e. what the averages don't cover)
+	WeightedAvgTimelinessScore  float64 `json:"weightedAvgTimelinessScore"`
+	WeightedAvgRepaymentHealth  float64 `json:"weightedAvgRepaymentHealth"`
+	MissingTimelinessScoreCount int     `json:"missingTimelinessScoreCount"`
+	MissingRepaymentHealthCount int     `json:"missingRepaymentHealthCount"`
+
 	// Total DPD Loans (current_dpd > 0 AND status in Active/Defaulted)
 	TotalDPDLoansCount        int     `json:"totalDPDLoansCount"`
 	TotalDPDActualOutstanding float64 `json:"totalDPDActualOutstanding"`
+
+	// Officers left out of all metrics by DASHBOARD_EXCLUDED_OFFICER_IDS
+	ExcludedOfficerCount int `json:"excludedOfficerCount"`
 }
 
 type TopOfficer struct {
@@ -65,8 +84,21 @@ type PortfolioLoanMetrics struct {
 	EarlyROTVolume      float64 `json:"earlyROTVolume"`
 	LateROTCount        int     `json:"lateROTCount"`
 	LateROTVolume       float64 `json:"lateROTVolume"`
+	UnknownAgeROTCount  int     `json:"unknownAgeROTCount"`
+	UnknownAgeROTVolume float64 `json:"unknownAgeROTVolume"`
 	AvgDaysPastDue      float64 `json:"avgDaysPastDue"`
+	WeightedAvgDPD      float64 `json:"weightedAvgDPD"` // current_dpd weighted by actual_outstanding
 	AvgTimelinessScore  float64 `json:"avgTimelinessScore"`
+	AvgRepaymentHealth  float64 `json:"avgRepaymentHealth"`
+
+	// Active loans with no disbursement_date (e.g. partially synced)
+	MissingDisbursementDateCount int `json:"missingDisbursementDateCount"`
+
+	// Outstanding-weighted soft scores over all active loans
+	WeightedAvgTimelinessScore  float64 `json:"weightedAvgTimelinessScore"`
+	WeightedAvgRepaymentHealth  float64 `json:"weightedAvgRepaymentHealth"`
+	MissingTimelinessScoreCount int     `json:"missingTimelinessScoreCount"`
+	MissingRepaymentHealthCount int     `json:"missingRepaymentHealthCount"`
 }
 
 // DashboardOfficerMetrics represents an officer with all calculated metrics for dashboard
@@ -80,10 +112,14 @@ type DashboardOfficerMetrics struct {
 	Channel           string             `json:"channel"`
 	UserType          *string            `json:"user_type,omitempty"`
 	HireDate          *time.Time         `json:"hire_date,omitempty"`
+	TenureBucket      string             `json:"tenure_bucket"` // <3mo, 3-6mo, 6-12mo, 1y+ or unknown
 	SupervisorEmail   *string            `json:"supervisor_email,omitempty"`
 	SupervisorName    *string            `json:"supervisor_name,omitempty"`
 	VerticalLeadEmail *string            `json:"vertical_lead_email,omitempty"`
 	VerticalLeadName  *string            `json:"vertical_lead_name,omitempty"`
+	AuditStatus       *string            `json:"audit_status,omitempty"` // current audit assignment; set by GetOfficerByID only
+	AssigneeName      *string            `json:"assignee_name,omitempty"`
+	AuditDate         *string            `json:"audit_date,omitempty"` // YYYY-MM-DD
 	RawMetrics        *RawMetrics        `json:"rawMetrics"`
 	CalculatedMetrics *CalculatedMetrics `json:"calculatedMetrics"`
 	RiskBand          string             `json:"riskBand"`
@@ -135,6 +171,67 @@ type CalculatedMetrics struct {
 	RepaymentDelayRate        float64 `json:"repaymentDelayRate"`
 }
 
+// MetricBreakdown explains a single calculated metric: the formula, the raw
+// inputs it was computed from and any intermediate terms.
+type MetricBreakdown struct {
+	Metric  string             `json:"metric"`
+	Value   float64            `json:"value"`
+	Formula string             `json:"formula"`
+	Inputs  map[string]float64 `json:"inputs"`
+	Terms   map[string]float64 `json:"terms,omitempty"`
+}
+
+// RiskWeights are the maximum penalties (0-1) the risk score deducts for
+// each component; a perfect officer scores 1 and every penalty is the
+// component's badness scaled by its weight.
+type RiskWeights struct {
+	PORR               float64 `json:"porr"`
+	FIMR               float64 `json:"fimr"`
+	Roll               float64 `json:"roll"`
+	RepaymentDelayRate float64 `json:"repayment_delay_rate"`
+	AYR                float64 `json:"ayr"`
+}
+
+// RiskWeightsOverride is the payload of POST
+// /officers/:officer_id/simulate-risk. Omitted weights keep their default.
+type RiskWeightsOverride struct {
+	PORR               *float64 `json:"porr"`
+	FIMR               *float64 `json:"fimr"`
+	Roll               *float64 `json:"roll"`
+	RepaymentDelayRate *float64 `json:"repayment_delay_rate"`
+	AYR                *float64 `json:"ayr"`
+}
+
+// RiskScenario is an officer's calculated metrics and risk band under one set
+// of risk weights.
+type RiskScenario struct {
+	RiskBand          string             `json:"risk_band"`
+	CalculatedMetrics *CalculatedMetrics `json:"calculated_metrics"`
+}
+
+// OfficerRiskSimulation compares an officer's metrics under the current risk
+// weights with a proposed weighting. Nothing is persisted.
+type OfficerRiskSimulation struct {
+	OfficerID      string        `json:"officer_id"`
+	Name           string        `json:"name"`
+	DefaultWeights RiskWeights   `json:"default_weights"`
+	Weights        RiskWeights   `json:"weights"`
+	Current        *RiskScenario `json:"current"`
+	Simulated      *RiskScenario `json:"simulated"`
+	BandChanged    bool          `json:"band_changed"`
+}
+
+// OfficerMetricsBreakdown is an officer's calculated metrics together with
+// how each was derived from their raw metrics.
+type OfficerMetricsBreakdown struct {
+	OfficerID         string             `json:"officer_id"`
+	Name              string             `json:"name"`
+	RiskBand          string             `json:"risk_band"`
+	RawMetrics        *RawMetrics        `json:"raw_metrics"`
+	CalculatedMetrics *CalculatedMetrics `json:"calculated_metrics"`
+	Breakdown         []*MetricBreakdown `json:"breakdown"`
+}
+
 // FIMRLoan represents a loan that missed first installment
 type FIMRLoan struct {
 	LoanID                   string  `json:"loan_id"`
@@ -161,6 +258,29 @@ type FIMRLoan struct {
 	Status                   string  `json:"status"`
 	DjangoStatus             string  `json:"django_status,omitempty"`
 	FIMRTagged               bool    `json:"fimr_tagged"`
+	FIMRRecovered            bool    `json:"fimr_recovered"` // first payment late, but repaying again with low DPD
+}
+
+// UpcomingFIMRLoan is a loan whose first payment is due soon (or due but
+// still within the FIMR grace period) and hasn't been received, so the
+// customer can be called before the loan becomes a first installment miss.
+type UpcomingFIMRLoan struct {
+	LoanID                  string  `json:"loan_id"`
+	OfficerID               string  `json:"officer_id"`
+	OfficerName             string  `json:"officer_name"`
+	OfficerPhone            string  `json:"officer_phone"`
+	Region                  string  `json:"region"`
+	Branch                  string  `json:"branch"`
+	CustomerID              string  `json:"customer_id"`
+	CustomerName            string  `json:"customer_name"`
+	CustomerPhone           string  `json:"customer_phone"`
+	DisbursementDate        string  `json:"disbursement_date"`
+	LoanAmount              float64 `json:"loan_amount"`
+	FirstPaymentDueDate     string  `json:"first_payment_due_date"`
+	DaysUntilDue            int     `json:"days_until_due"`             // negative once due, while still within the grace period
+	AmountDue1stInstallment float64 `json:"amount_due_1st_installment"` // the loan's daily repayment (see dailyRepaymentSQL)
+	Channel                 string  `json:"channel"`
+	DjangoStatus            string  `json:"django_status"`
 }
 
 // EarlyIndicatorLoan represents a loan in early delinquency
@@ -188,6 +308,28 @@ type EarlyIndicatorLoan struct {
 	LastPaymentDate     string  `json:"last_payment_date"`
 }
 
+// EarlyIndicatorBreakdown is the loan count and exposure of one DPD sub-band
+// or roll direction among early-indicator loans
+type EarlyIndicatorBreakdown struct {
+	Key              string  `json:"key"`
+	LoanCount        int     `json:"loan_count"`
+	TotalAmount      float64 `json:"total_amount"`
+	TotalOutstanding float64 `json:"total_outstanding"`
+}
+
+// EarlyIndicatorSummaryMetrics aggregates early-indicator loans (DPD 1-30)
+// overall, per DPD sub-band (D1-3, D4-6, D7-15, D16-30) and per roll direction
+type EarlyIndicatorSummaryMetrics struct {
+	TotalLoans       int                        `json:"total_loans"`
+	TotalAmount      float64                    `json:"total_amount"`
+	TotalOutstanding float64                    `json:"total_outstanding"`
+	Worsening        int                        `json:"worsening"`
+	Stable           int                        `json:"stable"`
+	Improving        int                        `json:"improving"`
+	ByBand           []*EarlyIndicatorBreakdown `json:"by_band"`
+	ByRollDirection  []*EarlyIndicatorBreakdown `json:"by_roll_direction"`
+}
+
 // DashboardBranchMetrics represents branch-level aggregated metrics for dashboard
 type DashboardBranchMetrics struct {
 	Branch                string  `json:"branch"`
@@ -224,6 +366,46 @@ type VerticalLeadMetricsRow struct {
 	QuietValue        float64 `json:"quiet_value"`
 }
 
+// DPDBucketTotals holds the loan count and outstanding balance for a single
+// DPD bucket.
+type DPDBucketTotals struct {
+	Count       int     `json:"count"`
+	Outstanding float64 `json:"outstanding"`
+}
+
+// BranchDPDMatrixRow represents one branch in the branch x DPD bucket aging
+// report. Buckets are mutually exclusive and together add up to Total.
+type BranchDPDMatrixRow struct {
+	Branch    string          `json:"branch"`
+	Region    string          `json:"region"`
+	Current   DPDBucketTotals `json:"current"`
+	DPD1to6   DPDBucketTotals `json:"dpd1_6"`
+	DPD7to14  DPDBucketTotals `json:"dpd7_14"`
+	DPD15to30 DPDBucketTotals `json:"dpd15_30"`
+	DPD31to60 DPDBucketTotals `json:"dpd31_60"`
+	DPD61to90 DPDBucketTotals `json:"dpd61_90"`
+	DPD90Plus DPDBucketTotals `json:"dpd90_plus"`
+	Total     DPDBucketTotals `json:"total"`
+}
+
+// Add accumulates another row's buckets into r, used to build a grand total.
+func (r *BranchDPDMatrixRow) Add(other *BranchDPDMatrixRow) {
+	pairs := []struct{ dst, src *DPDBucketTotals }{
+		{&r.Current, &other.Current},
+		{&r.DPD1to6, &other.DPD1to6},
+		{&r.DPD7to14, &other.DPD7to14},
+		{&r.DPD15to30, &other.DPD15to30},
+		{&r.DPD31to60, &other.DPD31to60},
+		{&r.DPD61to90, &other.DPD61to90},
+		{&r.DPD90Plus, &other.DPD90Plus},
+		{&r.Total, &other.Total},
+	}
+	for _, p := range pairs {
+		p.dst.Count += p.src.Count
+		p.dst.Outstanding += p.src.Outstanding
+	}
+}
+
 // BranchCollectionsLeaderboardRow represents per-branch collections metrics for the
 // Collections Control Centre "Branch Leaderboard" table.
 type BranchCollectionsLeaderboardRow struct {
@@ -250,6 +432,7 @@ type OfficerCollectionsLeaderboardRow struct {
 	OfficerEmail   string  `json:"officer_email"`
 	Branch         string  `json:"branch"`
 	Region         string  `json:"region"`
+	LoanCount      int     `json:"loan_count"`
 	PortfolioTotal float64 `json:"portfolio_total"`
 	Overdue15d     float64 `json:"overdue_15d"`
 	DueToday       float64 `json:"due_today"`
@@ -260,6 +443,7 @@ type OfficerCollectionsLeaderboardRow struct {
 	MissedToday    float64 `json:"missed_today"`
 	NPLRatio       float64 `json:"npl_ratio"`
 	Status         string  `json:"status"`
+	Rank           int     `json:"rank"` // 1-based position by the requested rank_by metric
 }
 
 // RepaymentWatchOfficerRow represents per-officer Wave 2 repayment performance for the
@@ -307,20 +491,326 @@ type AgentActivityDetailRow struct {
 	TotalCollected      float64 `json:"total_collected"`
 }
 
-// DailyCollectionsPoint represents a single day in the collections time series
-// used by the Collections Control Centre daily chart.
-type DailyCollectionsPoint struct {
+// CollectionHeatmapCell represents one day in the day-of-week x week
+// collections heatmap
+type CollectionHeatmapCell struct {
+	WeekStart       string  `json:"week_start"`  // Monday of the ISO week (YYYY-MM-DD)
+	DayOfWeek       int     `json:"day_of_week"` // ISO day of week: 1 = Monday ... 7 = Sunday
 	Date            string  `json:"date"`
 	CollectedAmount float64 `json:"collected_amount"`
 	RepaymentsCount int     `json:"repayments_count"`
+	PartialWeek     bool    `json:"partial_week"` // Day falls in the current, still-running week
+}
+
+// CollectionsProgress compares what was collected in a period with what the
+// filtered loans were expected to pay over the same period. Expected is each
+// loan's daily repayment times the business days (Mon-Fri, excluding
+// company-wide holidays) the loan was due within the period: from the later
+// of the period start and first_payment_due_date, to the earliest of the
+// period end, maturity_date and closed_date.
+type CollectionsProgress struct {
+	Period          string  `json:"period"`
+	PeriodStart     string  `json:"period_start"`
+	PeriodEnd       string  `json:"period_end"`
+	BusinessDays    int     `json:"business_days"`
+	LoansExpected   int     `json:"loans_expected"` // loans with at least one due business day in the period
+	ExpectedAmount  float64 `json:"expected_amount"`
+	CollectedAmount float64 `json:"collected_amount"`
+	ProgressRatio   float64 `json:"progress_ratio"` // collected / expected; 0 when nothing was expected
+}
+
+// PeriodAggregates holds the headline aggregates for one period of a
+// period comparison.
+type PeriodAggregates struct {
+	Period          string  `json:"period"`
+	PeriodStart     string  `json:"period_start"`
+	PeriodEnd       string  `json:"period_end"`
+	CollectedAmount float64 `json:"collected_amount"`
+	RepaymentsCount int     `json:"repayments_count"`
+	DisbursedAmount float64 `json:"disbursed_amount"`
+	DisbursedCount  int     `json:"disbursed_count"`
+	// PAR15 of the loans disbursed in the period, as of today (there are no
+	// historical balance snapshots to compute PAR at the period end)
+	PAR15Ratio float64 `json:"par15_ratio"`
+}
+
+// ConcentrationEntity is one officer or branch in a portfolio concentration
+// ranking.
+type ConcentrationEntity struct {
+	Rank        int     `json:"rank"`
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Outstanding float64 `json:"outstanding"`
+	Share       float64 `json:"share"` // of the filtered portfolio's outstanding (0-1)
+}
+
+// PortfolioConcentration reports how concentrated outstanding is across
+// officers or branches.
+type PortfolioConcentration struct {
+	By               string                 `json:"by"` // officer or branch
+	Top              int                    `json:"top"`
+	TotalOutstanding float64                `json:"total_outstanding"`
+	EntityCount      int                    `json:"entity_count"`
+	TopShare         float64                `json:"top_share"` // combined share of the returned entities (0-1)
+	HHI              float64                `json:"hhi"`       // sum of squared shares over all entities (0-1]
+	Entities         []*ConcentrationEntity `json:"entities"`
+}
+
+// LoanTermBucket holds the loan count, outstanding and delinquency of the
+// filtered loans whose loan_term_days falls in one tenor bucket.
+type LoanTermBucket struct {
+	Bucket               string  `json:"bucket"`   // e.g. "<=30d", "31-90d", "91d+"; MissingValueSentinel for loans with no (positive) term
+	MinDays              *int    `json:"min_days"` // inclusive; null for the missing-term bucket
+	MaxDays              *int    `json:"max_days"` // inclusive; null for the open-ended last bucket
+	LoansCount           int     `json:"loans_count"`
+	PrincipalOutstanding Money   `json:"principal_outstanding"`
+	Overdue15d           Money   `json:"overdue_15d"`
+	Overdue15dCount      int     `json:"overdue_15d_count"`
+	PAR15Ratio           float64 `json:"par15_ratio"` // overdue_15d / principal_outstanding
+}
+
+// DelayRateBin holds the number of filtered loans whose repayment_delay_rate
+// falls in one equal-width histogram bin.
+type DelayRateBin struct {
+	Bin        string  `json:"bin"`      // e.g. "0-10", "90-100"
+	MinRate    float64 `json:"min_rate"` // inclusive
+	MaxRate    float64 `json:"max_rate"` // exclusive, except the last bin which includes 100
+	LoansCount int     `json:"loans_count"`
+}
+
+// RepaymentDelayHistogram is the distribution of repayment_delay_rate across
+// the filtered loans. Loans with no delay rate are left out; rates outside
+// 0-100 are counted separately rather than folded into the end bins.
+type RepaymentDelayHistogram struct {
+	Bins            []*DelayRateBin `json:"bins"`
+	BelowRangeCount int             `json:"below_range_count"` // repayment_delay_rate < 0
+	AboveRangeCount int             `json:"above_range_count"` // repayment_delay_rate > 100
+	TotalLoans      int             `json:"total_loans"`       // loans with a delay rate, including out-of-range ones
+}
+
+// MetricsRollupRow holds the headline portfolio and collection metrics for
+// one value of a GET /metrics/rollup dimension.
+type MetricsRollupRow struct {
+	Key                  string   `json:"key"`  // dimension value; MissingValueSentinel when unset
+	Name                 string   `json:"name"` // display name (officer or vertical lead name, else Key)
+	ActiveLoans          int      `json:"active_loans"`
+	PortfolioTotal       float64  `json:"portfolio_total"`
+	PrincipalOutstanding float64  `json:"principal_outstanding"`
+	Overdue15d           float64  `json:"overdue_15d"`
+	PAR15Ratio           float64  `json:"par15_ratio"` // overdue_15d / principal_outstanding
+	DueToday             float64  `json:"due_today"`
+	CollectedToday       float64  `json:"collected_today"`
+	CollectionRateToday  *float64 `json:"collection_rate_today"` // collected_today / due_today; null when nothing is due
+}
+
+// MetricsRollup is the filtered portfolio grouped by a single dimension.
+type MetricsRollup struct {
+	Dimension string              `json:"dimension"`
+	Rows      []*MetricsRollupRow `json:"rows"`
+}
+
+// NonBusinessDayRepayment is a non-reversed repayment dated on a weekend or
+// holiday, listed for backdating/reconciliation audits.
+type NonBusinessDayRepayment struct {
+	RepaymentID    string  `json:"repayment_id"`
+	LoanID         string  `json:"loan_id"`
+	CustomerName   string  `json:"customer_name"`
+	OfficerID      string  `json:"officer_id"`
+	OfficerName    string  `json:"officer_name"`
+	Branch         string  `json:"branch"`
+	Region         string  `json:"region"`
+	PaymentDate    string  `json:"payment_date"`
+	PaymentAmount  float64 `json:"payment_amount"`
+	PaymentMethod  string  `json:"payment_method"`
+	PaymentChannel *string `json:"payment_channel,omitempty"`
+	IsBackdated    bool    `json:"is_backdated"`
+	IsReversed     bool    `json:"is_reversed"` // only true with include_reversed
+	DayType        string  `json:"day_type"`    // saturday, sunday or holiday
+	HolidayName    *string `json:"holiday_name,omitempty"`
+}
+
+// Holiday sources reported in Holiday.Source
+const (
+	HolidaySourceDjango = "django" // synced from Django's loans_holiday
+	HolidaySourceAPI    = "api"    // added through POST /api/v1/holidays
+)
+
+// Holiday is a company-wide non-working day, excluded from business-day
+// counts in collection rates.
+type Holiday struct {
+	Date      string    `json:"date"` // YYYY-MM-DD
+	Name      string    `json:"name"`
+	Source    string    `json:"source"` // django or api
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LoanReviewFlagInput is the payload for flagging loans for manual review.
+// Loans already flagged have their note and assignee replaced.
+type LoanReviewFlagInput struct {
+	LoanIDs  []string `json:"loan_ids" binding:"required"`
+	Note     string   `json:"note"`
+	Assignee string   `json:"assignee"`
+}
+
+// LoanReviewFlag is a loan on the manual review worklist with the loan
+// details collections needs to follow it up.
+type LoanReviewFlag struct {
+	LoanID            string    `json:"loan_id"`
+	Note              string    `json:"note"`
+	Assignee          string    `json:"assignee"`
+	FlaggedAt         time.Time `json:"flagged_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	CustomerName      string    `json:"customer_name"`
+	CustomerPhone     string    `json:"customer_phone"`
+	OfficerID         string    `json:"officer_id"`
+	OfficerName       string    `json:"officer_name"`
+	Branch            string    `json:"branch"`
+	Region            string    `json:"region"`
+	DjangoStatus      string    `json:"django_status"`
+	CurrentDPD        int       `json:"current_dpd"`
+	ActualOutstanding float64   `json:"actual_outstanding"`
+}
+
+// HolidayInput is the payload for creating or renaming a holiday
+type HolidayInput struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name" binding:"required"`
+}
+
+// MetricDelta is the change of one aggregate from period_b to period_a.
+type MetricDelta struct {
+	Delta     float64  `json:"delta"`      // period_a - period_b
+	PctChange *float64 `json:"pct_change"` // delta / period_b * 100; null when period_b is 0
+}
+
+// PeriodComparison compares the headline aggregates of two periods.
+type PeriodComparison struct {
+	PeriodA *PeriodAggregates      `json:"period_a"`
+	PeriodB *PeriodAggregates      `json:"period_b"`
+	Deltas  map[string]MetricDelta `json:"deltas"`
+}
+
+// OfficerCollectionMethod represents an officer's collections for a period
+// through a single normalised payment method (AGENT_DEBIT, TRANSFER,
+// ESCROW_DEBIT or OTHER).
+type OfficerCollectionMethod struct {
+	Method          string  `json:"method"`
+	Amount          float64 `json:"amount"`
+	RepaymentsCount int     `json:"repayments_count"`
+	SharePct        float64 `json:"share_pct"` // Percentage of the officer's total collections
+}
+
+// ChannelCollection represents collections for a period from loans
+// originated through a single channel (AGENT, MERCHANT, ...).
+type ChannelCollection struct {
+	Channel         string  `json:"channel"`
+	Amount          Money   `json:"amount"`
+	RepaymentsCount int     `json:"repayments_count"`
+	LoansCount      int     `json:"loans_count"`
+	SharePct        float64 `json:"share_pct"` // Percentage of total collections in the period
+}
+
+// UserTypeCollections summarizes today's collections performance for loans
+// booked by officers of a single user_type (AGENT, MERCHANT, AJO_AGENT, ...).
+type UserTypeCollections struct {
+	UserType       string  `json:"user_type"`
+	LoansCount     int     `json:"loans_count"`
+	PortfolioTotal Money   `json:"portfolio_total"`
+	Overdue15d     Money   `json:"overdue_15d"`
+	DueToday       Money   `json:"due_today"`
+	CollectedToday Money   `json:"collected_today"`
+	TodayRate      float64 `json:"today_rate"` // collected_today / due_today
+	NPLRatio       float64 `json:"npl_ratio"`  // overdue_15d / portfolio_total
+}
+
+// LoanCollectionMethod is a loan's lifetime collections through a single
+// normalised payment method (AGENT_DEBIT, TRANSFER, ESCROW_DEBIT or OTHER).
+type LoanCollectionMethod struct {
+	Method          string  `json:"method"`
+	Amount          Money   `json:"amount"`
+	RepaymentsCount int     `json:"repayments_count"`
+	SharePct        float64 `json:"share_pct"`         // Percentage of the loan's total collected
+	LastPaymentDate *string `json:"last_payment_date"` // nil when never paid through this method
+}
+
+// LoanCollectionSummary breaks down everything collected on a loan by payment
+// method, from non-reversed repayments.
+type LoanCollectionSummary struct {
+	LoanID           string                  `json:"loan_id"`
+	TotalCollected   Money                   `json:"total_collected"`
+	RepaymentsCount  int                     `json:"repayments_count"`
+	FirstPaymentDate *string                 `json:"first_payment_date"`
+	LastPaymentDate  *string                 `json:"last_payment_date"`
+	Methods          []*LoanCollectionMethod `json:"methods"`
+}
+
+// DailyCollectionsPoint represents a single day in the collections time series
+// used by the Collections Control Centre daily chart. Amounts are Money so they
+// reconcile exactly with the finance ledger.
+type DailyCollectionsPoint struct {
+	Date            string `json:"date"`
+	CollectedAmount Money  `json:"collected_amount"`
+	RepaymentsCount int    `json:"repayments_count"`
+
+	// Breadth of the day's collections: how many distinct loans and distinct
+	// customers the RepaymentsCount payments came from.
+	DistinctLoansPaid     int `json:"distinct_loans_paid"`
+	DistinctCustomersPaid int `json:"distinct_customers_paid"`
 
 	// Repayment type breakdown for the day. These amounts always sum to
 	// CollectedAmount and are grouped using normalised payment_method values
 	// (e.g. AGENT_DEBIT, TRANSFER, ESCROW_DEBIT; everything else is "other").
-	AgentDebitAmount      float64 `json:"agent_debit_amount"`
-	TransferAmount        float64 `json:"transfer_amount"`
-	EscrowDebitAmount     float64 `json:"escrow_debit_amount"`
-	OtherRepaymentsAmount float64 `json:"other_repayments_amount"`
+	AgentDebitAmount      Money `json:"agent_debit_amount"`
+	TransferAmount        Money `json:"transfer_amount"`
+	EscrowDebitAmount     Money `json:"escrow_debit_amount"`
+	OtherRepaymentsAmount Money `json:"other_repayments_amount"`
+
+	// Expected repayments for the day and the shortfall against them
+	// (max(0, DueAmount - CollectedAmount)). DueAmount is approximated from the
+	// loans' current daily_repayment_amount; see GetDailyCollections.
+	DueAmount    Money `json:"due_amount"`
+	MissedAmount Money `json:"missed_amount"`
+
+	// Reversed repayments within CollectedAmount; only set with
+	// include_reversed, when the collected amounts are gross of reversals.
+	ReversedAmount *Money `json:"reversed_amount,omitempty"`
+	ReversedCount  *int   `json:"reversed_count,omitempty"`
+}
+
+// LoanRecalculationResult reports which steps of the loan field recalculation
+// ran and how many rows each step affected. It is stored as the result of the
+// loan_recalculation sync job and updated as each step finishes.
+type LoanRecalculationResult struct {
+	RecalculationRan  bool  `json:"recalculation_ran"`
+	LoansRecalculated int64 `json:"loans_recalculated"`
+	FIMRRecomputeRan  bool  `json:"fimr_recompute_ran"`
+	FIMRTagsChanged   int64 `json:"fimr_tags_changed"`
+	NormalizationRan  bool  `json:"normalization_ran"`
+	LoansNormalized   int64 `json:"loans_normalized"`
+	SnapshotsRan      bool  `json:"snapshots_ran"`
+	OfficersCaptured  int64 `json:"officers_captured"`
+}
+
+// OfficerSnapshot is an officer's portfolio, overdue and collection figures
+// as captured on SnapshotDate (see officer_snapshots). Portfolio figures cover
+// loans that were open on that date; CollectedMTD runs from the start of that
+// month to SnapshotDate.
+type OfficerSnapshot struct {
+	OfficerID            string    `json:"officer_id"`
+	OfficerName          string    `json:"officer_name"`
+	SnapshotDate         string    `json:"snapshot_date"`
+	ActiveLoans          int       `json:"active_loans"`
+	PortfolioTotal       Money     `json:"portfolio_total"`
+	TotalOutstanding     Money     `json:"total_outstanding"`
+	PrincipalOutstanding Money     `json:"principal_outstanding"`
+	Overdue15d           Money     `json:"overdue_15d"`
+	PAR15Ratio           float64   `json:"par15_ratio"`
+	LoansInArrears       int       `json:"loans_in_arrears"`
+	DueToday             Money     `json:"due_today"`
+	CollectedToday       Money     `json:"collected_today"`
+	CollectedMTD         Money     `json:"collected_mtd"`
+	CapturedAt           time.Time `json:"captured_at"`
 }
 
 // TeamMember represents a team member for audit assignment
@@ -334,7 +824,30 @@ type TeamMember struct {
 type AuditUpdate struct {
 	AssigneeID   int    `json:"assignee_id"`
 	AssigneeName string `json:"assignee_name"`
-	AuditStatus  string `json:"audit_status"`
+	AuditStatus  string `json:"audit_status"` // one of AuditStatuses
+}
+
+// Audit statuses accepted by PUT /officers/:officer_id/audit. They are the
+// values the dashboard's audit status dropdown sends; migration 051 maps the
+// legacy lowercase values onto them.
+const (
+	AuditStatusUnassigned = "Unassigned"
+	AuditStatusAssigned   = "Assigned"
+	AuditStatusInProgress = "In Progress"
+	AuditStatusResolved   = "Resolved"
+)
+
+// AuditStatuses lists the valid audit_status values in workflow order.
+var AuditStatuses = []string{AuditStatusUnassigned, AuditStatusAssigned, AuditStatusInProgress, AuditStatusResolved}
+
+// IsValidAuditStatus reports whether status is one of AuditStatuses.
+func IsValidAuditStatus(status string) bool {
+	for _, s := range AuditStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
 }
 
 // AuditHistory represents audit history for an officer
@@ -373,6 +886,12 @@ type OfficerOption struct {
 	Region    string  `json:"region"`
 }
 
+// SupervisorOption represents a supervisor in the org filter dropdown
+type SupervisorOption struct {
+	Email string  `json:"email"`
+	Name  *string `json:"name,omitempty"`
+}
+
 // LoanDetail represents detailed loan information
 type LoanDetail struct {
 	Loan       *Loan              `json:"loan"`
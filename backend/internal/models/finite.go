@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+)
+
+// MarshalJSON encodes the response with any NaN or ±Inf float in Data
+// replaced by 0. encoding/json rejects non-finite floats, so a single bad
+// ratio would otherwise fail the whole response.
+func (r APIResponse) MarshalJSON() ([]byte, error) {
+	type plain APIResponse
+	r.Data = finiteFloats(r.Data)
+	return json.Marshal(plain(r))
+}
+
+// finiteFloats returns v with every non-finite float it reaches (through
+// pointers, interfaces, exported struct fields, slices, arrays and maps) set
+// to 0. Values behind pointers are fixed in place.
+func finiteFloats(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	c := reflect.New(rv.Type()).Elem()
+	c.Set(rv)
+	zeroNonFinite(c)
+	return c.Interface()
+}
+
+// zeroNonFinite walks v, which must be settable, zeroing non-finite floats.
+func zeroNonFinite(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			v.SetFloat(0)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			zeroNonFinite(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		e := v.Elem()
+		if e.Kind() == reflect.Ptr {
+			zeroNonFinite(e)
+			return
+		}
+		c := reflect.New(e.Type()).Elem()
+		c.Set(e)
+		zeroNonFinite(c)
+		v.Set(c)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				zeroNonFinite(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			zeroNonFinite(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		for _, k := range v.MapKeys() {
+			c := reflect.New(v.Type().Elem()).Elem()
+			c.Set(v.MapIndex(k))
+			zeroNonFinite(c)
+			v.SetMapIndex(k, c)
+		}
+	}
+}
@@ -166,6 +166,7 @@ type AllLoan struct {
 	TimelinessScore               *float64 `json:"timeliness_score"`
 	RepaymentHealth               *float64 `json:"repayment_health"`
 	DaysSinceLastRepayment        *int     `json:"days_since_last_repayment"`
+	LastPaymentDate               *string  `json:"last_payment_date"` // YYYY-MM-DD of the latest non-reversed repayment
 	RepaymentDelayRate            *float64 `json:"repayment_delay_rate"`
 	Wave                          string   `json:"wave"`
 	DailyRepaymentAmount          *float64 `json:"daily_repayment_amount,omitempty"`
@@ -176,11 +177,33 @@ type AllLoan struct {
 	VerificationStatus            *string  `json:"verification_status,omitempty"`
 	DjangoStatus                  *string  `json:"django_status,omitempty"`
 	RepaymentsToday               *float64 `json:"repayments_today,omitempty"`
+	CollectionRateToday           *float64 `json:"collection_rate_today"` // repayments_today / daily_repayment_amount; null when nothing is expected
+	MissedToday                   bool     `json:"missed_today"`
+}
+
+// LoanStatusCount represents the number of loans and their outstanding
+// balance for a single status value
+type LoanStatusCount struct {
+	Status      string  `json:"status"`
+	Count       int     `json:"count"`
+	Outstanding float64 `json:"outstanding"`
+}
+
+// VerificationStatusCount represents the number of loans and their
+// outstanding balance for a single verification_status value
+type VerificationStatusCount struct {
+	VerificationStatus string  `json:"verification_status"`
+	Count              int     `json:"count"`
+	Outstanding        float64 `json:"outstanding"`
 }
 
 // TopRiskLoan represents a high-risk loan for audit purposes
 type TopRiskLoan struct {
 	LoanID                string  `json:"loan_id"`
+	OfficerID             string  `json:"officer_id,omitempty"`
+	OfficerName           string  `json:"officer_name,omitempty"`
+	Branch                string  `json:"branch,omitempty"`
+	Region                string  `json:"region,omitempty"`
 	CustomerName          string  `json:"customer_name"`
 	CustomerPhone         string  `json:"customer_phone"`
 	LoanAmount            float64 `json:"loan_amount"`
@@ -196,5 +219,38 @@ type TopRiskLoan struct {
 	RiskScore             float64 `json:"risk_score"`
 	RiskCategory          string  `json:"risk_category"`
 	Channel               string  `json:"channel"`
-	DaysSinceDisbursement int     `json:"days_since_disbursement"`
+	DaysSinceDisbursement *int    `json:"days_since_disbursement"` // nil when disbursement_date is missing
+}
+
+// MultiFlagSignals selects the risk signals a loan must carry to appear in
+// the multi-flagged triage list. Selected signals are combined with AND.
+type MultiFlagSignals struct {
+	FIMR         bool `json:"fimr"`          // tagged first-installment-missed
+	DPDOver      *int `json:"dpd_over"`      // current_dpd strictly above this; nil when not selected
+	Quiet        bool `json:"quiet"`         // no repayment in the last 6 days (or never repaid)
+	PastMaturity bool `json:"past_maturity"` // past maturity_date with a balance still owed
+}
+
+// Any reports whether at least one signal is selected.
+func (s MultiFlagSignals) Any() bool {
+	return s.FIMR || s.DPDOver != nil || s.Quiet || s.PastMaturity
+}
+
+// MultiFlaggedLoan is an open loan matching every selected MultiFlagSignals
+// signal, with the fields each signal is judged on.
+type MultiFlaggedLoan struct {
+	LoanID                 string  `json:"loan_id"`
+	CustomerName           string  `json:"customer_name"`
+	CustomerPhone          string  `json:"customer_phone"`
+	OfficerID              string  `json:"officer_id"`
+	OfficerName            string  `json:"officer_name"`
+	Branch                 string  `json:"branch"`
+	Region                 string  `json:"region"`
+	DjangoStatus           string  `json:"django_status"`
+	FIMRTagged             bool    `json:"fimr_tagged"`
+	CurrentDPD             int     `json:"current_dpd"`
+	DaysSinceLastRepayment *int    `json:"days_since_last_repayment"` // nil when the loan has never been repaid
+	MaturityDate           *string `json:"maturity_date"`
+	DaysPastMaturity       int     `json:"days_past_maturity"` // 0 when not yet matured
+	ActualOutstanding      float64 `json:"actual_outstanding"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// MoneyPlaces is the number of decimal places (kobo) money is reported to.
+const MoneyPlaces = 2
+
+// Money is an exact decimal amount for reconciliation-critical aggregates.
+// Postgres NUMERIC sums are scanned into it without passing through float64,
+// and arithmetic on it (e.g. due - collected) is exact.
+//
+// Money is rounded only when serialized: half away from zero (half-up for
+// positive amounts) to MoneyPlaces, written as a JSON number so responses keep
+// the same shape as the float64 fields they replace.
+type Money struct {
+	decimal.Decimal
+}
+
+// NewMoney wraps d as Money.
+func NewMoney(d decimal.Decimal) Money {
+	return Money{Decimal: d}
+}
+
+// MarshalJSON writes the amount as a JSON number rounded to MoneyPlaces.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal.StringFixed(MoneyPlaces)), nil
+}
@@ -66,3 +66,15 @@ type RepaymentFilter struct {
 	Offset         int        `json:"offset"`
 }
 
+// CustomerRepaymentTimelineEntry represents one repayment in a customer's
+// chronological repayment timeline across all of their loans
+type CustomerRepaymentTimelineEntry struct {
+	RepaymentID         string          `json:"repayment_id"`
+	LoanID              string          `json:"loan_id"`
+	PaymentDate         time.Time       `json:"payment_date"`
+	PaymentAmount       decimal.Decimal `json:"payment_amount"`
+	PaymentMethod       string          `json:"payment_method"`
+	PaymentChannel      *string         `json:"payment_channel,omitempty"`
+	IsReversed          bool            `json:"is_reversed"`           // only true with include_reversed
+	BalanceAfterPayment decimal.Decimal `json:"balance_after_payment"` // Loan balance once this and all earlier repayments are applied
+}
@@ -12,11 +12,22 @@ type APIResponse struct {
 
 // APIError represents an error response
 type APIError struct {
-	Code    string                 `json:"code"`
-	Message string                 `json:"message"`
-	Details map[string]interface{} `json:"details,omitempty"`
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"` // matches the X-Request-ID response header
 }
 
+// Error codes returned in APIError.Code so clients can branch on the cause
+// of a failure rather than parsing the message.
+const (
+	ErrCodeInternal      = "INTERNAL_ERROR"
+	ErrCodeDBTimeout     = "DB_TIMEOUT"
+	ErrCodeInvalidFilter = "INVALID_FILTER"
+	ErrCodeNotFound      = "NOT_FOUND"
+	ErrCodeValidation    = "VALIDATION_ERROR"
+)
+
 // PaginatedResponse represents a paginated API response
 type PaginatedResponse struct {
 	Status     string      `json:"status"`
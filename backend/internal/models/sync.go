@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SyncRun represents a single invocation of a sync job
+type SyncRun struct {
+	RunID       int64      `json:"run_id"`
+	SyncType    string     `json:"sync_type"`
+	Scope       *string    `json:"scope,omitempty"`
+	Status      string     `json:"status"`
+	TotalSynced int        `json:"total_synced"`
+	TotalErrors int        `json:"total_errors"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// SyncError represents a record that failed to sync during a run. Attempts
+// is how many times the record was tried before it was recorded; RetriedAt is
+// set once POST /sync/retry-errors has reprocessed it, and Resolved reports
+// whether that retry succeeded.
+type SyncError struct {
+	ErrorID      int64      `json:"error_id"`
+	RunID        int64      `json:"run_id"`
+	EntityType   string     `json:"entity_type"`
+	RecordID     *string    `json:"record_id,omitempty"`
+	LoanID       *string    `json:"loan_id,omitempty"`
+	ErrorMessage string     `json:"error_message"`
+	Attempts     int        `json:"attempts"`
+	RetriedAt    *time.Time `json:"retried_at,omitempty"`
+	Resolved     bool       `json:"resolved"`
+	Dead         bool       `json:"dead"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// SyncJob represents a batch sync running in the background, with its
+// progress and, once finished, its final counts. Result holds job-type
+// specific output, such as the rows affected by each step of a
+// loan_recalculation job.
+type SyncJob struct {
+	JobID              string          `json:"job_id"`
+	JobType            string          `json:"job_type"`
+	Status             string          `json:"status"`
+	TotalRecords       int             `json:"total_records"`
+	ProcessedRecords   int             `json:"processed_records"`
+	LoansInserted      int             `json:"loans_inserted"`
+	LoansFailed        int             `json:"loans_failed"`
+	RepaymentsInserted int             `json:"repayments_inserted"`
+	RepaymentsFailed   int             `json:"repayments_failed"`
+	ErrorMessage       *string         `json:"error_message,omitempty"`
+	Result             json.RawMessage `json:"result,omitempty"`
+	StartedAt          time.Time       `json:"started_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	FinishedAt         *time.Time      `json:"finished_at,omitempty"`
+}
+
+// DataFreshness describes how current the synced data is, for the dashboard's
+// "data as of" banner
+type DataFreshness struct {
+	LatestPaymentDate   *time.Time `json:"latest_payment_date"`
+	MaxRepaymentID      int64      `json:"max_repayment_id"`
+	LastSyncRun         *SyncRun   `json:"last_sync_run"`
+	LastCompletedSyncAt *time.Time `json:"last_completed_sync_at"`
+	StaleAfterHours     int        `json:"stale_after_hours"`
+	IsStale             bool       `json:"is_stale"`
+}
+
+// OrphanedLoan is a loan whose officer_id has no row in officers, so the
+// INNER JOIN in the metric queries drops it.
+type OrphanedLoan struct {
+	LoanID            string  `json:"loan_id"`
+	OfficerID         string  `json:"officer_id"`
+	CustomerName      string  `json:"customer_name"`
+	DjangoStatus      string  `json:"django_status"`
+	ActualOutstanding float64 `json:"actual_outstanding"`
+}
+
+// OrphanedRepayment is a repayment whose loan_id has no row in loans.
+type OrphanedRepayment struct {
+	RepaymentID   string  `json:"repayment_id"`
+	LoanID        string  `json:"loan_id"`
+	PaymentDate   string  `json:"payment_date"`
+	PaymentAmount float64 `json:"payment_amount"`
+}
+
+// OrphanReport counts and samples the loans and repayments that the metric
+// queries' INNER JOINs silently leave out, explaining gaps between raw row
+// counts and dashboard totals.
+type OrphanReport struct {
+	LoansWithoutOfficer            int                  `json:"loans_without_officer"`
+	LoansWithoutOfficerOutstanding float64              `json:"loans_without_officer_outstanding"`
+	LoanSamples                    []*OrphanedLoan      `json:"loan_samples"`
+	RepaymentsWithoutLoan          int                  `json:"repayments_without_loan"`
+	RepaymentsWithoutLoanAmount    float64              `json:"repayments_without_loan_amount"`
+	RepaymentSamples               []*OrphanedRepayment `json:"repayment_samples"`
+	SampleLimit                    int                  `json:"sample_limit"`
+}
+
+// StatusMismatchLoan is a loan whose normalized status disagrees with the
+// status its raw django_status maps to.
+type StatusMismatchLoan struct {
+	LoanID            string  `json:"loan_id"`
+	OfficerID         string  `json:"officer_id"`
+	CustomerName      string  `json:"customer_name"`
+	DjangoStatus      string  `json:"django_status"`
+	Status            string  `json:"status"`
+	ExpectedStatus    string  `json:"expected_status"` // empty for unmapped django statuses
+	MismatchType      string  `json:"mismatch_type"`
+	ActualOutstanding float64 `json:"actual_outstanding"`
+}
+
+// StatusMismatchCount counts the mismatched loans sharing one django_status,
+// status and expected status combination.
+type StatusMismatchCount struct {
+	MismatchType   string `json:"mismatch_type"`
+	DjangoStatus   string `json:"django_status"`
+	Status         string `json:"status"`
+	ExpectedStatus string `json:"expected_status"`
+	Count          int    `json:"count"`
+}
+
+// StatusMismatchReport lists loans whose status is inconsistent with their
+// django_status under the documented mapping, so normalization gaps can be
+// found and fixed.
+type StatusMismatchReport struct {
+	TotalMismatches int                    `json:"total_mismatches"`
+	CountsByType    map[string]int         `json:"counts_by_type"`
+	Breakdown       []*StatusMismatchCount `json:"breakdown"`
+	LoanSamples     []*StatusMismatchLoan  `json:"loan_samples"`
+	SampleLimit     int                    `json:"sample_limit"`
+	Mapping         map[string]string      `json:"mapping"` // django_status -> expected status
+}
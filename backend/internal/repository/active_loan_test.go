@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBehaviorAveragesUseActiveLoanSQL checks that the officer and portfolio
+// behaviour averages pick their loans with activeLoanSQL, so they follow
+// RecentRepaymentDays like the active loan counts do.
+func TestBehaviorAveragesUseActiveLoanSQL(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = func(query string) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "SELECT COUNT(*)") {
+			return []string{"count"}, [][]driver.Value{{int64(1)}}
+		}
+		return nil, nil
+	}
+	repo := NewDashboardRepository(db, config.DashboardConfig{RecentRepaymentDays: 8})
+
+	endpoints := map[string]func(){
+		"officers":          func() { repo.GetOfficers(map[string]interface{}{}) },
+		"officer by id":     func() { repo.GetOfficerByID("OFFICER-1") },
+		"portfolio metrics": func() { repo.GetPortfolioLoanMetrics(map[string]interface{}{}) },
+	}
+
+	for name, call := range endpoints {
+		rec.Reset()
+		call()
+
+		found := false
+		for _, query := range rec.Queries() {
+			if !strings.Contains(query, "avg_timeliness_score") {
+				continue
+			}
+			found = true
+			assert.Contains(t, query, "WHEN "+repo.activeLoanSQL()+" THEN", name)
+			assert.NotRegexp(t, `WHEN \(?[a-z_.+ ]*outstanding\)? > 2000\s+THEN`, query, name)
+		}
+		assert.True(t, found, "%s ran no behaviour average query", name)
+	}
+}
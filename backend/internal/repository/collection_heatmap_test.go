@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionHeatmapFlagsPartialWeek checks that the current week's days
+// come back flagged rather than looking like a complete week.
+func TestCollectionHeatmapFlagsPartialWeek(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = func(query string) ([]string, [][]driver.Value) {
+		return []string{"week_start", "day_of_week", "date", "collected_amount", "repayments_count", "partial_week"},
+			[][]driver.Value{
+				{"2026-10-05", int64(7), "2026-10-11", float64(0), int64(0), false},
+				{"2026-10-12", int64(1), "2026-10-12", float64(2500), int64(3), true},
+			}
+	}
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	cells, err := repo.GetCollectionHeatmap(map[string]interface{}{}, 2)
+	require.NoError(t, err)
+	require.Len(t, cells, 2)
+	assert.False(t, cells[0].PartialWeek)
+	assert.True(t, cells[1].PartialWeek)
+	assert.Contains(t, rec.Queries()[0], "AS partial_week")
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectionsProgressRejectsUnknownPeriod checks that an unknown period
+// fails with ErrInvalidFilter instead of silently reporting today.
+func TestCollectionsProgressRejectsUnknownPeriod(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	_, err := repo.GetCollectionsProgress(map[string]interface{}{}, "fortnight")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidFilter))
+	assert.Empty(t, rec.Queries())
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/seeds-metrics/analytics-backend/internal/models"
@@ -98,39 +99,201 @@ func (r *CustomerRepository) GetByID(ctx context.Context, customerID string) (*m
 	return &customer, nil
 }
 
-// List retrieves all customers
-func (r *CustomerRepository) List(ctx context.Context) ([]*models.Customer, error) {
-	query := `
-		SELECT 
+// customerColumns is the column list scanCustomerRow expects
+const customerColumns = `
 			customer_id, customer_name, customer_phone, customer_email,
 			date_of_birth, gender, state, lga, address,
 			kyc_status, kyc_verified_date,
-			created_at, updated_at
-		FROM customers
-		ORDER BY customer_name
-	`
+			created_at, updated_at`
+
+// likeEscaper escapes the LIKE wildcards, and the escape character itself,
+// so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
 
-	rows, err := r.db.QueryContext(ctx, query)
+// customerSearchFilter returns the WHERE clause narrowing customers to those
+// whose name or phone contains search, ignoring case, and its args. % and _
+// in search match literally. An empty search matches every customer.
+func customerSearchFilter(search string) (string, []interface{}) {
+	if search == "" {
+		return "", nil
+	}
+	pattern := "%" + likeEscaper.Replace(search) + "%"
+	return ` WHERE (customer_name ILIKE $1 ESCAPE '\' OR customer_phone ILIKE $1 ESCAPE '\')`, []interface{}{pattern}
+}
+
+// List retrieves one page of customers ordered by name, optionally narrowed
+// by customerSearchFilter, and returns the total number of matching
+// customers for pagination.
+func (r *CustomerRepository) List(ctx context.Context, search string, limit, offset int) ([]*models.Customer, int, error) {
+	where, args := customerSearchFilter(search)
+
+	// Counted separately so the total is still reported for a page past the
+	// last customer.
+	total := 0
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM customers"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count customers: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM customers%s
+		ORDER BY customer_name, customer_id
+		LIMIT $%d OFFSET $%d
+	`, customerColumns, where, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, fmt.Errorf("failed to query customers: %w", err)
 	}
 	defer rows.Close()
 
-	var customers []*models.Customer
+	customers := []*models.Customer{}
 	for rows.Next() {
 		var customer models.Customer
-		err := rows.Scan(
-			&customer.CustomerID, &customer.CustomerName, &customer.CustomerPhone, &customer.CustomerEmail,
-			&customer.DateOfBirth, &customer.Gender, &customer.State, &customer.LGA, &customer.Address,
-			&customer.KYCStatus, &customer.KYCVerifiedDate,
-			&customer.CreatedAt, &customer.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
+		if err := rows.Scan(customerScanDest(&customer)...); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan customer row: %w", err)
 		}
 		customers = append(customers, &customer)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate customer rows: %w", err)
+	}
+
+	return customers, total, nil
+}
+
+// Stream calls fn for every customer matching search, in the same order as
+// List, as rows are read, so the full customer base is never held in memory.
+// It stops at the first error fn returns.
+func (r *CustomerRepository) Stream(ctx context.Context, search string, fn func(*models.Customer) error) error {
+	where, args := customerSearchFilter(search)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM customers%s
+		ORDER BY customer_name, customer_id
+	`, customerColumns, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query customers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var customer models.Customer
+		if err := rows.Scan(customerScanDest(&customer)...); err != nil {
+			return fmt.Errorf("failed to scan customer row: %w", err)
+		}
+		if err := fn(&customer); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
 
-	return customers, nil
+// customerScanDest returns the scan destinations for customerColumns
+func customerScanDest(customer *models.Customer) []interface{} {
+	return []interface{}{
+		&customer.CustomerID, &customer.CustomerName, &customer.CustomerPhone, &customer.CustomerEmail,
+		&customer.DateOfBirth, &customer.Gender, &customer.State, &customer.LGA, &customer.Address,
+		&customer.KYCStatus, &customer.KYCVerifiedDate,
+		&customer.CreatedAt, &customer.UpdatedAt,
+	}
 }
 
+// FindDuplicatePhones returns phone numbers that appear on loans under more
+// than one distinct customer_id, with the loans involved. Groups are ordered
+// by the number of customer_ids sharing the phone, and the total number of
+// groups is returned for pagination.
+func (r *CustomerRepository) FindDuplicatePhones(ctx context.Context, limit, offset int) ([]*models.DuplicatePhoneGroup, int, error) {
+	// Counted separately so the total is still reported for a page past the
+	// last group.
+	countQuery := `
+		SELECT COUNT(*)
+		FROM (
+			SELECT customer_phone
+			FROM loans
+			WHERE customer_phone IS NOT NULL
+				AND TRIM(customer_phone) <> ''
+			GROUP BY customer_phone
+			HAVING COUNT(DISTINCT customer_id) > 1
+		) dup
+	`
+	total := 0
+	if err := r.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count duplicate phones: %w", err)
+	}
+
+	query := `
+		WITH dup AS (
+			SELECT
+				customer_phone,
+				COUNT(DISTINCT customer_id) AS customer_count
+			FROM loans
+			WHERE customer_phone IS NOT NULL
+				AND TRIM(customer_phone) <> ''
+			GROUP BY customer_phone
+			HAVING COUNT(DISTINCT customer_id) > 1
+			ORDER BY customer_count DESC, customer_phone
+			LIMIT $1 OFFSET $2
+		)
+		SELECT
+			d.customer_phone,
+			l.loan_id,
+			l.customer_id,
+			l.customer_name,
+			l.officer_id,
+			l.officer_name,
+			l.status,
+			TO_CHAR(l.disbursement_date, 'YYYY-MM-DD')
+		FROM dup d
+		INNER JOIN loans l ON l.customer_phone = d.customer_phone
+		ORDER BY d.customer_count DESC, d.customer_phone, l.customer_id, l.disbursement_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query duplicate phones: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*models.DuplicatePhoneGroup
+	var current *models.DuplicatePhoneGroup
+	seenCustomers := map[string]bool{}
+	seenOfficers := map[string]bool{}
+	for rows.Next() {
+		var phone string
+		loan := &models.DuplicatePhoneLoan{}
+		if err := rows.Scan(
+			&phone,
+			&loan.LoanID, &loan.CustomerID, &loan.CustomerName,
+			&loan.OfficerID, &loan.OfficerName, &loan.Status, &loan.DisbursementDate,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan duplicate phone row: %w", err)
+		}
+
+		if current == nil || current.CustomerPhone != phone {
+			current = &models.DuplicatePhoneGroup{CustomerPhone: phone}
+			groups = append(groups, current)
+			seenCustomers = map[string]bool{}
+			seenOfficers = map[string]bool{}
+		}
+		if !seenCustomers[loan.CustomerID] {
+			seenCustomers[loan.CustomerID] = true
+			current.CustomerIDs = append(current.CustomerIDs, loan.CustomerID)
+		}
+		if !seenOfficers[loan.OfficerID] {
+			seenOfficers[loan.OfficerID] = true
+			current.OfficerIDs = append(current.OfficerIDs, loan.OfficerID)
+		}
+		current.LoanCount++
+		current.Loans = append(current.Loans, loan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countOnlyRows answers SELECT COUNT(*) queries with count and every other
+// query with no rows, like a page requested past the last one.
+func countOnlyRows(count int64) func(string) ([]string, [][]driver.Value) {
+	return func(query string) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "SELECT COUNT(*)") {
+			return []string{"count"}, [][]driver.Value{{count}}
+		}
+		return nil, nil
+	}
+}
+
+// TestFindDuplicatePhonesTotalPastLastPage checks that the group total still
+// comes back when the requested page is empty.
+func TestFindDuplicatePhonesTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(7)
+	repo := NewCustomerRepository(&database.DB{DB: db})
+
+	groups, total, err := repo.FindDuplicatePhones(context.Background(), 50, 500)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+	assert.Equal(t, 7, total)
+}
+
+// TestCustomerListTotalPastLastPage checks that the customer total still
+// comes back when the requested page is empty.
+func TestCustomerListTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(12)
+	repo := NewCustomerRepository(&database.DB{DB: db})
+
+	customers, total, err := repo.List(context.Background(), "ada", 50, 500)
+	require.NoError(t, err)
+	assert.Empty(t, customers)
+	assert.Equal(t, 12, total)
+}
+
+func TestCustomerSearchFilterEscapesWildcards(t *testing.T) {
+	where, args := customerSearchFilter(`50%_off\`)
+	assert.Contains(t, where, `ILIKE $1 ESCAPE '\'`)
+	assert.Equal(t, []interface{}{`%50\%\_off\\%`}, args)
+
+	where, args = customerSearchFilter("")
+	assert.Empty(t, where)
+	assert.Nil(t, args)
+}
@@ -4,48 +4,285 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/models"
+	"github.com/shopspring/decimal"
 )
 
 const MissingValueSentinel = "__MISSING__"
 
+// UnassignedVerticalLead is the bucket label for loans with no vertical lead
+// name. As a vertical_lead_name filter value it matches NULL or blank names.
+const UnassignedVerticalLead = "Unassigned Vertical Lead"
+
 // DashboardRepository handles dashboard data queries
 type DashboardRepository struct {
-	db *sql.DB
+	db          *sql.DB
+	reportingDB *sql.DB // optional; heavy analytical reports run here when set
+	cfg         config.DashboardConfig
+	filterCache *filterOptionsCache
 }
 
 // NewDashboardRepository creates a new dashboard repository
-func NewDashboardRepository(db *sql.DB) *DashboardRepository {
-	return &DashboardRepository{db: db}
+func NewDashboardRepository(db *sql.DB, cfg config.DashboardConfig) *DashboardRepository {
+	return &DashboardRepository{db: db, cfg: cfg, filterCache: newFilterOptionsCache(cfg.FilterOptionsCacheTTL)}
+}
+
+// SetReportingDB routes the heavy reporting queries (aging matrices, cohorts,
+// curves) to a separate reporting/OLAP database instead of the transactional one.
+func (r *DashboardRepository) SetReportingDB(db *sql.DB) {
+	r.reportingDB = db
+}
+
+// userTypeFilter returns the standard officer restriction for officers
+// aliased o: the user_type filter, honouring the IncludeNullUserType setting,
+// plus the ExcludedOfficerIDs exclusion list.
+func (r *DashboardRepository) userTypeFilter() string {
+	return officerScopeSQL(r.cfg.IncludeNullUserType, r.cfg.ExcludedOfficerIDs)
+}
+
+// excludedOfficerFilter returns " AND l.officer_id NOT IN (...)" for the
+// ExcludedOfficerIDs setting, or "" when none are configured. Loan queries
+// that don't apply userTypeFilter use it so excluded officers' loans stay
+// out of every metric.
+func (r *DashboardRepository) excludedOfficerFilter() string {
+	if excluded := excludedOfficersSQL("l.officer_id", r.cfg.ExcludedOfficerIDs); excluded != "" {
+		return " AND " + excluded
+	}
+	return ""
+}
+
+// activeLoanSQL is the active-loan classification for loans aliased l: a
+// balance above 2000 and a repayment within the last RecentRepaymentDays
+// days. Loans with no repayment yet count as recent. inactiveLoanSQL is its
+// exact complement, so the two always partition the book.
+func (r *DashboardRepository) activeLoanSQL() string {
+	return fmt.Sprintf("(l.total_outstanding > 2000 AND COALESCE(l.days_since_last_repayment, 0) < %d)", r.recentRepaymentDays())
+}
+
+// inactiveLoanSQL is the complement of activeLoanSQL.
+func (r *DashboardRepository) inactiveLoanSQL() string {
+	return fmt.Sprintf("(l.total_outstanding <= 2000 OR COALESCE(l.days_since_last_repayment, 0) >= %d)", r.recentRepaymentDays())
+}
+
+// recentRepaymentDays is the configured RecentRepaymentDays, defaulting to 6.
+func (r *DashboardRepository) recentRepaymentDays() int {
+	if r.cfg.RecentRepaymentDays > 0 {
+		return r.cfg.RecentRepaymentDays
+	}
+	return 6
+}
+
+// openStatusFilter returns the restriction of loans aliased l to the
+// configured open django_status values (OpenDjangoStatuses).
+func (r *DashboardRepository) openStatusFilter() string {
+	return openDjangoStatusSQL(r.cfg.OpenDjangoStatuses)
+}
+
+// CountExcludedOfficers returns how many officers are left out of the
+// dashboard by the ExcludedOfficerIDs setting.
+func (r *DashboardRepository) CountExcludedOfficers() (int, error) {
+	list := officerIDListSQL(r.cfg.ExcludedOfficerIDs)
+	if list == "" {
+		return 0, nil
+	}
+
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM officers WHERE officer_id IN (" + list + ")").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count excluded officers: %w", err)
+	}
+	return count, nil
+}
+
+// dailyRepaymentSQL returns the expression used for a loan's expected daily
+// repayment. With DailyRepaymentFallback enabled, loans synced without a
+// daily_repayment_amount fall back to repayment_amount / loan_term_days,
+// mirroring the FIMR first-installment fallback (loan_amount / loan_term_days).
+func (r *DashboardRepository) dailyRepaymentSQL() string {
+	if !r.cfg.DailyRepaymentFallback {
+		return "l.daily_repayment_amount"
+	}
+	return `(CASE
+					WHEN COALESCE(l.daily_repayment_amount, 0) > 0 THEN l.daily_repayment_amount
+					WHEN l.loan_term_days > 0 THEN COALESCE(l.repayment_amount, 0) / l.loan_term_days
+					ELSE 0
+				END)`
+}
+
+// collectedTodaySQL is a loan's unreversed repayments dated today.
+var collectedTodaySQL = `COALESCE((
+					SELECT SUM(rt.payment_amount)
+					FROM repayments rt
+					WHERE rt.loan_id = l.loan_id
+						AND ` + repaymentNotReversedSQL("rt") + `
+						AND DATE(rt.payment_date) = CURRENT_DATE
+				), 0)`
+
+// missedTodaySQL returns the condition for a loan that missed today's
+// installment: it owes money, repayments have started, today is a collection
+// day (not a weekend or holiday) and less than its expected daily repayment
+// was collected today. GetAllLoans' missed_today flag and the summary's
+// missed_repayments_today use it so the two always agree. It is never NULL.
+func (r *DashboardRepository) missedTodaySQL() string {
+	return `COALESCE(l.actual_outstanding > 0
+				AND l.first_payment_due_date <= CURRENT_DATE
+				AND count_collection_days(CURRENT_DATE, CURRENT_DATE) > 0
+				AND ` + r.dailyRepaymentSQL() + ` > 0
+				AND ` + collectedTodaySQL + ` < ` + r.dailyRepaymentSQL() + `, false)`
+}
+
+// dailyRepaymentFallbackUsedSQL returns a condition that is true for loans
+// whose expected daily repayment comes from the fallback in dailyRepaymentSQL.
+func (r *DashboardRepository) dailyRepaymentFallbackUsedSQL() string {
+	if !r.cfg.DailyRepaymentFallback {
+		return "FALSE"
+	}
+	return "COALESCE(l.daily_repayment_amount, 0) <= 0 AND l.loan_term_days > 0"
+}
+
+// fimrRecoveredSQL returns a condition that is true for loans whose first
+// payment arrived after first_payment_due_date but which are paying again:
+// repaid within the last FIMRRecoveredRecentDays days and at most
+// FIMRRecoveredMaxDPD days past due. It is never NULL, so it can be negated.
+func (r *DashboardRepository) fimrRecoveredSQL() string {
+	return fmt.Sprintf(`COALESCE(
+				l.first_payment_received_date > l.first_payment_due_date
+				AND l.days_since_last_repayment <= %d
+				AND l.current_dpd <= %d, false)`, r.cfg.FIMRRecoveredRecentDays, r.cfg.FIMRRecoveredMaxDPD)
+}
+
+// pageBounds returns the LIMIT and OFFSET for filters["page"] and
+// filters["limit"], rejecting pages whose offset exceeds MaxPaginationOffset.
+func (r *DashboardRepository) pageBounds(filters map[string]interface{}, defaultLimit int) (int, int, error) {
+	page := 1
+	limit := defaultLimit
+	if p, ok := filters["page"].(int); ok && p > 0 {
+		page = p
+	}
+	if l, ok := filters["limit"].(int); ok && l > 0 {
+		limit = l
+	}
+	offset := (page - 1) * limit
+	if r.cfg.MaxPaginationOffset > 0 && offset > r.cfg.MaxPaginationOffset {
+		return 0, 0, fmt.Errorf("%w: page %d with limit %d exceeds the maximum offset of %d rows; narrow the filters or use keyset pagination",
+			ErrInvalidFilter, page, limit, r.cfg.MaxPaginationOffset)
+	}
+	return limit, offset, nil
+}
+
+// roundRatio rounds a 0-1 ratio (today_rate, npl_ratio, ...) to the configured
+// RatioPrecision so every screen shows the same number.
+func (r *DashboardRepository) roundRatio(v float64) float64 {
+	return roundTo(v, r.cfg.RatioPrecision)
+}
+
+// roundPercent rounds a 0-100 percentage to the same significant digits as
+// roundRatio, i.e. two fewer decimal places.
+func (r *DashboardRepository) roundPercent(v float64) float64 {
+	places := r.cfg.RatioPrecision - 2
+	if places < 0 {
+		places = 0
+	}
+	return roundTo(v, places)
+}
+
+// safeDivide returns a / b, or 0 when b is zero or the result is not finite,
+// so ratios over empty populations never reach JSON as NaN or Inf.
+func safeDivide(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	q := a / b
+	if math.IsNaN(q) || math.IsInf(q, 0) {
+		return 0
+	}
+	return q
+}
+
+// roundTo rounds v half away from zero to places decimal places.
+func roundTo(v float64, places int) float64 {
+	multiplier := math.Pow(10, float64(places))
+	return math.Round(v*multiplier) / multiplier
+}
+
+// reportingConn returns the connection reporting queries should use, falling
+// back to the main database when no reporting database is configured.
+func (r *DashboardRepository) reportingConn() *sql.DB {
+	if r.reportingDB != nil {
+		return r.reportingDB
+	}
+	return r.db
 }
 
 // RecalculateAllLoanFields triggers comprehensive recalculation of all computed fields for all loans.
 //
-// It performs two steps:
+// It performs three steps:
 //  1. Calls the recalculate_all_loan_fields() stored procedure which recomputes all
 //     derived metrics (DPD, risk tags, timeliness scores, etc.) using the database logic.
-//  2. Applies a safety normalisation pass directly on the loans table to ensure that
+//  2. Re-derives fimr_tagged with RecomputeFIMRTags, since the stored procedure
+//     ignores DashboardConfig.FIMRGracePeriodDays.
+//  3. Applies a safety normalisation pass directly on the loans table to ensure that
 //     monetary fields are internally consistent, specifically:
 //     - total_outstanding is always max(0, repayment_amount - total_repayments)
 //     - actual_outstanding is never greater than total_outstanding
 //
-// This second step gives us the business guarantee that "Actual Outstanding" can
+// This last step gives us the business guarantee that "Actual Outstanding" can
 // never exceed the contractual "Outstanding" amount, even if older versions of the
-// database function left inconsistent values behind.
-func (r *DashboardRepository) RecalculateAllLoanFields() (int64, error) {
+// database function left inconsistent values behind. It overwrites values that
+// may be authoritative in Django, so it only runs when
+// DashboardConfig.NormalizeOutstanding is enabled (the default).
+func (r *DashboardRepository) RecalculateAllLoanFields() (*models.LoanRecalculationResult, error) {
+	result := &models.LoanRecalculationResult{}
+
 	// Step 1: run the main database-side recalculation.
 	//
 	// We intentionally call the function via Exec rather than QueryRow+Scan so that
 	// this code is compatible with older deployments where
 	// recalculate_all_loan_fields() may not return the
 	// (total_loans_processed, loans_updated, execution_time_ms) columns.
-	if _, err := r.db.Exec("SELECT recalculate_all_loan_fields()"); err != nil {
-		return 0, fmt.Errorf("failed to recalculate loan fields: %w", err)
+	//
+	// The function sets updated_at = CURRENT_TIMESTAMP on every row it updates,
+	// and CURRENT_TIMESTAMP is fixed for the duration of a transaction, so
+	// counting the rows stamped with it inside the same transaction gives the
+	// rows the function actually updated.
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start loan recalculation: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT recalculate_all_loan_fields()"); err != nil {
+		return nil, fmt.Errorf("failed to recalculate loan fields: %w", err)
+	}
+	if err := tx.QueryRow("SELECT COUNT(*) FROM loans WHERE updated_at = CURRENT_TIMESTAMP").Scan(&result.LoansRecalculated); err != nil {
+		return nil, fmt.Errorf("failed to count recalculated loans: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit loan recalculation: %w", err)
+	}
+	result.RecalculationRan = true
+
+	// The stored function tags FIMR with no grace period; re-derive the tags
+	// with the configured one so FIMR-based metrics stay consistent.
+	fimrChanged, err := r.RecomputeFIMRTags(r.cfg.FIMRGracePeriodDays)
+	if err != nil {
+		return nil, err
+	}
+	result.FIMRRecomputeRan = true
+	result.FIMRTagsChanged = fimrChanged
+
+	if !r.cfg.NormalizeOutstanding {
+		log.Println("⏭️  Skipping outstanding balance normalisation (DASHBOARD_NORMALIZE_OUTSTANDING=false)")
+		return result, nil
 	}
 
-	// Step 2: enforce consistent outstanding balances using a single, set-based UPDATE.
+	// Step 3: enforce consistent outstanding balances using a single, set-based UPDATE.
 	//
 	// This uses only stable columns (repayment_amount, total_repayments, total_outstanding,
 	// actual_outstanding) and does NOT depend on any particular version of the
@@ -76,48 +313,69 @@ func (r *DashboardRepository) RecalculateAllLoanFields() (int64, error) {
 				);
 		`
 
-	result, err := r.db.Exec(fixQuery)
+	res, err := r.db.Exec(fixQuery)
 	if err != nil {
-		return 0, fmt.Errorf("failed to normalise outstanding balances: %w", err)
+		return nil, fmt.Errorf("failed to normalise outstanding balances: %w", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	return rowsAffected, nil
+	result.NormalizationRan = true
+	result.LoansNormalized, _ = res.RowsAffected()
+	return result, nil
 }
 
 // GetPortfolioLoanMetrics retrieves loan-level aggregated metrics for portfolio calculations
 func (r *DashboardRepository) GetPortfolioLoanMetrics(filters map[string]interface{}) (*models.PortfolioLoanMetrics, error) {
 	query := `
 		SELECT
-			-- Active vs Inactive Loans
-			COUNT(CASE WHEN total_outstanding > 2000
-				AND days_since_last_repayment < 6 THEN 1 END) as active_loans_count,
-			COALESCE(SUM(CASE WHEN total_outstanding > 2000
-				AND days_since_last_repayment < 6
+			-- Active vs Inactive Loans (see activeLoanSQL)
+			COUNT(CASE WHEN ` + r.activeLoanSQL() + ` THEN 1 END) as active_loans_count,
+			COALESCE(SUM(CASE WHEN ` + r.activeLoanSQL() + `
 				THEN total_outstanding END), 0) as active_loans_volume,
-			COUNT(CASE WHEN total_outstanding <= 2000
-				OR days_since_last_repayment > 5 THEN 1 END) as inactive_loans_count,
-			COALESCE(SUM(CASE WHEN total_outstanding <= 2000
-				OR days_since_last_repayment > 5
+			COUNT(CASE WHEN ` + r.inactiveLoanSQL() + ` THEN 1 END) as inactive_loans_count,
+			COALESCE(SUM(CASE WHEN ` + r.inactiveLoanSQL() + `
 				THEN total_outstanding END), 0) as inactive_loans_volume,
 
-			-- ROT (Risk of Termination) Analysis
+			-- ROT (Risk of Termination) Analysis. Loans without a disbursement_date
+			-- have no age, so they are bucketed as "unknown age" instead of
+			-- silently dropping out of both early and late ROT.
 			COUNT(CASE WHEN (CURRENT_DATE - disbursement_date::date) < 7 AND current_dpd > 4 THEN 1 END) as early_rot_count,
 			COALESCE(SUM(CASE WHEN (CURRENT_DATE - disbursement_date::date) < 7 AND current_dpd > 4
 				THEN total_outstanding END), 0) as early_rot_volume,
 			COUNT(CASE WHEN (CURRENT_DATE - disbursement_date::date) >= 7 AND current_dpd > 4 THEN 1 END) as late_rot_count,
 			COALESCE(SUM(CASE WHEN (CURRENT_DATE - disbursement_date::date) >= 7 AND current_dpd > 4
 				THEN total_outstanding END), 0) as late_rot_volume,
+			COUNT(CASE WHEN disbursement_date IS NULL AND current_dpd > 4 THEN 1 END) as unknown_age_rot_count,
+			COALESCE(SUM(CASE WHEN disbursement_date IS NULL AND current_dpd > 4
+				THEN total_outstanding END), 0) as unknown_age_rot_volume,
+			COUNT(CASE WHEN disbursement_date IS NULL THEN 1 END) as missing_disbursement_date_count,
 
-			-- Portfolio Repayment Behavior Metrics (only active loans)
-			COALESCE(AVG(CASE WHEN total_outstanding > 2000
+			-- Portfolio Repayment Behavior Metrics (active loans only, see activeLoanSQL)
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + `
 				THEN current_dpd END), 0) as avg_days_past_due,
-			COALESCE(AVG(CASE WHEN total_outstanding > 2000
-				THEN timeliness_score END), 0) as avg_timeliness_score
+			-- Same loans as avg_days_past_due, but large balances count for more
+			COALESCE(SUM(CASE WHEN ` + r.activeLoanSQL() + `
+				THEN current_dpd * actual_outstanding END)
+				/ NULLIF(SUM(CASE WHEN ` + r.activeLoanSQL() + `
+				THEN actual_outstanding END), 0), 0) as weighted_avg_dpd,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + `
+				THEN timeliness_score END), 0) as avg_timeliness_score,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + `
+				THEN repayment_health END), 0) as avg_repayment_health,
+
+			-- Soft scores weighted by actual_outstanding over the whole active
+			-- book (including quiet loans), plus how many loans have no score
+			COALESCE(SUM(timeliness_score * actual_outstanding)
+				/ NULLIF(SUM(CASE WHEN timeliness_score IS NOT NULL
+				THEN actual_outstanding END), 0), 0) as weighted_avg_timeliness_score,
+			COALESCE(SUM(repayment_health * actual_outstanding)
+				/ NULLIF(SUM(CASE WHEN repayment_health IS NOT NULL
+				THEN actual_outstanding END), 0), 0) as weighted_avg_repayment_health,
+			COUNT(CASE WHEN timeliness_score IS NULL THEN 1 END) as missing_timeliness_score_count,
+			COUNT(CASE WHEN repayment_health IS NULL THEN 1 END) as missing_repayment_health_count
 		FROM loans l
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE UPPER(l.status) = 'ACTIVE'
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
 	args := []interface{}{}
@@ -125,7 +383,7 @@ func (r *DashboardRepository) GetPortfolioLoanMetrics(filters map[string]interfa
 
 	// Apply wave filter
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
@@ -140,14 +398,28 @@ func (r *DashboardRepository) GetPortfolioLoanMetrics(filters map[string]interfa
 		&metrics.EarlyROTVolume,
 		&metrics.LateROTCount,
 		&metrics.LateROTVolume,
+		&metrics.UnknownAgeROTCount,
+		&metrics.UnknownAgeROTVolume,
+		&metrics.MissingDisbursementDateCount,
 		&metrics.AvgDaysPastDue,
+		&metrics.WeightedAvgDPD,
 		&metrics.AvgTimelinessScore,
+		&metrics.AvgRepaymentHealth,
+		&metrics.WeightedAvgTimelinessScore,
+		&metrics.WeightedAvgRepaymentHealth,
+		&metrics.MissingTimelinessScoreCount,
+		&metrics.MissingRepaymentHealthCount,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if metrics.MissingDisbursementDateCount > 0 {
+		log.Printf("⚠️  %d active loans have no disbursement_date; %d of them are counted as unknown-age ROT",
+			metrics.MissingDisbursementDateCount, metrics.UnknownAgeROTCount)
+	}
+
 	return metrics, nil
 }
 
@@ -165,7 +437,7 @@ func (r *DashboardRepository) GetActualOverdue15d(filters map[string]interface{}
 			AND UPPER(l.status) = 'ACTIVE'
 			AND ls.due_date <= CURRENT_DATE
 			AND ls.payment_status IN ('Pending', 'Partial', 'Overdue')
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
 	args := []interface{}{}
@@ -173,7 +445,7 @@ func (r *DashboardRepository) GetActualOverdue15d(filters map[string]interface{}
 
 	// Apply wave filter to schedule query
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		scheduleQuery += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		scheduleQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
@@ -203,7 +475,7 @@ func (r *DashboardRepository) GetActualOverdue15d(filters map[string]interface{}
 			INNER JOIN officers o ON l.officer_id = o.officer_id
 			WHERE l.current_dpd >= 15
 				AND UPPER(l.status) = 'ACTIVE'
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+				AND ` + r.userTypeFilter() + `
 		`
 
 		fallbackArgs := []interface{}{}
@@ -211,7 +483,7 @@ func (r *DashboardRepository) GetActualOverdue15d(filters map[string]interface{}
 
 		// Apply wave filter to fallback query
 		if wave, ok := filters["wave"].(string); ok && wave != "" {
-			fallbackQuery += fmt.Sprintf(" AND l.wave = $%d", fallbackArgCount)
+			fallbackQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", fallbackArgCount)
 			fallbackArgs = append(fallbackArgs, wave)
 			fallbackArgCount++
 		}
@@ -236,7 +508,7 @@ func (r *DashboardRepository) GetTotalDPDLoans(filters map[string]interface{}) (
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.current_dpd > 0
 			AND UPPER(l.status) IN ('ACTIVE', 'DEFAULTED')
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
 	args := []interface{}{}
@@ -244,7 +516,7 @@ func (r *DashboardRepository) GetTotalDPDLoans(filters map[string]interface{}) (
 
 	// Apply wave filter
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
@@ -278,8 +550,131 @@ func (r *DashboardRepository) GetTotalDPDLoans(filters map[string]interface{}) (
 	return count, actualOutstanding, nil
 }
 
+// officerTenureBucketSQL buckets an officer's tenure (hire_date to today).
+// Officers without a hire_date are "unknown".
+const officerTenureBucketSQL = `(CASE
+				WHEN o.hire_date IS NULL THEN 'unknown'
+				WHEN o.hire_date > CURRENT_DATE - INTERVAL '3 months' THEN '<3mo'
+				WHEN o.hire_date > CURRENT_DATE - INTERVAL '6 months' THEN '3-6mo'
+				WHEN o.hire_date > CURRENT_DATE - INTERVAL '12 months' THEN '6-12mo'
+				ELSE '1y+'
+			END)`
+
+// OfficerTenureBuckets lists the tenure_bucket values in ascending tenure.
+var OfficerTenureBuckets = []string{"<3mo", "3-6mo", "6-12mo", "1y+", "unknown"}
+
+func isOfficerTenureBucket(bucket string) bool {
+	for _, b := range OfficerTenureBuckets {
+		if b == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// Allocation methods for the fees/interest collected officer metrics
+// (DashboardConfig.FeeAllocationMethod).
+const (
+	FeeAllocationProRata   = "pro_rata"
+	FeeAllocationWaterfall = "waterfall"
+)
+
+// feeAllocationSQL returns the per-loan fees and interest collected
+// expressions over the loan_repayments CTE (lr), which the officer queries
+// SUM. Pro-rata attributes total_repayments to fees and interest by their
+// share of the total repayable (loan_amount*(1+interest_rate) + fee_amount).
+// Waterfall applies it to fee_amount first, then to the loan_amount*
+// interest_rate interest, then principal. Unknown methods fall back to
+// pro-rata.
+func (r *DashboardRepository) feeAllocationSQL() (string, string) {
+	if r.cfg.FeeAllocationMethod == FeeAllocationWaterfall {
+		fees := `LEAST(COALESCE(lr.total_repayments, 0), lr.fee_amount)`
+		interest := `LEAST(GREATEST(COALESCE(lr.total_repayments, 0) - lr.fee_amount, 0), lr.loan_amount * lr.interest_rate)`
+		return fees, interest
+	}
+
+	fees := `
+				CASE
+					WHEN lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount > 0 THEN
+						lr.total_repayments * lr.fee_amount / (lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount)
+					ELSE 0
+				END
+			`
+	interest := `
+				CASE
+					WHEN lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount > 0 THEN
+						lr.total_repayments * (lr.loan_amount * lr.interest_rate) / (lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount)
+					ELSE 0
+				END
+			`
+	return fees, interest
+}
+
+// Matching modes for the officer_email filter in GetOfficers.
+const (
+	OfficerMatchContains = "contains"
+	OfficerMatchExact    = "exact"
+)
+
 // GetOfficers retrieves all officers with their raw metrics
-func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*models.DashboardOfficerMetrics, error) {
+func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*models.DashboardOfficerMetrics, int, error) {
+	officers := []*models.DashboardOfficerMetrics{}
+	total, err := r.eachOfficer(filters, func(officer *models.DashboardOfficerMetrics) error {
+		officers = append(officers, officer)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return officers, total, nil
+}
+
+// StreamOfficers calls fn for every officer matching filters, ignoring
+// pagination, as each row is read, so the full officer set never has to be
+// held in memory. An error from fn stops the stream and is returned.
+func (r *DashboardRepository) StreamOfficers(filters map[string]interface{}, fn func(*models.DashboardOfficerMetrics) error) error {
+	scoped := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		scoped[k] = v
+	}
+	scoped["unpaginated"] = true
+
+	_, err := r.eachOfficer(scoped, fn)
+	return err
+}
+
+// eachOfficer runs the GetOfficers query and calls fn for each officer row,
+// returning the total number of officers matching the filters.
+func (r *DashboardRepository) eachOfficer(filters map[string]interface{}, fn func(*models.DashboardOfficerMetrics) error) (int, error) {
+	// By default only currently open loans (OpenDjangoStatuses) feed the
+	// officer aggregation. Completed or declined loans are only included when
+	// include_closed is set, e.g. for historical views. The condition lives in
+	// the JOIN so officers without active loans are still returned.
+	loanJoinCondition := " AND " + r.openStatusFilter()
+	if includeClosed, ok := filters["include_closed"].(bool); ok && includeClosed {
+		loanJoinCondition = ""
+	}
+
+	args := []interface{}{}
+	argCount := 1
+
+	// Loan-level filters also live in the JOIN: an officer stays listed, but
+	// only their matching loans feed the metrics.
+	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &argCount); cond != "" {
+			loanJoinCondition += " AND " + cond
+			args = append(args, condArgs...)
+		}
+	}
+
+	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &argCount); cond != "" {
+			loanJoinCondition += " AND " + cond
+			args = append(args, condArgs...)
+		}
+	}
+	feesCollected, interestCollected := r.feeAllocationSQL()
+
 	query := `
 		WITH loan_repayments AS (
 			SELECT
@@ -290,7 +685,7 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 				l.fee_amount,
 				SUM(r.payment_amount) as total_repayments
 			FROM loans l
-			LEFT JOIN repayments r ON l.loan_id = r.loan_id AND r.is_reversed = false
+			LEFT JOIN repayments r ON l.loan_id = r.loan_id AND ` + reversalFilterSQL(false) + `
 			GROUP BY l.loan_id, l.officer_id, l.loan_amount, l.interest_rate, l.fee_amount
 		)
 		SELECT
@@ -302,6 +697,7 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 			COALESCE(o.primary_channel, '') as primary_channel,
 		o.user_type,
 			o.hire_date,
+			` + officerTenureBucketSQL + ` as tenure_bucket,
 			o.supervisor_email,
 			o.supervisor_name,
 			o.vertical_lead_email,
@@ -313,23 +709,11 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 			COALESCE(SUM(l.principal_outstanding + l.interest_outstanding + l.fees_outstanding), 0) as amount_due_7d,
 			COALESCE(SUM(CASE WHEN l.current_dpd BETWEEN 7 AND 30 THEN l.principal_outstanding ELSE 0 END), 0) as moved_to_7to30,
 			COALESCE(SUM(CASE WHEN l.current_dpd BETWEEN 1 AND 6 THEN l.principal_outstanding ELSE 0 END), 0) as prev_dpd1to6_bal,
-			-- Calculate fees collected from repayments (proportional allocation)
-			COALESCE(SUM(
-				CASE
-					WHEN lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount > 0 THEN
-						lr.total_repayments * lr.fee_amount / (lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount)
-					ELSE 0
-				END
-			), 0) as fees_collected,
+			-- Fees collected from repayments (see feeAllocationSQL)
+			COALESCE(SUM(` + feesCollected + `), 0) as fees_collected,
 			COALESCE(SUM(l.fee_amount), 0) as fees_due,
-			-- Calculate interest collected from repayments (proportional allocation)
-			COALESCE(SUM(
-				CASE
-					WHEN lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount > 0 THEN
-						lr.total_repayments * (lr.loan_amount * lr.interest_rate) / (lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount)
-					ELSE 0
-				END
-			), 0) as interest_collected,
+			-- Interest collected from repayments (see feeAllocationSQL)
+			COALESCE(SUM(` + interestCollected + `), 0) as interest_collected,
 			COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) as overdue_15d,
 			COALESCE(SUM(l.principal_outstanding), 0) as total_portfolio,
 			COALESCE(SUM(l.principal_outstanding), 0) as par15_mid_month,
@@ -338,25 +722,25 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 			0 as entries,
 			0 as reversals,
 			false as had_float_gap,
-			-- NEW: Repayment behavior metrics (only for loans with total_outstanding > 2000)
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.timeliness_score ELSE NULL END), 0) as avg_timeliness_score,
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.repayment_health ELSE NULL END), 0) as avg_repayment_health,
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.days_since_last_repayment ELSE NULL END), 0) as avg_days_since_last_repayment,
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.loan_age ELSE NULL END), 0) as avg_loan_age,
-			COALESCE(COUNT(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN 1 ELSE NULL END), 0) as active_loans_count
+			-- Repayment behavior metrics (active loans only, see activeLoanSQL)
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.timeliness_score ELSE NULL END), 0) as avg_timeliness_score,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.repayment_health ELSE NULL END), 0) as avg_repayment_health,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.days_since_last_repayment ELSE NULL END), 0) as avg_days_since_last_repayment,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.loan_age ELSE NULL END), 0) as avg_loan_age,
+			COALESCE(COUNT(CASE WHEN ` + r.activeLoanSQL() + ` THEN 1 ELSE NULL END), 0) as active_loans_count
 		FROM officers o
-		LEFT JOIN loans l ON o.officer_id = l.officer_id
-		LEFT JOIN loan_repayments lr ON l.loan_id = lr.loan_id
+		LEFT JOIN loans l ON o.officer_id = l.officer_id` + loanJoinCondition + `
+		LEFT JOIN loan_repayments lr ON l.loan_id = lr.loan_id`
+
+	// where is shared with the separate count query below
+	where := `
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
-	args := []interface{}{}
-	argCount := 1
-
 	// Apply filters
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
-		query += fmt.Sprintf(" AND o.branch = $%d", argCount)
+		where += fmt.Sprintf(" AND o.branch = $%d", argCount)
 		args = append(args, branch)
 		argCount++
 	}
@@ -365,7 +749,7 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 		// Support comma-separated regions for multi-select
 		regions := strings.Split(region, ",")
 		if len(regions) == 1 {
-			query += fmt.Sprintf(" AND o.region = $%d", argCount)
+			where += fmt.Sprintf(" AND o.region = $%d", argCount)
 			args = append(args, regions[0])
 			argCount++
 		} else {
@@ -376,57 +760,93 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 				args = append(args, strings.TrimSpace(r))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND o.region IN (%s)", strings.Join(placeholders, ", "))
+			where += fmt.Sprintf(" AND o.region IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	if channel, ok := filters["channel"].(string); ok && channel != "" {
-		query += fmt.Sprintf(" AND o.primary_channel = $%d", argCount)
+		where += fmt.Sprintf(" AND o.primary_channel = $%d", argCount)
 		args = append(args, channel)
 		argCount++
 	}
 
 	if userType, ok := filters["user_type"].(string); ok && userType != "" {
-		query += fmt.Sprintf(" AND o.user_type = $%d", argCount)
+		where += fmt.Sprintf(" AND o.user_type = $%d", argCount)
 		args = append(args, userType)
 		argCount++
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		where += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
 
 	if officerEmail, ok := filters["officer_email"].(string); ok && officerEmail != "" {
-		query += fmt.Sprintf(" AND (o.officer_email ILIKE $%d OR o.officer_name ILIKE $%d)", argCount, argCount)
-		args = append(args, "%"+officerEmail+"%")
+		// match=contains (default) is a substring search on email or name;
+		// match=exact only returns officers whose email or name is equal,
+		// ignoring case.
+		match, _ := filters["match"].(string)
+		switch match {
+		case "", OfficerMatchContains:
+			where += fmt.Sprintf(" AND (o.officer_email ILIKE $%d OR o.officer_name ILIKE $%d)", argCount, argCount)
+			args = append(args, "%"+officerEmail+"%")
+		case OfficerMatchExact:
+			where += fmt.Sprintf(" AND (LOWER(o.officer_email) = LOWER($%d) OR LOWER(o.officer_name) = LOWER($%d))", argCount, argCount)
+			args = append(args, officerEmail)
+		default:
+			return 0, fmt.Errorf("%w: unsupported match %q", ErrInvalidFilter, match)
+		}
 		argCount++
 	}
 
-	query += " GROUP BY o.officer_id, o.officer_name, o.officer_email, o.region, o.branch, o.primary_channel, o.user_type, o.hire_date"
+	if tenureBucket, ok := filters["tenure_bucket"].(string); ok && tenureBucket != "" {
+		placeholders := []string{}
+		for _, bucket := range strings.Split(tenureBucket, ",") {
+			bucket = strings.TrimSpace(bucket)
+			if !isOfficerTenureBucket(bucket) {
+				return 0, fmt.Errorf("%w: unsupported tenure_bucket %q", ErrInvalidFilter, bucket)
+			}
+			placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+			args = append(args, bucket)
+			argCount++
+		}
+		where += fmt.Sprintf(" AND %s IN (%s)", officerTenureBucketSQL, strings.Join(placeholders, ", "))
+	}
+
+	query += where + " GROUP BY o.officer_id, o.officer_name, o.officer_email, o.region, o.branch, o.primary_channel, o.user_type, o.hire_date"
 
 	// Apply sorting
-	sortBy := "o.officer_name"
-	if sort, ok := filters["sort_by"].(string); ok && sort != "" {
-		sortBy = sort
-	}
-	sortDir := "ASC"
-	if dir, ok := filters["sort_dir"].(string); ok && strings.ToUpper(dir) == "DESC" {
-		sortDir = "DESC"
+	orderBy, err := orderByClause(SortEntityOfficers, filters, "o.officer_name", "ASC")
+	if err != nil {
+		return 0, err
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortDir)
+	query += orderBy
 
-	// Apply pagination
-	limit := 50
-	if l, ok := filters["limit"].(int); ok && l > 0 {
-		limit = l
-	}
-	offset := 0
-	if page, ok := filters["page"].(int); ok && page > 0 {
-		offset = (page - 1) * limit
+	// Apply pagination. Callers that sort on metrics computed in Go set
+	// unpaginated to fetch the full officer set and paginate it themselves.
+	// Paginated totals are counted separately so a page past the last officer
+	// still reports them.
+	total := 0
+	unpaginated, _ := filters["unpaginated"].(bool)
+	if !unpaginated {
+		limit, offset, err := r.pageBounds(filters, 50)
+		if err != nil {
+			return 0, err
+		}
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+
+		countQuery := `
+		SELECT COUNT(*)
+		FROM (
+			SELECT DISTINCT o.officer_id
+			FROM officers o
+			LEFT JOIN loans l ON o.officer_id = l.officer_id` + loanJoinCondition + where + `
+		) matched`
+		if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+			return 0, fmt.Errorf("failed to count officers: %w", err)
+		}
 	}
-	query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
 
 	// Log the query for debugging
 	log.Printf("🔍 GetOfficers SQL Query: %s", query)
@@ -435,11 +855,10 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		log.Printf("❌ GetOfficers SQL Error: %v", err)
-		return nil, err
+		return 0, err
 	}
 	defer rows.Close()
 
-	officers := []*models.DashboardOfficerMetrics{}
 	for rows.Next() {
 		officer := &models.DashboardOfficerMetrics{
 			RawMetrics: &models.RawMetrics{},
@@ -456,6 +875,7 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 			&officer.Channel,
 			&officer.UserType,
 			&officer.HireDate,
+			&officer.TenureBucket,
 			&supervisorEmail,
 			&supervisorName,
 			&verticalLeadEmail,
@@ -484,7 +904,7 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 			&officer.RawMetrics.ActiveLoansCount,
 		)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
 		// Handle NULL values for supervisor and vertical lead fields
@@ -501,14 +921,25 @@ func (r *DashboardRepository) GetOfficers(filters map[string]interface{}) ([]*mo
 			officer.VerticalLeadName = &verticalLeadName.String
 		}
 
-		officers = append(officers, officer)
+		if err := fn(officer); err != nil {
+			return 0, err
+		}
+		if unpaginated {
+			total++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
 	}
 
-	return officers, nil
+	return total, nil
 }
 
-// GetOfficerByID retrieves a single officer by ID
+// GetOfficerByID retrieves a single officer by ID, including their current
+// audit assignment (status, assignee, date) when one exists
 func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.DashboardOfficerMetrics, error) {
+	feesCollected, interestCollected := r.feeAllocationSQL()
+
 	query := `
 		WITH loan_repayments AS (
 			SELECT
@@ -519,7 +950,7 @@ func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.Dashboar
 				l.fee_amount,
 				SUM(r.payment_amount) as total_repayments
 			FROM loans l
-			LEFT JOIN repayments r ON l.loan_id = r.loan_id AND r.is_reversed = false
+			LEFT JOIN repayments r ON l.loan_id = r.loan_id AND ` + reversalFilterSQL(false) + `
 			WHERE l.officer_id = $1
 			GROUP BY l.loan_id, l.officer_id, l.loan_amount, l.interest_rate, l.fee_amount
 		)
@@ -532,6 +963,7 @@ func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.Dashboar
 			COALESCE(o.primary_channel, '') as primary_channel,
 			o.user_type,
 			o.hire_date,
+			` + officerTenureBucketSQL + ` as tenure_bucket,
 			o.supervisor_email,
 			o.supervisor_name,
 			o.vertical_lead_email,
@@ -542,23 +974,11 @@ func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.Dashboar
 			COALESCE(SUM(l.principal_outstanding + l.interest_outstanding + l.fees_outstanding), 0) as amount_due_7d,
 			COALESCE(SUM(CASE WHEN l.current_dpd BETWEEN 7 AND 30 THEN l.principal_outstanding ELSE 0 END), 0) as moved_to_7to30,
 			COALESCE(SUM(CASE WHEN l.current_dpd BETWEEN 1 AND 6 THEN l.principal_outstanding ELSE 0 END), 0) as prev_dpd1to6_bal,
-			-- Calculate fees collected from repayments (proportional allocation)
-			COALESCE(SUM(
-				CASE
-					WHEN lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount > 0 THEN
-						lr.total_repayments * lr.fee_amount / (lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount)
-					ELSE 0
-				END
-			), 0) as fees_collected,
+			-- Fees collected from repayments (see feeAllocationSQL)
+			COALESCE(SUM(` + feesCollected + `), 0) as fees_collected,
 			COALESCE(SUM(l.fee_amount), 0) as fees_due,
-			-- Calculate interest collected from repayments (proportional allocation)
-			COALESCE(SUM(
-				CASE
-					WHEN lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount > 0 THEN
-						lr.total_repayments * (lr.loan_amount * lr.interest_rate) / (lr.loan_amount * (1 + lr.interest_rate) + lr.fee_amount)
-					ELSE 0
-				END
-			), 0) as interest_collected,
+			-- Interest collected from repayments (see feeAllocationSQL)
+			COALESCE(SUM(` + interestCollected + `), 0) as interest_collected,
 			COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) as overdue_15d,
 			COALESCE(SUM(l.principal_outstanding), 0) as total_portfolio,
 			COALESCE(SUM(l.principal_outstanding), 0) as par15_mid_month,
@@ -568,17 +988,29 @@ func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.Dashboar
 			0 as reversals,
 			false as had_float_gap,
 			-- Repayment behavior metrics (align with list query)
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.timeliness_score ELSE NULL END), 0) as avg_timeliness_score,
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.repayment_health ELSE NULL END), 0) as avg_repayment_health,
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.days_since_last_repayment ELSE NULL END), 0) as avg_days_since_last_repayment,
-			COALESCE(AVG(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN l.loan_age ELSE NULL END), 0) as avg_loan_age,
-			COALESCE(COUNT(CASE WHEN (l.principal_outstanding + l.interest_outstanding + l.fees_outstanding) > 2000 THEN 1 ELSE NULL END), 0) as active_loans_count
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.timeliness_score ELSE NULL END), 0) as avg_timeliness_score,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.repayment_health ELSE NULL END), 0) as avg_repayment_health,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.days_since_last_repayment ELSE NULL END), 0) as avg_days_since_last_repayment,
+			COALESCE(AVG(CASE WHEN ` + r.activeLoanSQL() + ` THEN l.loan_age ELSE NULL END), 0) as avg_loan_age,
+			COALESCE(COUNT(CASE WHEN ` + r.activeLoanSQL() + ` THEN 1 ELSE NULL END), 0) as active_loans_count,
+			-- Current audit assignment (latest audit_tracking row)
+			au.audit_status,
+			au.assignee_name,
+			TO_CHAR(au.audit_date, 'YYYY-MM-DD') as audit_date
 		FROM officers o
 		LEFT JOIN loans l ON o.officer_id = l.officer_id
 		LEFT JOIN loan_repayments lr ON l.loan_id = lr.loan_id
+		LEFT JOIN LATERAL (
+			SELECT at.audit_status, at.assignee_name, at.audit_date
+			FROM audit_tracking at
+			WHERE at.officer_id = o.officer_id
+			ORDER BY COALESCE(at.updated_at, at.created_at) DESC
+			LIMIT 1
+		) au ON TRUE
 		WHERE o.officer_id = $1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-		GROUP BY o.officer_id, o.officer_name, o.region, o.branch, o.primary_channel, o.user_type, o.hire_date, o.supervisor_email, o.supervisor_name, o.vertical_lead_email, o.vertical_lead_name
+			AND ` + r.userTypeFilter() + `
+		GROUP BY o.officer_id, o.officer_name, o.region, o.branch, o.primary_channel, o.user_type, o.hire_date, o.supervisor_email, o.supervisor_name, o.vertical_lead_email, o.vertical_lead_name,
+			au.audit_status, au.assignee_name, au.audit_date
 	`
 
 	officer := &models.DashboardOfficerMetrics{
@@ -596,6 +1028,7 @@ func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.Dashboar
 		&officer.Channel,
 		&officer.UserType,
 		&officer.HireDate,
+		&officer.TenureBucket,
 		&supervisorEmail,
 		&supervisorName,
 		&verticalLeadEmail,
@@ -622,8 +1055,14 @@ func (r *DashboardRepository) GetOfficerByID(officerID string) (*models.Dashboar
 		&officer.RawMetrics.AvgDaysSinceLastRepayment,
 		&officer.RawMetrics.AvgLoanAge,
 		&officer.RawMetrics.ActiveLoansCount,
+		&officer.AuditStatus,
+		&officer.AssigneeName,
+		&officer.AuditDate,
 	)
 
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("officer %s %w", officerID, ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -675,11 +1114,12 @@ func (r *DashboardRepository) GetFIMRLoans(filters map[string]interface{}) ([]*m
 			l.channel,
 				l.status,
 				l.django_status,
-				l.fimr_tagged as fimr_tagged
+				l.fimr_tagged as fimr_tagged,
+				` + r.fimrRecoveredSQL() + ` as fimr_recovered
 		FROM loans l
 		JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.fimr_tagged = true
-	`
+	` + r.excludedOfficerFilter()
 
 	args := []interface{}{}
 	argCount := 1
@@ -723,59 +1163,34 @@ func (r *DashboardRepository) GetFIMRLoans(filters map[string]interface{}) ([]*m
 	}
 
 	if status, ok := filters["status"].(string); ok && status != "" {
-		query += fmt.Sprintf(" AND l.status = $%d", argCount)
+		query += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", argCount)
 		args = append(args, status)
 		argCount++
 	}
 
 	// Raw Django status filter - supports comma-separated values and optional missing sentinel
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.django_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, s)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &argCount); cond != "" {
+			query += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
 
+	// fimr_recovered=false leaves the FIMR loans collections still has to chase
+	if recovered, ok := filters["fimr_recovered"].(bool); ok {
+		if recovered {
+			query += " AND " + r.fimrRecoveredSQL()
+		} else {
+			query += " AND NOT " + r.fimrRecoveredSQL()
+		}
+	}
+
 	// Apply sorting
 	sortBy := "l.disbursement_date"
 	if sort, ok := filters["sort_by"].(string); ok && sort != "" {
@@ -826,6 +1241,7 @@ func (r *DashboardRepository) GetFIMRLoans(filters map[string]interface{}) ([]*m
 			&loan.Status,
 			&djangoStatus,
 			&loan.FIMRTagged,
+			&loan.FIMRRecovered,
 		)
 		if err != nil {
 			return nil, err
@@ -852,48 +1268,133 @@ func (r *DashboardRepository) GetFIMRLoans(filters map[string]interface{}) ([]*m
 	return loans, nil
 }
 
-// GetEarlyIndicatorLoans retrieves loans in early delinquency (DPD 1-30)
-func (r *DashboardRepository) GetEarlyIndicatorLoans(filters map[string]interface{}) ([]*models.EarlyIndicatorLoan, error) {
-	query := `
+// GetUpcomingFIMRLoans returns open loans whose first installment hasn't been
+// received and is due within the next daysAhead days, or is already due but
+// still inside the FIMR grace period (DashboardConfig.FIMRGracePeriodDays),
+// soonest first. Loans already tagged FIMR are excluded.
+func (r *DashboardRepository) GetUpcomingFIMRLoans(filters map[string]interface{}, daysAhead int) ([]*models.UpcomingFIMRLoan, int, error) {
+	limit, offset, err := r.pageBounds(filters, 50)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	loanFilters, args, argCount := buildLoanFilters(filters, 3)
+	args = append([]interface{}{r.cfg.FIMRGracePeriodDays, daysAhead}, args...)
+
+	fromWhere := fmt.Sprintf(`
+		FROM loans l
+		INNER JOIN officers o ON l.officer_id = o.officer_id
+		WHERE l.first_payment_received_date IS NULL
+			AND l.first_payment_due_date::date >= CURRENT_DATE - $1::int
+			AND l.first_payment_due_date::date <= CURRENT_DATE + $2::int
+			AND COALESCE(l.fimr_tagged, false) = false
+			AND %s
+			AND %s
+			%s`, r.openStatusFilter(), r.userTypeFilter(), loanFilters)
+
+	// Counted separately so the total is still reported for a page past the
+	// last loan.
+	total := 0
+	if err := r.db.QueryRow("SELECT COUNT(*)"+fromWhere, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count upcoming FIMR loans: %w", err)
+	}
+
+	query := fmt.Sprintf(`
 		SELECT
 			l.loan_id,
 			l.officer_id,
-			o.officer_name as officer_name,
-			l.region,
-			l.branch,
-			l.customer_id,
-			l.customer_name,
-			l.customer_phone,
-			l.disbursement_date,
-			l.loan_amount,
-			l.current_dpd,
-			'Current' as previous_dpd_status,
-			0 as days_in_current_status,
-			l.total_outstanding as amount_due,
-			l.total_principal_paid + l.total_interest_paid + l.total_fees_paid as amount_paid,
-			l.principal_outstanding as outstanding_balance,
-			l.channel,
-			l.status,
-			l.fimr_tagged as fimr_tagged,
-			'Stable' as roll_direction,
-			(SELECT MAX(r.payment_date) FROM repayments r WHERE r.loan_id = l.loan_id AND NOT r.is_reversed) as last_payment_date
-		FROM loans l
-		JOIN officers o ON l.officer_id = o.officer_id
-		WHERE l.current_dpd BETWEEN 1 AND 30
-	`
+			COALESCE(o.officer_name, l.officer_name, '') AS officer_name,
+			COALESCE(o.officer_phone, '') AS officer_phone,
+			COALESCE(l.region, '') AS region,
+			COALESCE(l.branch, '') AS branch,
+			COALESCE(l.customer_id, '') AS customer_id,
+			COALESCE(l.customer_name, '') AS customer_name,
+			COALESCE(l.customer_phone, '') AS customer_phone,
+			TO_CHAR(l.disbursement_date, 'YYYY-MM-DD') AS disbursement_date,
+			COALESCE(l.loan_amount, 0)::float AS loan_amount,
+			TO_CHAR(l.first_payment_due_date, 'YYYY-MM-DD') AS first_payment_due_date,
+			(l.first_payment_due_date::date - CURRENT_DATE) AS days_until_due,
+			COALESCE(`+r.dailyRepaymentSQL()+`, 0)::float AS amount_due_1st_installment,
+			COALESCE(l.channel, '') AS channel,
+			COALESCE(l.django_status, '') AS django_status
+		%s
+		ORDER BY l.first_payment_due_date, l.loan_amount DESC, l.loan_id
+		LIMIT $%d OFFSET $%d
+	`, fromWhere, argCount, argCount+1)
+	args = append(args, limit, offset)
 
-	args := []interface{}{}
-	argCount := 1
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve upcoming FIMR loans: %w", err)
+	}
+	defer rows.Close()
+
+	loans := []*models.UpcomingFIMRLoan{}
+	for rows.Next() {
+		loan := &models.UpcomingFIMRLoan{}
+		var disbursementDate sql.NullString
+		if err := rows.Scan(
+			&loan.LoanID,
+			&loan.OfficerID,
+			&loan.OfficerName,
+			&loan.OfficerPhone,
+			&loan.Region,
+			&loan.Branch,
+			&loan.CustomerID,
+			&loan.CustomerName,
+			&loan.CustomerPhone,
+			&disbursementDate,
+			&loan.LoanAmount,
+			&loan.FirstPaymentDueDate,
+			&loan.DaysUntilDue,
+			&loan.AmountDue1stInstallment,
+			&loan.Channel,
+			&loan.DjangoStatus,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan upcoming FIMR loan: %w", err)
+		}
+		loan.DisbursementDate = disbursementDate.String
+		loans = append(loans, loan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return loans, total, nil
+}
+
+// earlyIndicatorRollDirectionSQL classifies a loan l by how its DPD moved
+// since the previous day's snapshot (previous_dpd). Loans without a snapshot
+// count as Stable.
+const earlyIndicatorRollDirectionSQL = `CASE
+			WHEN l.previous_dpd IS NULL OR l.current_dpd = l.previous_dpd THEN 'Stable'
+			WHEN l.current_dpd > l.previous_dpd THEN 'Worsening'
+			ELSE 'Improving'
+		END`
+
+// earlyIndicatorBandSQL buckets a loan l in early delinquency into the DPD
+// sub-bands used by the early-indicator status filter.
+const earlyIndicatorBandSQL = `CASE
+			WHEN l.current_dpd BETWEEN 1 AND 3 THEN 'D1-3'
+			WHEN l.current_dpd BETWEEN 4 AND 6 THEN 'D4-6'
+			WHEN l.current_dpd BETWEEN 7 AND 15 THEN 'D7-15'
+			ELSE 'D16-30'
+		END`
+
+// earlyIndicatorFilters builds the WHERE conditions shared by the
+// early-indicator loan list and summary, starting at placeholder argCount.
+func earlyIndicatorFilters(filters map[string]interface{}, argCount int) (string, []interface{}) {
+	where := ""
+	args := []interface{}{}
 
-	// Apply filters
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
+		where += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
 		args = append(args, officerID)
 		argCount++
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
-		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
+		where += fmt.Sprintf(" AND l.branch = $%d", argCount)
 		args = append(args, branch)
 		argCount++
 	}
@@ -902,7 +1403,7 @@ func (r *DashboardRepository) GetEarlyIndicatorLoans(filters map[string]interfac
 		// Support comma-separated regions for multi-select
 		regions := strings.Split(region, ",")
 		if len(regions) == 1 {
-			query += fmt.Sprintf(" AND l.region = $%d", argCount)
+			where += fmt.Sprintf(" AND l.region = $%d", argCount)
 			args = append(args, regions[0])
 			argCount++
 		} else {
@@ -913,12 +1414,12 @@ func (r *DashboardRepository) GetEarlyIndicatorLoans(filters map[string]interfac
 				args = append(args, strings.TrimSpace(r))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
+			where += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	if channel, ok := filters["channel"].(string); ok && channel != "" {
-		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
+		where += fmt.Sprintf(" AND l.channel = $%d", argCount)
 		args = append(args, channel)
 		argCount++
 	}
@@ -927,22 +1428,58 @@ func (r *DashboardRepository) GetEarlyIndicatorLoans(filters map[string]interfac
 		// Status filter for DPD ranges
 		switch status {
 		case "D1-3":
-			query += " AND l.current_dpd BETWEEN 1 AND 3"
+			where += " AND l.current_dpd BETWEEN 1 AND 3"
 		case "D4-6":
-			query += " AND l.current_dpd BETWEEN 4 AND 6"
+			where += " AND l.current_dpd BETWEEN 4 AND 6"
 		case "D7-15":
-			query += " AND l.current_dpd BETWEEN 7 AND 15"
+			where += " AND l.current_dpd BETWEEN 7 AND 15"
 		case "D16-30":
-			query += " AND l.current_dpd BETWEEN 16 AND 30"
+			where += " AND l.current_dpd BETWEEN 16 AND 30"
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		where += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
 
+	return where, args
+}
+
+// GetEarlyIndicatorLoans retrieves loans in early delinquency (DPD 1-30)
+func (r *DashboardRepository) GetEarlyIndicatorLoans(filters map[string]interface{}) ([]*models.EarlyIndicatorLoan, error) {
+	query := `
+		SELECT
+			l.loan_id,
+			l.officer_id,
+			o.officer_name as officer_name,
+			l.region,
+			l.branch,
+			l.customer_id,
+			l.customer_name,
+			l.customer_phone,
+			l.disbursement_date,
+			l.loan_amount,
+			l.current_dpd,
+			'Current' as previous_dpd_status,
+			0 as days_in_current_status,
+			l.total_outstanding as amount_due,
+			l.total_principal_paid + l.total_interest_paid + l.total_fees_paid as amount_paid,
+			l.principal_outstanding as outstanding_balance,
+			l.channel,
+			l.status,
+			l.fimr_tagged as fimr_tagged,
+			` + earlyIndicatorRollDirectionSQL + ` as roll_direction,
+			(SELECT MAX(r.payment_date) FROM repayments r WHERE r.loan_id = l.loan_id AND ` + reversalFilterSQL(false) + `) as last_payment_date
+		FROM loans l
+		JOIN officers o ON l.officer_id = o.officer_id
+		WHERE l.current_dpd BETWEEN 1 AND 30
+	` + r.excludedOfficerFilter()
+
+	where, args := earlyIndicatorFilters(filters, 1)
+	query += where
+
 	// Apply sorting
 	sortBy := "l.current_dpd"
 	if sort, ok := filters["sort_by"].(string); ok && sort != "" {
@@ -1002,8 +1539,134 @@ func (r *DashboardRepository) GetEarlyIndicatorLoans(filters map[string]interfac
 	return loans, nil
 }
 
-// GetLoansSummaryMetrics calculates summary metrics for all loans matching the given filters
+// EarlyIndicatorBands and EarlyIndicatorRollDirections list the summary
+// breakdown keys in display order
+var (
+	EarlyIndicatorBands          = []string{"D1-3", "D4-6", "D7-15", "D16-30"}
+	EarlyIndicatorRollDirections = []string{"Worsening", "Stable", "Improving"}
+)
+
+// GetEarlyIndicatorSummaryMetrics aggregates the loans GetEarlyIndicatorLoans
+// would return, in one query: totals plus count and exposure per DPD sub-band
+// and per roll direction. Every band and direction is present, zero-filled.
+func (r *DashboardRepository) GetEarlyIndicatorSummaryMetrics(filters map[string]interface{}) (*models.EarlyIndicatorSummaryMetrics, error) {
+	where, args := earlyIndicatorFilters(filters, 1)
+
+	query := `
+		WITH early AS (
+			SELECT
+				` + earlyIndicatorBandSQL + ` AS band,
+				` + earlyIndicatorRollDirectionSQL + ` AS roll_direction,
+				l.loan_amount,
+				l.principal_outstanding
+			FROM loans l
+			JOIN officers o ON l.officer_id = o.officer_id
+			WHERE l.current_dpd BETWEEN 1 AND 30
+				` + r.excludedOfficerFilter() + `
+				` + where + `
+		)
+		SELECT
+			band,
+			roll_direction,
+			COUNT(*),
+			COALESCE(SUM(loan_amount), 0),
+			COALESCE(SUM(principal_outstanding), 0)
+		FROM early
+		GROUP BY GROUPING SETS ((band), (roll_direction), ())
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get early indicator summary: %w", err)
+	}
+	defer rows.Close()
+
+	bands := map[string]*models.EarlyIndicatorBreakdown{}
+	directions := map[string]*models.EarlyIndicatorBreakdown{}
+	summary := &models.EarlyIndicatorSummaryMetrics{}
+
+	for rows.Next() {
+		var band, direction sql.NullString
+		entry := &models.EarlyIndicatorBreakdown{}
+		if err := rows.Scan(&band, &direction, &entry.LoanCount, &entry.TotalAmount, &entry.TotalOutstanding); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case band.Valid:
+			entry.Key = band.String
+			bands[band.String] = entry
+		case direction.Valid:
+			entry.Key = direction.String
+			directions[direction.String] = entry
+		default:
+			summary.TotalLoans = entry.LoanCount
+			summary.TotalAmount = entry.TotalAmount
+			summary.TotalOutstanding = entry.TotalOutstanding
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	summary.ByBand = orderedEarlyIndicatorBreakdown(EarlyIndicatorBands, bands)
+	summary.ByRollDirection = orderedEarlyIndicatorBreakdown(EarlyIndicatorRollDirections, directions)
+	for _, entry := range summary.ByRollDirection {
+		switch entry.Key {
+		case "Worsening":
+			summary.Worsening = entry.LoanCount
+		case "Stable":
+			summary.Stable = entry.LoanCount
+		case "Improving":
+			summary.Improving = entry.LoanCount
+		}
+	}
+
+	return summary, nil
+}
+
+// orderedEarlyIndicatorBreakdown returns the entries for keys in order,
+// zero-filling keys that had no loans.
+func orderedEarlyIndicatorBreakdown(keys []string, entries map[string]*models.EarlyIndicatorBreakdown) []*models.EarlyIndicatorBreakdown {
+	ordered := make([]*models.EarlyIndicatorBreakdown, 0, len(keys))
+	for _, key := range keys {
+		entry, ok := entries[key]
+		if !ok {
+			entry = &models.EarlyIndicatorBreakdown{Key: key}
+		}
+		ordered = append(ordered, entry)
+	}
+	return ordered
+}
+
+// loansSummarySubMetricFields maps each GetLoansSummaryMetrics sub-metric
+// query to the response fields derived from it.
+var loansSummarySubMetricFields = map[string][]string{
+	"portfolio": {
+		"total_loans", "distinct_customers", "total_portfolio_amount", "at_risk_loans", "portfolio_health",
+		"total_amount_in_dpd", "critical_loans", "repayment_delay_categories",
+		"total_due_for_today", "past_maturity_outstanding", "percentage_of_due_collected",
+	},
+	"repayments_today":            {"total_repayments_today", "percentage_of_due_collected"},
+	"repayments_yesterday":        {"total_repayments_yesterday"},
+	"repayments_by_django_status": {"repayments_by_django_status"},
+	"missed_today":                {"missed_repayments_today", "missed_repayments_today_count"},
+}
+
+// GetLoansSummaryMetrics calculates summary metrics for all loans matching the given filters.
+//
+// Each sub-metric query (portfolio, repayments today, repayments yesterday,
+// repayments by django_status, missed today) runs independently. When one
+// fails its fields are returned as null, "partial" is set and the sub-metric
+// is listed in "unavailable_metrics", so a transient failure doesn't take down
+// the whole loans page. An error is returned only if every sub-metric fails.
 func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interface{}) (map[string]interface{}, error) {
+	unavailable := []string{}
+	subMetricFailed := func(name string, err error) {
+		log.Printf("⚠️  Loans summary: %s unavailable: %v", name, err)
+		unavailable = append(unavailable, name)
+	}
+
 	// Determine requested period for period-based metrics (currently used for
 	// repayments-only aggregates further down). Defaults to "today" semantics
 	// if not specified.
@@ -1019,16 +1682,19 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	query := `
 			SELECT
 				COUNT(*) as total_loans,
+				COUNT(DISTINCT l.customer_id) as distinct_customers,
 				COALESCE(SUM(l.loan_amount), 0) as total_portfolio_amount,
 				COALESCE(SUM(CASE WHEN l.current_dpd > 14 THEN 1 ELSE 0 END), 0) as at_risk_count,
 				COALESCE(SUM(CASE WHEN l.current_dpd > 14 THEN l.loan_amount ELSE 0 END), 0) as at_risk_amount,
 				COALESCE(SUM(CASE WHEN l.current_dpd > 14 THEN l.actual_outstanding ELSE 0 END), 0) as at_risk_outstanding,
 				COALESCE(SUM(CASE WHEN l.current_dpd > 0 THEN l.actual_outstanding ELSE 0 END), 0) as total_amount_in_dpd,
 				COALESCE(SUM(CASE WHEN l.current_dpd > 21 THEN 1 ELSE 0 END), 0) as critical_count,
-				COALESCE(SUM(CASE WHEN l.repayment_delay_rate >= 80 THEN 1 ELSE 0 END), 0) as excellent_delay_count,
-				COALESCE(SUM(CASE WHEN l.repayment_delay_rate >= 40 AND l.repayment_delay_rate < 80 THEN 1 ELSE 0 END), 0) as okay_delay_count,
-				COALESCE(SUM(CASE WHEN l.repayment_delay_rate < 40 THEN 1 ELSE 0 END), 0) as critical_delay_count,
-				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN l.daily_repayment_amount ELSE 0 END), 0) as total_due_for_today,
+				-- Repayment health bands; $1/$2 are the configured excellent/okay thresholds
+				COALESCE(SUM(CASE WHEN l.repayment_delay_rate >= $1 THEN 1 ELSE 0 END), 0) as excellent_delay_count,
+				COALESCE(SUM(CASE WHEN l.repayment_delay_rate >= $2 AND l.repayment_delay_rate < $1 THEN 1 ELSE 0 END), 0) as okay_delay_count,
+				COALESCE(SUM(CASE WHEN l.repayment_delay_rate < $2 THEN 1 ELSE 0 END), 0) as critical_delay_count,
+				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN ` + r.dailyRepaymentSQL() + ` ELSE 0 END), 0) as total_due_for_today,
+				COUNT(CASE WHEN l.actual_outstanding > 0 AND ` + r.dailyRepaymentFallbackUsedSQL() + ` THEN 1 END) as daily_repayment_fallback_count,
 				COALESCE(SUM(
 					CASE
 						-- Past maturity outstanding: all loans for which today is past
@@ -1046,17 +1712,29 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 			FROM loans l
 			JOIN officers o ON l.officer_id = o.officer_id
 			WHERE 1=1
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+				AND ` + r.userTypeFilter() + `
 			`
 
-	args := []interface{}{}
-	argCount := 1
+	args := []interface{}{r.cfg.DelayRateExcellentThreshold, r.cfg.DelayRateOkayThreshold}
+	argCount := 3
 
 	// Apply the same filters as GetAllLoans
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
-		args = append(args, officerID)
-		argCount++
+		// Support comma-separated officer IDs for multi-select
+		officerIDs := strings.Split(officerID, ",")
+		if len(officerIDs) == 1 {
+			query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
+			args = append(args, officerIDs[0])
+			argCount++
+		} else {
+			placeholders := []string{}
+			for _, id := range officerIDs {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+				args = append(args, strings.TrimSpace(id))
+				argCount++
+			}
+			query += fmt.Sprintf(" AND l.officer_id IN (%s)", strings.Join(placeholders, ", "))
+		}
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
@@ -1091,105 +1769,37 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if status, ok := filters["status"].(string); ok && status != "" {
 		statuses := strings.Split(status, ",")
 		if len(statuses) == 1 {
-			query += fmt.Sprintf(" AND l.status = $%d", argCount)
+			query += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", argCount)
 			args = append(args, statuses[0])
 			argCount++
 		} else {
 			placeholders := []string{}
 			for _, s := range statuses {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
-				args = append(args, strings.TrimSpace(s))
+				args = append(args, strings.ToUpper(strings.TrimSpace(s)))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.status IN (%s)", strings.Join(placeholders, ", "))
+			query += fmt.Sprintf(" AND UPPER(l.status) IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	// Raw Django status filter - supports comma-separated values and optional missing sentinel
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.django_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, s)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &argCount); cond != "" {
+			query += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
-		performanceStatuses := strings.Split(performanceStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, ps := range performanceStatuses {
-			value := strings.TrimSpace(ps)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.performance_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, ps := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, ps)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.performance_status IN (%s)", strings.Join(placeholders, ", ")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.performance_status IS NULL OR l.performance_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.performance_status", performanceStatus, &argCount); cond != "" {
+			query += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
@@ -1206,87 +1816,31 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 		argCount++
 	}
 
-	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
-		// Support comma-separated values for multiple loan types, including a sentinel for missing values
-		loanTypes := strings.Split(loanType, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, lt := range loanTypes {
-			value := strings.TrimSpace(lt)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.loan_type = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, lt := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, lt)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.loan_type IN (%s)", strings.Join(placeholders, ",")))
+	if supervisorEmail, ok := filters["supervisor_email"].(string); ok && supervisorEmail != "" {
+		if cond, condArgs := supervisorFilter(supervisorEmail, &argCount); cond != "" {
+			query += " AND " + cond
+			args = append(args, condArgs...)
 		}
+	}
 
-		if includeMissing {
-			conditions = append(conditions, "(l.loan_type IS NULL OR l.loan_type = '')")
-		}
+	if verticalLeadName, ok := filters["vertical_lead_name"].(string); ok && verticalLeadName != "" {
+		clause, nameArgs, next := verticalLeadNameFilter(verticalLeadName, argCount)
+		query += clause
+		args = append(args, nameArgs...)
+		argCount = next
+	}
 
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &argCount); cond != "" {
+			query += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
-		// Support comma-separated values for multiple verification statuses, including a sentinel for missing values
-		verificationStatuses := strings.Split(verificationStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, vs := range verificationStatuses {
-			value := strings.TrimSpace(vs)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.verification_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, vs := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, vs)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.verification_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.verification_status IS NULL OR l.verification_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &argCount); cond != "" {
+			query += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
@@ -1309,15 +1863,18 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if quietLoans, ok := filters["quiet_loans"].(bool); ok && quietLoans {
 		query += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
 	}
+	if reviewFlagged, ok := filters["review_flagged"].(bool); ok && reviewFlagged {
+		query += " AND " + reviewFlaggedLoanSQL
+	}
 
 	// Behavior-based filters (active/inactive/overdue_15d, early/late ROT, risky delay)
 	// kept in sync with GetAllLoans so summary metrics match the table and exports.
 	if behaviorLoanType, ok := filters["behavior_loan_type"].(string); ok && behaviorLoanType != "" {
 		switch behaviorLoanType {
 		case "active":
-			query += " AND l.total_outstanding > 2000 AND COALESCE(l.days_since_last_repayment, 0) < 6"
+			query += " AND " + r.activeLoanSQL()
 		case "inactive":
-			query += " AND (l.total_outstanding <= 2000 OR COALESCE(l.days_since_last_repayment, 0) > 5)"
+			query += " AND " + r.inactiveLoanSQL()
 		case "overdue_15d":
 			query += " AND l.current_dpd > 15"
 		}
@@ -1329,21 +1886,28 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 			query += " AND (CURRENT_DATE - l.disbursement_date::date) < 7 AND l.current_dpd > 4"
 		case "late":
 			query += " AND (CURRENT_DATE - l.disbursement_date::date) >= 7 AND l.current_dpd > 4"
+		case "unknown_age":
+			query += " AND l.disbursement_date IS NULL AND l.current_dpd > 4"
 		}
 	}
 
 	if delayType, ok := filters["delay_type"].(string); ok && delayType != "" {
 		if delayType == "risky" {
-			query += " AND l.status = 'Active' AND l.total_outstanding > 2000 AND l.repayment_delay_rate IS NOT NULL AND l.repayment_delay_rate < 60"
+			query += fmt.Sprintf(" AND UPPER(l.status) = 'ACTIVE' AND l.total_outstanding > 2000 AND l.repayment_delay_rate IS NOT NULL AND l.repayment_delay_rate < $%d", argCount)
+			args = append(args, r.cfg.DelayRateRiskyThreshold)
+			argCount++
 		}
 	}
 
 	// Execute query
-	var totalLoans, atRiskCount, criticalCount, excellentDelayCount, okayDelayCount, criticalDelayCount, performingLoansCount int
-	var totalPortfolioAmount, atRiskAmount, atRiskOutstanding, totalAmountInDPD, totalDueForToday, pastMaturityOutstanding, performingActualOutstanding float64
+	var totalLoans, distinctCustomers, atRiskCount, criticalCount, excellentDelayCount, okayDelayCount, criticalDelayCount, performingLoansCount, dailyRepaymentFallbackCount int
+	// Money totals are scanned exactly from the NUMERIC sums (see models.Money)
+	var totalPortfolioAmount, atRiskAmount, atRiskOutstanding, totalAmountInDPD, totalDueForToday, pastMaturityOutstanding, performingActualOutstanding models.Money
 
+	queryStart := time.Now()
 	err := r.db.QueryRow(query, args...).Scan(
 		&totalLoans,
+		&distinctCustomers,
 		&totalPortfolioAmount,
 		&atRiskCount,
 		&atRiskAmount,
@@ -1354,12 +1918,18 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 		&okayDelayCount,
 		&criticalDelayCount,
 		&totalDueForToday,
+		&dailyRepaymentFallbackCount,
 		&pastMaturityOutstanding,
 		&performingLoansCount,
 		&performingActualOutstanding,
 	)
+	r.observeQuery("loans_summary.portfolio", query, args, queryStart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate summary metrics: %w", err)
+		subMetricFailed("portfolio", fmt.Errorf("failed to calculate summary metrics: %w", err))
+	}
+
+	if dailyRepaymentFallbackCount > 0 {
+		log.Printf("ℹ️  %d loans with no daily_repayment_amount used the repayment_amount / loan_term_days fallback for due today", dailyRepaymentFallbackCount)
 	}
 
 	// Build a base WHERE clause to sum repayments made in the requested period for
@@ -1369,43 +1939,37 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 			FROM repayments r
 			INNER JOIN loans l ON r.loan_id = l.loan_id
 			INNER JOIN officers o ON l.officer_id = o.officer_id
-			WHERE r.is_reversed = false
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			WHERE ` + reversalFilterSQL(false) + `
+				AND ` + r.userTypeFilter() + `
 		`
 
 	// Apply period restriction on repayment dates. This affects only the repayments
 	// aggregation; loan-level metrics (e.g. total_due_for_today) remain as currently
 	// defined until collections-specific period handling is implemented for them.
-	switch period {
-	case "this_week":
-		repaymentsWhere += `
-				AND DATE(r.payment_date) >= DATE_TRUNC('week', CURRENT_DATE)::date
-				AND DATE(r.payment_date) <= CURRENT_DATE
-			`
-	case "this_month":
-		repaymentsWhere += `
-				AND DATE(r.payment_date) >= DATE_TRUNC('month', CURRENT_DATE)::date
-				AND DATE(r.payment_date) <= CURRENT_DATE
-			`
-	case "last_month":
-		repaymentsWhere += `
-				AND DATE(r.payment_date) >= (DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '1 month')::date
-				AND DATE(r.payment_date) < DATE_TRUNC('month', CURRENT_DATE)::date
-			`
-	default: // "today" or any unrecognised value
-		repaymentsWhere += `
-				AND DATE(r.payment_date) = CURRENT_DATE
-			`
-	}
+	// Unrecognised periods fall back to today (see collectionsPeriodRange).
+	periodStart, periodEnd := collectionsPeriodRange(period)
+	repaymentsWhere += " AND " + periodDateFilter("DATE(r.payment_date)", periodStart, periodEnd)
 
 	// Apply the same filters to the repayments WHERE clause
 	repaymentsArgs := []interface{}{}
 	repaymentsArgCount := 1
 
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		repaymentsWhere += fmt.Sprintf(" AND l.officer_id = $%d", repaymentsArgCount)
-		repaymentsArgs = append(repaymentsArgs, officerID)
-		repaymentsArgCount++
+		// Support comma-separated officer IDs for multi-select
+		officerIDs := strings.Split(officerID, ",")
+		if len(officerIDs) == 1 {
+			repaymentsWhere += fmt.Sprintf(" AND l.officer_id = $%d", repaymentsArgCount)
+			repaymentsArgs = append(repaymentsArgs, officerIDs[0])
+			repaymentsArgCount++
+		} else {
+			placeholders := []string{}
+			for _, id := range officerIDs {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", repaymentsArgCount))
+				repaymentsArgs = append(repaymentsArgs, strings.TrimSpace(id))
+				repaymentsArgCount++
+			}
+			repaymentsWhere += fmt.Sprintf(" AND l.officer_id IN (%s)", strings.Join(placeholders, ", "))
+		}
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
@@ -1440,81 +2004,37 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if status, ok := filters["status"].(string); ok && status != "" {
 		statuses := strings.Split(status, ",")
 		if len(statuses) == 1 {
-			repaymentsWhere += fmt.Sprintf(" AND l.status = $%d", repaymentsArgCount)
+			repaymentsWhere += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", repaymentsArgCount)
 			repaymentsArgs = append(repaymentsArgs, statuses[0])
 			repaymentsArgCount++
 		} else {
 			placeholders := []string{}
 			for _, s := range statuses {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", repaymentsArgCount))
-				repaymentsArgs = append(repaymentsArgs, strings.TrimSpace(s))
+				repaymentsArgs = append(repaymentsArgs, strings.ToUpper(strings.TrimSpace(s)))
 				repaymentsArgCount++
 			}
-			repaymentsWhere += fmt.Sprintf(" AND l.status IN (%s)", strings.Join(placeholders, ", "))
+			repaymentsWhere += fmt.Sprintf(" AND UPPER(l.status) IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	// Raw Django status filter - supports comma-separated values and optional missing sentinel
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &repaymentsArgCount); cond != "" {
+			repaymentsWhere += " AND " + cond
+			repaymentsArgs = append(repaymentsArgs, condArgs...)
 		}
+	}
 
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.django_status = $%d", repaymentsArgCount))
-			repaymentsArgs = append(repaymentsArgs, nonMissing[0])
-			repaymentsArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", repaymentsArgCount)
-				repaymentsArgs = append(repaymentsArgs, s)
-				repaymentsArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
+	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
+		if cond, condArgs := inListFilter("l.performance_status", performanceStatus, &repaymentsArgCount); cond != "" {
+			repaymentsWhere += " AND " + cond
+			repaymentsArgs = append(repaymentsArgs, condArgs...)
 		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			repaymentsWhere += " AND (" + strings.Join(conditions, " OR ") + ")"
-		}
-	}
-
-	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
-		performanceStatuses := strings.Split(performanceStatus, ",")
-		if len(performanceStatuses) == 1 {
-			repaymentsWhere += fmt.Sprintf(" AND l.performance_status = $%d", repaymentsArgCount)
-			repaymentsArgs = append(repaymentsArgs, performanceStatuses[0])
-			repaymentsArgCount++
-		} else {
-			placeholders := []string{}
-			for _, ps := range performanceStatuses {
-				placeholders = append(placeholders, fmt.Sprintf("$%d", repaymentsArgCount))
-				repaymentsArgs = append(repaymentsArgs, strings.TrimSpace(ps))
-				repaymentsArgCount++
-			}
-			repaymentsWhere += fmt.Sprintf(" AND l.performance_status IN (%s)", strings.Join(placeholders, ", "))
-		}
-	}
+	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		repaymentsWhere += fmt.Sprintf(" AND l.wave = $%d", repaymentsArgCount)
+		repaymentsWhere += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", repaymentsArgCount)
 		repaymentsArgs = append(repaymentsArgs, wave)
 		repaymentsArgCount++
 	}
@@ -1531,85 +2051,31 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 		repaymentsArgCount++
 	}
 
-	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
-		loanTypes := strings.Split(loanType, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, lt := range loanTypes {
-			value := strings.TrimSpace(lt)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.loan_type = $%d", repaymentsArgCount))
-			repaymentsArgs = append(repaymentsArgs, nonMissing[0])
-			repaymentsArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, lt := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", repaymentsArgCount)
-				repaymentsArgs = append(repaymentsArgs, lt)
-				repaymentsArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.loan_type IN (%s)", strings.Join(placeholders, ",")))
+	if supervisorEmail, ok := filters["supervisor_email"].(string); ok && supervisorEmail != "" {
+		if cond, condArgs := supervisorFilter(supervisorEmail, &repaymentsArgCount); cond != "" {
+			repaymentsWhere += " AND " + cond
+			repaymentsArgs = append(repaymentsArgs, condArgs...)
 		}
+	}
 
-		if includeMissing {
-			conditions = append(conditions, "(l.loan_type IS NULL OR l.loan_type = '')")
-		}
+	if verticalLeadName, ok := filters["vertical_lead_name"].(string); ok && verticalLeadName != "" {
+		clause, nameArgs, next := verticalLeadNameFilter(verticalLeadName, repaymentsArgCount)
+		repaymentsWhere += clause
+		repaymentsArgs = append(repaymentsArgs, nameArgs...)
+		repaymentsArgCount = next
+	}
 
-		if len(conditions) > 0 {
-			repaymentsWhere += " AND (" + strings.Join(conditions, " OR ") + ")"
+	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &repaymentsArgCount); cond != "" {
+			repaymentsWhere += " AND " + cond
+			repaymentsArgs = append(repaymentsArgs, condArgs...)
 		}
 	}
 
 	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
-		verificationStatuses := strings.Split(verificationStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, vs := range verificationStatuses {
-			value := strings.TrimSpace(vs)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.verification_status = $%d", repaymentsArgCount))
-			repaymentsArgs = append(repaymentsArgs, nonMissing[0])
-			repaymentsArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, vs := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", repaymentsArgCount)
-				repaymentsArgs = append(repaymentsArgs, vs)
-				repaymentsArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.verification_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.verification_status IS NULL OR l.verification_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			repaymentsWhere += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &repaymentsArgCount); cond != "" {
+			repaymentsWhere += " AND " + cond
+			repaymentsArgs = append(repaymentsArgs, condArgs...)
 		}
 	}
 
@@ -1630,16 +2096,21 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if quietLoans, ok := filters["quiet_loans"].(bool); ok && quietLoans {
 		repaymentsWhere += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
 	}
+	if reviewFlagged, ok := filters["review_flagged"].(bool); ok && reviewFlagged {
+		repaymentsWhere += " AND " + reviewFlaggedLoanSQL
+	}
 
 	// Overall total repayments in the period
 	repaymentsTotalQuery := `
 			SELECT COALESCE(SUM(r.payment_amount), 0) as total_repayments_today
 		` + repaymentsWhere
 
-	var totalRepaymentsToday float64
+	var totalRepaymentsToday models.Money
+	queryStart = time.Now()
 	err = r.db.QueryRow(repaymentsTotalQuery, repaymentsArgs...).Scan(&totalRepaymentsToday)
+	r.observeQuery("loans_summary.repayments_today", repaymentsTotalQuery, repaymentsArgs, queryStart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate today's repayments: %w", err)
+		subMetricFailed("repayments_today", fmt.Errorf("failed to calculate today's repayments: %w", err))
 	}
 
 	// Additionally calculate total repayments for "yesterday" (exactly one
@@ -1651,8 +2122,8 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 				FROM repayments r
 				INNER JOIN loans l ON r.loan_id = l.loan_id
 				INNER JOIN officers o ON l.officer_id = o.officer_id
-				WHERE r.is_reversed = false
-					AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+				WHERE ` + reversalFilterSQL(false) + `
+					AND ` + r.userTypeFilter() + `
 					AND DATE(r.payment_date) = CURRENT_DATE - INTERVAL '1 day'
 			`
 
@@ -1660,9 +2131,21 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	repaymentsYesterdayArgCount := 1
 
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		repaymentsWhereYesterday += fmt.Sprintf(" AND l.officer_id = $%d", repaymentsYesterdayArgCount)
-		repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, officerID)
-		repaymentsYesterdayArgCount++
+		// Support comma-separated officer IDs for multi-select
+		officerIDs := strings.Split(officerID, ",")
+		if len(officerIDs) == 1 {
+			repaymentsWhereYesterday += fmt.Sprintf(" AND l.officer_id = $%d", repaymentsYesterdayArgCount)
+			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, officerIDs[0])
+			repaymentsYesterdayArgCount++
+		} else {
+			placeholders := []string{}
+			for _, id := range officerIDs {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", repaymentsYesterdayArgCount))
+				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, strings.TrimSpace(id))
+				repaymentsYesterdayArgCount++
+			}
+			repaymentsWhereYesterday += fmt.Sprintf(" AND l.officer_id IN (%s)", strings.Join(placeholders, ", "))
+		}
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
@@ -1697,100 +2180,37 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if status, ok := filters["status"].(string); ok && status != "" {
 		statuses := strings.Split(status, ",")
 		if len(statuses) == 1 {
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.status = $%d", repaymentsYesterdayArgCount)
+			repaymentsWhereYesterday += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", repaymentsYesterdayArgCount)
 			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, statuses[0])
 			repaymentsYesterdayArgCount++
 		} else {
 			placeholders := []string{}
 			for _, s := range statuses {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", repaymentsYesterdayArgCount))
-				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, strings.TrimSpace(s))
+				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, strings.ToUpper(strings.TrimSpace(s)))
 				repaymentsYesterdayArgCount++
 			}
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.status IN (%s)", strings.Join(placeholders, ", "))
+			repaymentsWhereYesterday += fmt.Sprintf(" AND UPPER(l.status) IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	// Raw Django status filter - supports comma-separated values and optional missing sentinel
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.django_status = $%d", repaymentsYesterdayArgCount)
-			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, nonMissing[0])
-			repaymentsYesterdayArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", repaymentsYesterdayArgCount)
-				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, s)
-				repaymentsYesterdayArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			repaymentsWhereYesterday += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &repaymentsYesterdayArgCount); cond != "" {
+			repaymentsWhereYesterday += " AND " + cond
+			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, condArgs...)
 		}
 	}
 
 	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
-		performanceStatuses := strings.Split(performanceStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, ps := range performanceStatuses {
-			value := strings.TrimSpace(ps)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		if len(nonMissing) == 1 {
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.performance_status = $%d", repaymentsYesterdayArgCount)
-			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, nonMissing[0])
-			repaymentsYesterdayArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := []string{}
-			for _, ps := range nonMissing {
-				placeholders = append(placeholders, fmt.Sprintf("$%d", repaymentsYesterdayArgCount))
-				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, strings.TrimSpace(ps))
-				repaymentsYesterdayArgCount++
-			}
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.performance_status IN (%s)", strings.Join(placeholders, ", "))
-		}
-
-		if includeMissing {
-			repaymentsWhereYesterday += " AND (l.performance_status IS NULL OR l.performance_status = '')"
+		if cond, condArgs := inListFilter("l.performance_status", performanceStatus, &repaymentsYesterdayArgCount); cond != "" {
+			repaymentsWhereYesterday += " AND " + cond
+			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, condArgs...)
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		repaymentsWhereYesterday += fmt.Sprintf(" AND l.wave = $%d", repaymentsYesterdayArgCount)
+		repaymentsWhereYesterday += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", repaymentsYesterdayArgCount)
 		repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, wave)
 		repaymentsYesterdayArgCount++
 	}
@@ -1807,85 +2227,31 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 		repaymentsYesterdayArgCount++
 	}
 
-	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
-		loanTypes := strings.Split(loanType, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, lt := range loanTypes {
-			value := strings.TrimSpace(lt)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.loan_type = $%d", repaymentsYesterdayArgCount)
-			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, nonMissing[0])
-			repaymentsYesterdayArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, lt := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", repaymentsYesterdayArgCount)
-				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, lt)
-				repaymentsYesterdayArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.loan_type IN (%s)", strings.Join(placeholders, ",")))
+	if supervisorEmail, ok := filters["supervisor_email"].(string); ok && supervisorEmail != "" {
+		if cond, condArgs := supervisorFilter(supervisorEmail, &repaymentsYesterdayArgCount); cond != "" {
+			repaymentsWhereYesterday += " AND " + cond
+			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, condArgs...)
 		}
+	}
 
-		if includeMissing {
-			conditions = append(conditions, "(l.loan_type IS NULL OR l.loan_type = '')")
-		}
+	if verticalLeadName, ok := filters["vertical_lead_name"].(string); ok && verticalLeadName != "" {
+		clause, nameArgs, next := verticalLeadNameFilter(verticalLeadName, repaymentsYesterdayArgCount)
+		repaymentsWhereYesterday += clause
+		repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, nameArgs...)
+		repaymentsYesterdayArgCount = next
+	}
 
-		if len(conditions) > 0 {
-			repaymentsWhereYesterday += " AND (" + strings.Join(conditions, " OR ") + ")"
+	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &repaymentsYesterdayArgCount); cond != "" {
+			repaymentsWhereYesterday += " AND " + cond
+			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, condArgs...)
 		}
 	}
 
 	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
-		verificationStatuses := strings.Split(verificationStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, vs := range verificationStatuses {
-			value := strings.TrimSpace(vs)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			repaymentsWhereYesterday += fmt.Sprintf(" AND l.verification_status = $%d", repaymentsYesterdayArgCount)
-			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, nonMissing[0])
-			repaymentsYesterdayArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, vs := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", repaymentsYesterdayArgCount)
-				repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, vs)
-				repaymentsYesterdayArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.verification_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.verification_status IS NULL OR l.verification_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			repaymentsWhereYesterday += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &repaymentsYesterdayArgCount); cond != "" {
+			repaymentsWhereYesterday += " AND " + cond
+			repaymentsYesterdayArgs = append(repaymentsYesterdayArgs, condArgs...)
 		}
 	}
 
@@ -1906,15 +2272,20 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if quietLoans, ok := filters["quiet_loans"].(bool); ok && quietLoans {
 		repaymentsWhereYesterday += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
 	}
+	if reviewFlagged, ok := filters["review_flagged"].(bool); ok && reviewFlagged {
+		repaymentsWhereYesterday += " AND " + reviewFlaggedLoanSQL
+	}
 
 	repaymentsYesterdayQuery := `
 				SELECT COALESCE(SUM(r.payment_amount), 0) as total_repayments_yesterday
 			` + repaymentsWhereYesterday
 
-	var totalRepaymentsYesterday float64
+	var totalRepaymentsYesterday models.Money
+	queryStart = time.Now()
 	err = r.db.QueryRow(repaymentsYesterdayQuery, repaymentsYesterdayArgs...).Scan(&totalRepaymentsYesterday)
+	r.observeQuery("loans_summary.repayments_yesterday", repaymentsYesterdayQuery, repaymentsYesterdayArgs, queryStart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate yesterday's repayments: %w", err)
+		subMetricFailed("repayments_yesterday", fmt.Errorf("failed to calculate yesterday's repayments: %w", err))
 	}
 
 	// Breakdown of repayments by django_status using the same filters and period.
@@ -1927,57 +2298,71 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 			ORDER BY amount DESC
 		`, MissingValueSentinel, repaymentsWhere, MissingValueSentinel)
 
-	repaymentsByStatus := []map[string]interface{}{}
-	rows, err := r.db.Query(repaymentsByStatusQuery, repaymentsArgs...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate repayments by django_status: %w", err)
-	}
-	defer rows.Close()
+	queryStart = time.Now()
+	repaymentsByStatus, err := func() ([]map[string]interface{}, error) {
+		rows, err := r.db.Query(repaymentsByStatusQuery, repaymentsArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate repayments by django_status: %w", err)
+		}
+		defer rows.Close()
 
-	for rows.Next() {
-		var status string
-		var amount float64
-		if scanErr := rows.Scan(&status, &amount); scanErr != nil {
-			return nil, fmt.Errorf("failed to scan repayments by django_status row: %w", scanErr)
+		byStatus := []map[string]interface{}{}
+		for rows.Next() {
+			var status string
+			var amount models.Money
+			if scanErr := rows.Scan(&status, &amount); scanErr != nil {
+				return nil, fmt.Errorf("failed to scan repayments by django_status row: %w", scanErr)
+			}
+			byStatus = append(byStatus, map[string]interface{}{
+				"django_status": status,
+				"amount":        amount,
+			})
 		}
-		repaymentsByStatus = append(repaymentsByStatus, map[string]interface{}{
-			"django_status": status,
-			"amount":        amount,
-		})
-	}
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate repayments by django_status rows: %w", err)
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to iterate repayments by django_status rows: %w", err)
+		}
+		return byStatus, nil
+	}()
+	r.observeQuery("loans_summary.repayments_by_status", repaymentsByStatusQuery, repaymentsArgs, queryStart)
+	if err != nil {
+		subMetricFailed("repayments_by_django_status", err)
 	}
 
-	// Calculate missed repayments today: loans that have a scheduled daily repayment
-	// today (same population as total_due_for_today) but have no repayment recorded
-	// for the current day. This uses the same filters as the loans and repayments
-	// summary so that amounts and counts stay aligned.
+	// Calculate missed repayments today: loans matching missedTodaySQL, the
+	// same condition as the per-loan missed_today flag. The amount is the part
+	// of today's installment that was not collected. This uses the same
+	// filters as the loans and repayments summary so that amounts and counts
+	// stay aligned.
 	missedQuery := `
 			SELECT
-				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN l.daily_repayment_amount ELSE 0 END), 0) AS missed_amount_today,
+				COALESCE(SUM(` + r.dailyRepaymentSQL() + ` - ` + collectedTodaySQL + `), 0) AS missed_amount_today,
 				COUNT(*) AS missed_count_today
 			FROM loans l
 			INNER JOIN officers o ON l.officer_id = o.officer_id
 			WHERE 1=1
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-				AND l.actual_outstanding > 0
-				AND NOT EXISTS (
-					SELECT 1
-					FROM repayments r
-					WHERE r.loan_id = l.loan_id
-						AND r.is_reversed = false
-						AND DATE(r.payment_date) = CURRENT_DATE
-				)
+				AND ` + r.userTypeFilter() + `
+				AND ` + r.missedTodaySQL() + `
 		`
 
 	missedArgs := []interface{}{}
 	missedArgCount := 1
 
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		missedQuery += fmt.Sprintf(" AND l.officer_id = $%d", missedArgCount)
-		missedArgs = append(missedArgs, officerID)
-		missedArgCount++
+		// Support comma-separated officer IDs for multi-select
+		officerIDs := strings.Split(officerID, ",")
+		if len(officerIDs) == 1 {
+			missedQuery += fmt.Sprintf(" AND l.officer_id = $%d", missedArgCount)
+			missedArgs = append(missedArgs, officerIDs[0])
+			missedArgCount++
+		} else {
+			placeholders := []string{}
+			for _, id := range officerIDs {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", missedArgCount))
+				missedArgs = append(missedArgs, strings.TrimSpace(id))
+				missedArgCount++
+			}
+			missedQuery += fmt.Sprintf(" AND l.officer_id IN (%s)", strings.Join(placeholders, ", "))
+		}
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
@@ -2012,81 +2397,37 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if status, ok := filters["status"].(string); ok && status != "" {
 		statuses := strings.Split(status, ",")
 		if len(statuses) == 1 {
-			missedQuery += fmt.Sprintf(" AND l.status = $%d", missedArgCount)
+			missedQuery += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", missedArgCount)
 			missedArgs = append(missedArgs, statuses[0])
 			missedArgCount++
 		} else {
 			placeholders := []string{}
 			for _, s := range statuses {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", missedArgCount))
-				missedArgs = append(missedArgs, strings.TrimSpace(s))
+				missedArgs = append(missedArgs, strings.ToUpper(strings.TrimSpace(s)))
 				missedArgCount++
 			}
-			missedQuery += fmt.Sprintf(" AND l.status IN (%s)", strings.Join(placeholders, ", "))
+			missedQuery += fmt.Sprintf(" AND UPPER(l.status) IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	// Raw Django status filter - supports comma-separated values and optional missing sentinel
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.django_status = $%d", missedArgCount))
-			missedArgs = append(missedArgs, nonMissing[0])
-			missedArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", missedArgCount)
-				missedArgs = append(missedArgs, s)
-				missedArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			missedQuery += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &missedArgCount); cond != "" {
+			missedQuery += " AND " + cond
+			missedArgs = append(missedArgs, condArgs...)
 		}
 	}
 
 	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
-		performanceStatuses := strings.Split(performanceStatus, ",")
-		if len(performanceStatuses) == 1 {
-			missedQuery += fmt.Sprintf(" AND l.performance_status = $%d", missedArgCount)
-			missedArgs = append(missedArgs, performanceStatuses[0])
-			missedArgCount++
-		} else {
-			placeholders := []string{}
-			for _, ps := range performanceStatuses {
-				placeholders = append(placeholders, fmt.Sprintf("$%d", missedArgCount))
-				missedArgs = append(missedArgs, strings.TrimSpace(ps))
-				missedArgCount++
-			}
-			missedQuery += fmt.Sprintf(" AND l.performance_status IN (%s)", strings.Join(placeholders, ", "))
+		if cond, condArgs := inListFilter("l.performance_status", performanceStatus, &missedArgCount); cond != "" {
+			missedQuery += " AND " + cond
+			missedArgs = append(missedArgs, condArgs...)
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		missedQuery += fmt.Sprintf(" AND l.wave = $%d", missedArgCount)
+		missedQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", missedArgCount)
 		missedArgs = append(missedArgs, wave)
 		missedArgCount++
 	}
@@ -2103,85 +2444,31 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 		missedArgCount++
 	}
 
-	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
-		loanTypes := strings.Split(loanType, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, lt := range loanTypes {
-			value := strings.TrimSpace(lt)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.loan_type = $%d", missedArgCount))
-			missedArgs = append(missedArgs, nonMissing[0])
-			missedArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, lt := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", missedArgCount)
-				missedArgs = append(missedArgs, lt)
-				missedArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.loan_type IN (%s)", strings.Join(placeholders, ",")))
+	if supervisorEmail, ok := filters["supervisor_email"].(string); ok && supervisorEmail != "" {
+		if cond, condArgs := supervisorFilter(supervisorEmail, &missedArgCount); cond != "" {
+			missedQuery += " AND " + cond
+			missedArgs = append(missedArgs, condArgs...)
 		}
+	}
 
-		if includeMissing {
-			conditions = append(conditions, "(l.loan_type IS NULL OR l.loan_type = '')")
-		}
+	if verticalLeadName, ok := filters["vertical_lead_name"].(string); ok && verticalLeadName != "" {
+		clause, nameArgs, next := verticalLeadNameFilter(verticalLeadName, missedArgCount)
+		missedQuery += clause
+		missedArgs = append(missedArgs, nameArgs...)
+		missedArgCount = next
+	}
 
-		if len(conditions) > 0 {
-			missedQuery += " AND (" + strings.Join(conditions, " OR ") + ")"
+	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &missedArgCount); cond != "" {
+			missedQuery += " AND " + cond
+			missedArgs = append(missedArgs, condArgs...)
 		}
 	}
 
 	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
-		verificationStatuses := strings.Split(verificationStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, vs := range verificationStatuses {
-			value := strings.TrimSpace(vs)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.verification_status = $%d", missedArgCount))
-			missedArgs = append(missedArgs, nonMissing[0])
-			missedArgCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, vs := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", missedArgCount)
-				missedArgs = append(missedArgs, vs)
-				missedArgCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.verification_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.verification_status IS NULL OR l.verification_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			missedQuery += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &missedArgCount); cond != "" {
+			missedQuery += " AND " + cond
+			missedArgs = append(missedArgs, condArgs...)
 		}
 	}
 
@@ -2202,34 +2489,40 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 	if quietLoans, ok := filters["quiet_loans"].(bool); ok && quietLoans {
 		missedQuery += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
 	}
+	if reviewFlagged, ok := filters["review_flagged"].(bool); ok && reviewFlagged {
+		missedQuery += " AND " + reviewFlaggedLoanSQL
+	}
 
-	var missedAmountToday float64
+	var missedAmountToday models.Money
 	var missedCountToday int
+	queryStart = time.Now()
 	err = r.db.QueryRow(missedQuery, missedArgs...).Scan(&missedAmountToday, &missedCountToday)
+	r.observeQuery("loans_summary.missed_today", missedQuery, missedArgs, queryStart)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate missed repayments today: %w", err)
+		subMetricFailed("missed_today", fmt.Errorf("failed to calculate missed repayments today: %w", err))
 	}
 
-	// Calculate percentages
-	atRiskPercentage := 0.0
-	if totalLoans > 0 {
-		atRiskPercentage = (float64(atRiskCount) / float64(totalLoans)) * 100
+	if len(unavailable) == len(loansSummarySubMetricFields) {
+		return nil, fmt.Errorf("failed to calculate summary metrics: %w", err)
 	}
 
-	criticalPercentage := 0.0
-	if totalLoans > 0 {
-		criticalPercentage = (float64(criticalCount) / float64(totalLoans)) * 100
-	}
+	// Calculate percentages
+	atRiskPercentage := safeDivide(float64(atRiskCount), float64(totalLoans)) * 100
+	criticalPercentage := safeDivide(float64(criticalCount), float64(totalLoans)) * 100
 
 	// Calculate percentage of due collected
 	percentageDueCollected := 0.0
-	if totalDueForToday > 0 {
-		percentageDueCollected = (totalRepaymentsToday / totalDueForToday) * 100
+	if totalDueForToday.IsPositive() {
+		percentageDueCollected = totalRepaymentsToday.Div(totalDueForToday.Decimal).Mul(decimal.NewFromInt(100)).InexactFloat64()
 	}
+	atRiskPercentage = r.roundPercent(atRiskPercentage)
+	criticalPercentage = r.roundPercent(criticalPercentage)
+	percentageDueCollected = r.roundPercent(percentageDueCollected)
 
 	// Build response
 	metrics := map[string]interface{}{
 		"total_loans":            totalLoans,
+		"distinct_customers":     distinctCustomers,
 		"total_portfolio_amount": totalPortfolioAmount,
 		"at_risk_loans": map[string]interface{}{
 			"count":              atRiskCount,
@@ -2259,14 +2552,50 @@ func (r *DashboardRepository) GetLoansSummaryMetrics(filters map[string]interfac
 		"missed_repayments_today":       missedAmountToday,
 		"missed_repayments_today_count": missedCountToday,
 		"past_maturity_outstanding":     pastMaturityOutstanding,
+		"partial":                       len(unavailable) > 0,
+		"unavailable_metrics":           unavailable,
+	}
+
+	// Null out the fields of failed sub-metrics rather than reporting zeros.
+	for _, name := range unavailable {
+		for _, field := range loansSummarySubMetricFields[name] {
+			metrics[field] = nil
+		}
 	}
 
 	return metrics, nil
 }
 
 // GetAllLoans retrieves all loans with pagination and filters
+//
+// With filters["cursor"] present, loans are keyset-paginated instead: they
+// are ordered by (disbursement_date, loan_id) descending with undated loans
+// last, only loans after the cursor are returned (an empty cursor starts from
+// the beginning) and page is ignored. Pages stay fast however deep the caller scrolls and don't skip or
+// repeat rows when loans are added between requests.
 func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*models.AllLoan, int, error) {
-	// NOTE: For the per-loan "repayments_today" field we now intentionally
+	pageFilters := filters
+	cursor, keyset := filters["cursor"].(string)
+	var cursorDate, cursorLoanID string
+	if keyset {
+		if sortBy, ok := filters["sort_by"].(string); ok && sortBy != "" {
+			return nil, 0, fmt.Errorf("%w: sort_by is not supported with cursor pagination", ErrInvalidFilter)
+		}
+		if cursor != "" {
+			var err error
+			if cursorDate, cursorLoanID, err = decodeLoanCursor(cursor); err != nil {
+				return nil, 0, err
+			}
+		}
+		// Keyset pages have no offset, so page (and the offset cap) don't apply
+		pageFilters = map[string]interface{}{"limit": filters["limit"]}
+	}
+	limit, offset, err := r.pageBounds(pageFilters, 50)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// NOTE: For the per-loan "repayments_today" field we now intentionally
 	// ignore the selected period and always aggregate ONLY today's repayments
 	// (DATE(r.payment_date) = CURRENT_DATE). This keeps the "Collection Today"
 	// column in the Officer/Branch loan tables strictly "today-only" even when
@@ -2282,7 +2611,7 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 				r.loan_id,
 				COALESCE(SUM(r.payment_amount), 0) AS repayments_in_period
 			FROM repayments r
-			WHERE r.is_reversed = false
+			WHERE `+reversalFilterSQL(false)+`
 				AND %s
 			GROUP BY r.loan_id
 		) rp ON rp.loan_id = l.loan_id
@@ -2303,7 +2632,7 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 			l.channel,
 			l.loan_amount,
 			l.repayment_amount,
-			TO_CHAR(l.disbursement_date, 'YYYY-MM-DD') as disbursement_date,
+			COALESCE(TO_CHAR(l.disbursement_date, 'YYYY-MM-DD'), '') as disbursement_date,
 			TO_CHAR(l.first_payment_due_date, 'YYYY-MM-DD') as first_payment_due_date,
 			TO_CHAR(l.maturity_date, 'YYYY-MM-DD') as maturity_date,
 			l.loan_term_days,
@@ -2331,12 +2660,16 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 			l.business_days_since_disbursement,
 			l.loan_type,
 			l.verification_status,
-			COALESCE(rp.repayments_in_period, 0) AS repayments_today
+			COALESCE(rp.repayments_in_period, 0) AS repayments_today,
+			` + r.missedTodaySQL() + ` AS missed_today,
+			-- Correlated so it is only evaluated for the returned page (served by
+			-- idx_repayments_loan_date) rather than aggregating all repayments
+			(SELECT TO_CHAR(MAX(lr.payment_date), 'YYYY-MM-DD') FROM repayments lr WHERE lr.loan_id = l.loan_id AND ` + repaymentNotReversedSQL("lr") + `) AS last_payment_date
 		FROM loans l
 		JOIN officers o ON l.officer_id = o.officer_id
 	` + repaymentsJoin + `
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
 	countQuery := `
@@ -2344,23 +2677,240 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 		FROM loans l
 		JOIN officers o ON l.officer_id = o.officer_id
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
+	where, args, argCount := r.allLoansFilterSQL(filters)
+	query += where
+	countQuery += where
+
+	// Get total count
+	var total int
+	err = r.db.QueryRow(countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Apply sorting. The keyset cursor only applies to the page query; total
+	// still counts every matching loan.
+	if keyset {
+		if cursor != "" {
+			cond, condArgs := loanCursorCondition(cursorDate, cursorLoanID, argCount)
+			query += " AND " + cond
+			args = append(args, condArgs...)
+			argCount += len(condArgs)
+		}
+		query += " ORDER BY l.disbursement_date DESC NULLS LAST, l.loan_id DESC"
+	} else {
+		orderBy, err := orderByClause(SortEntityLoans, filters, "l.disbursement_date", "DESC")
+		if err != nil {
+			return nil, 0, err
+		}
+		query += orderBy
+	}
+
+	// Apply pagination
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	// Execute query
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	loans := []*models.AllLoan{}
+	for rows.Next() {
+		loan := &models.AllLoan{}
+		var customerPhone, officerID, firstPaymentDueDate, maturityDate sql.NullString
+		var verticalLeadName, verticalLeadEmail, performanceStatus sql.NullString
+		var loanType, verificationStatus, djangoStatus sql.NullString
+		var repaymentAmount, timelinessScore, repaymentHealth, repaymentDelayRate sql.NullFloat64
+		var dailyRepaymentAmount, repaymentDaysPaid sql.NullFloat64
+		var repaymentsToday sql.NullFloat64
+		var lastPaymentDate sql.NullString
+		var daysSinceLastRepayment, repaymentDaysDueToday, businessDaysSinceDisbursement sql.NullInt64
+		var previousDPD, dpdChange sql.NullInt64
+
+		err := rows.Scan(
+			&loan.LoanID,
+			&loan.CustomerName,
+			&customerPhone,
+			&officerID,
+			&loan.OfficerName,
+			&loan.Region,
+			&loan.Branch,
+			&verticalLeadName,
+			&verticalLeadEmail,
+			&loan.Channel,
+			&loan.LoanAmount,
+			&repaymentAmount,
+			&loan.DisbursementDate,
+			&firstPaymentDueDate,
+			&maturityDate,
+			&loan.LoanTermDays,
+			&loan.CurrentDPD,
+			&previousDPD,
+			&dpdChange,
+			&loan.PrincipalOutstanding,
+			&loan.InterestOutstanding,
+			&loan.FeesOutstanding,
+			&loan.TotalOutstanding,
+			&loan.ActualOutstanding,
+			&loan.TotalRepayments,
+			&loan.Status,
+			&djangoStatus,
+			&performanceStatus,
+			&loan.FIMRTagged,
+			&timelinessScore,
+			&repaymentHealth,
+			&daysSinceLastRepayment,
+			&repaymentDelayRate,
+			&loan.Wave,
+			&dailyRepaymentAmount,
+			&repaymentDaysDueToday,
+			&repaymentDaysPaid,
+			&businessDaysSinceDisbursement,
+			&loanType,
+			&verificationStatus,
+			&repaymentsToday,
+			&loan.MissedToday,
+			&lastPaymentDate,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if customerPhone.Valid {
+			loan.CustomerPhone = customerPhone.String
+		}
+		if officerID.Valid {
+			loan.OfficerID = officerID.String
+		}
+		if verticalLeadName.Valid {
+			loan.VerticalLeadName = &verticalLeadName.String
+		}
+		if verticalLeadEmail.Valid {
+			loan.VerticalLeadEmail = &verticalLeadEmail.String
+		}
+		if performanceStatus.Valid {
+			loan.PerformanceStatus = &performanceStatus.String
+		}
+		if djangoStatus.Valid {
+			loan.DjangoStatus = &djangoStatus.String
+		}
+		if previousDPD.Valid {
+			val := int(previousDPD.Int64)
+			loan.PreviousDPD = &val
+		}
+		if dpdChange.Valid {
+			val := int(dpdChange.Int64)
+			loan.DPDChange = &val
+		}
+		if loanType.Valid {
+			loan.LoanType = &loanType.String
+		}
+		if verificationStatus.Valid {
+			loan.VerificationStatus = &verificationStatus.String
+		}
+		if repaymentsToday.Valid {
+			val := repaymentsToday.Float64
+			loan.RepaymentsToday = &val
+		}
+		if repaymentAmount.Valid {
+			val := repaymentAmount.Float64
+			loan.RepaymentAmount = &val
+		}
+		if firstPaymentDueDate.Valid {
+			loan.FirstPaymentDueDate = &firstPaymentDueDate.String
+		}
+		if maturityDate.Valid {
+			loan.MaturityDate = maturityDate.String
+		}
+		if timelinessScore.Valid {
+			val := timelinessScore.Float64
+			loan.TimelinessScore = &val
+		}
+		if repaymentHealth.Valid {
+			val := repaymentHealth.Float64
+			loan.RepaymentHealth = &val
+		}
+		if daysSinceLastRepayment.Valid {
+			val := int(daysSinceLastRepayment.Int64)
+			loan.DaysSinceLastRepayment = &val
+		}
+		if lastPaymentDate.Valid {
+			loan.LastPaymentDate = &lastPaymentDate.String
+		}
+		if repaymentDelayRate.Valid {
+			val := repaymentDelayRate.Float64
+			loan.RepaymentDelayRate = &val
+		}
+		if dailyRepaymentAmount.Valid {
+			val := dailyRepaymentAmount.Float64
+			loan.DailyRepaymentAmount = &val
+		}
+		if repaymentDaysDueToday.Valid {
+			val := int(repaymentDaysDueToday.Int64)
+			loan.RepaymentDaysDueToday = &val
+		}
+		if repaymentDaysPaid.Valid {
+			val := repaymentDaysPaid.Float64
+			loan.RepaymentDaysPaid = &val
+		}
+		if businessDaysSinceDisbursement.Valid {
+			val := int(businessDaysSinceDisbursement.Int64)
+			loan.BusinessDaysSinceDisbursement = &val
+		}
+
+		// Today's collection vs the expected daily installment. Loans with no
+		// expected amount get no rate; MissedToday comes from missedTodaySQL.
+		collected := 0.0
+		if loan.RepaymentsToday != nil {
+			collected = *loan.RepaymentsToday
+		}
+		if loan.DailyRepaymentAmount != nil && *loan.DailyRepaymentAmount > 0 {
+			rate := collected / *loan.DailyRepaymentAmount
+			loan.CollectionRateToday = &rate
+		}
+
+		loans = append(loans, loan)
+	}
+
+	return loans, total, nil
+}
+
+// allLoansFilterSQL builds the " AND ..." conditions GetAllLoans and
+// CountLoans apply for the All Loans filters, with placeholders numbered from
+// $1. It returns the next free placeholder number.
+func (r *DashboardRepository) allLoansFilterSQL(filters map[string]interface{}) (string, []interface{}, int) {
+	where := ""
 	args := []interface{}{}
 	argCount := 1
 
-	// Apply filters
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
-		args = append(args, officerID)
-		argCount++
+		// Support comma-separated officer IDs for multi-select
+		officerIDs := strings.Split(officerID, ",")
+		if len(officerIDs) == 1 {
+			where += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
+			args = append(args, officerIDs[0])
+			argCount++
+		} else {
+			// Build IN clause for multiple officers
+			placeholders := []string{}
+			for _, id := range officerIDs {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+				args = append(args, strings.TrimSpace(id))
+				argCount++
+			}
+			inClause := fmt.Sprintf(" AND l.officer_id IN (%s)", strings.Join(placeholders, ", "))
+			where += inClause
+		}
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
-		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.branch = $%d", argCount)
+		where += fmt.Sprintf(" AND l.branch = $%d", argCount)
 		args = append(args, branch)
 		argCount++
 	}
@@ -2369,8 +2919,7 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 		// Support comma-separated regions for multi-select
 		regions := strings.Split(region, ",")
 		if len(regions) == 1 {
-			query += fmt.Sprintf(" AND l.region = $%d", argCount)
-			countQuery += fmt.Sprintf(" AND l.region = $%d", argCount)
+			where += fmt.Sprintf(" AND l.region = $%d", argCount)
 			args = append(args, regions[0])
 			argCount++
 		} else {
@@ -2382,14 +2931,12 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 				argCount++
 			}
 			inClause := fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
-			query += inClause
-			countQuery += inClause
+			where += inClause
 		}
 	}
 
 	if channel, ok := filters["channel"].(string); ok && channel != "" {
-		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.channel = $%d", argCount)
+		where += fmt.Sprintf(" AND l.channel = $%d", argCount)
 		args = append(args, channel)
 		argCount++
 	}
@@ -2398,8 +2945,7 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 		// Support comma-separated statuses for multi-select
 		statuses := strings.Split(status, ",")
 		if len(statuses) == 1 {
-			query += fmt.Sprintf(" AND l.status = $%d", argCount)
-			countQuery += fmt.Sprintf(" AND l.status = $%d", argCount)
+			where += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", argCount)
 			args = append(args, statuses[0])
 			argCount++
 		} else {
@@ -2407,113 +2953,37 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 			placeholders := []string{}
 			for _, s := range statuses {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
-				args = append(args, strings.TrimSpace(s))
+				args = append(args, strings.ToUpper(strings.TrimSpace(s)))
 				argCount++
 			}
-			inClause := fmt.Sprintf(" AND l.status IN (%s)", strings.Join(placeholders, ", "))
-			query += inClause
-			countQuery += inClause
+			inClause := fmt.Sprintf(" AND UPPER(l.status) IN (%s)", strings.Join(placeholders, ", "))
+			where += inClause
 		}
 	}
 
 	// Raw Django status filter - supports comma-separated values and optional missing sentinel
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.django_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, s)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			clause := " AND (" + strings.Join(conditions, " OR ") + ")"
-			query += clause
-			countQuery += clause
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &argCount); cond != "" {
+			where += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
-		// Support comma-separated performance statuses for multi-select, including a sentinel for missing values
-		performanceStatuses := strings.Split(performanceStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, ps := range performanceStatuses {
-			value := strings.TrimSpace(ps)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.performance_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, ps := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, ps)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.performance_status IN (%s)", strings.Join(placeholders, ", ")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.performance_status IS NULL OR l.performance_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			clause := " AND (" + strings.Join(conditions, " OR ") + ")"
-			query += clause
-			countQuery += clause
+		if cond, condArgs := inListFilter("l.performance_status", performanceStatus, &argCount); cond != "" {
+			where += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		where += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
 
 	if customerPhone, ok := filters["customer_phone"].(string); ok && customerPhone != "" {
-		query += fmt.Sprintf(" AND l.customer_phone LIKE $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.customer_phone LIKE $%d", argCount)
+		where += fmt.Sprintf(" AND l.customer_phone LIKE $%d", argCount)
 		args = append(args, "%"+customerPhone+"%")
 		argCount++
 	}
@@ -2522,8 +2992,7 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 	if verticalLeadEmail, ok := filters["vertical_lead_email"].(string); ok && verticalLeadEmail != "" {
 		emails := strings.Split(verticalLeadEmail, ",")
 		if len(emails) == 1 {
-			query += fmt.Sprintf(" AND l.vertical_lead_email = $%d", argCount)
-			countQuery += fmt.Sprintf(" AND l.vertical_lead_email = $%d", argCount)
+			where += fmt.Sprintf(" AND l.vertical_lead_email = $%d", argCount)
 			args = append(args, strings.TrimSpace(emails[0]))
 			argCount++
 		} else {
@@ -2534,113 +3003,51 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 				argCount++
 			}
 			inClause := fmt.Sprintf(" AND l.vertical_lead_email IN (%s)", strings.Join(placeholders, ", "))
-			query += inClause
-			countQuery += inClause
+			where += inClause
+		}
+	}
+
+	// Supervisor filter - comma-separated supervisor emails of the loan's officer
+	if supervisorEmail, ok := filters["supervisor_email"].(string); ok && supervisorEmail != "" {
+		if cond, condArgs := supervisorFilter(supervisorEmail, &argCount); cond != "" {
+			where += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
+	// Vertical lead name filter - comma-separated, with a bucket for unassigned loans
+	if verticalLeadName, ok := filters["vertical_lead_name"].(string); ok && verticalLeadName != "" {
+		clause, nameArgs, next := verticalLeadNameFilter(verticalLeadName, argCount)
+		where += clause
+		args = append(args, nameArgs...)
+		argCount = next
+	}
+
 	// Loan type filter - support comma-separated values for multiple loan types, including a sentinel for missing values
 	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
-		loanTypes := strings.Split(loanType, ",")
-		fmt.Printf("DEBUG GetAllLoans: loan_type filter - raw input: '%s', split into %d values: %v\n", loanType, len(loanTypes), loanTypes)
-
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, lt := range loanTypes {
-			value := strings.TrimSpace(lt)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.loan_type = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, lt := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, lt)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.loan_type IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.loan_type IS NULL OR l.loan_type = '')")
-		}
-
-		if len(conditions) > 0 {
-			clause := " AND (" + strings.Join(conditions, " OR ") + ")"
-			fmt.Printf("DEBUG GetAllLoans: loan_type WHERE clause: %s, total args: %d\n", clause, len(args))
-			query += clause
-			countQuery += clause
-		}
-	}
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &argCount); cond != "" {
+			where += " AND " + cond
+			args = append(args, condArgs...)
+		}
+	}
 
 	// Verification status filter - support comma-separated values for multiple verification statuses, including a sentinel for missing values
 	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
-		verificationStatuses := strings.Split(verificationStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, vs := range verificationStatuses {
-			value := strings.TrimSpace(vs)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.verification_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, vs := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, vs)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.verification_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.verification_status IS NULL OR l.verification_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			clause := " AND (" + strings.Join(conditions, " OR ") + ")"
-			query += clause
-			countQuery += clause
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &argCount); cond != "" {
+			where += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	// DPD range filter
 	if dpdMin, ok := filters["dpd_min"].(int); ok {
-		query += fmt.Sprintf(" AND l.current_dpd >= $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.current_dpd >= $%d", argCount)
+		where += fmt.Sprintf(" AND l.current_dpd >= $%d", argCount)
 		args = append(args, dpdMin)
 		argCount++
 	}
 
 	if dpdMax, ok := filters["dpd_max"].(int); ok {
-		query += fmt.Sprintf(" AND l.current_dpd <= $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.current_dpd <= $%d", argCount)
+		where += fmt.Sprintf(" AND l.current_dpd <= $%d", argCount)
 		args = append(args, dpdMax)
 		argCount++
 	}
@@ -2650,8 +3057,12 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 	// GetLoansSummaryMetrics so that table rows, summary cards, and exports all
 	// reflect the same filtered population.
 	if quietLoans, ok := filters["quiet_loans"].(bool); ok && quietLoans {
-		query += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
-		countQuery += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
+		where += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
+	}
+
+	// Manual review worklist: only loans flagged via POST /loans/review-flags.
+	if reviewFlagged, ok := filters["review_flagged"].(bool); ok && reviewFlagged {
+		where += " AND " + reviewFlaggedLoanSQL
 	}
 
 	// Behavior-based filters that were previously applied only on the frontend
@@ -2660,16 +3071,13 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 		switch behaviorLoanType {
 		case "active":
 			// Active: significant outstanding and recent repayment
-			query += " AND l.total_outstanding > 2000 AND COALESCE(l.days_since_last_repayment, 0) < 6"
-			countQuery += " AND l.total_outstanding > 2000 AND COALESCE(l.days_since_last_repayment, 0) < 6"
+			where += " AND " + r.activeLoanSQL()
 		case "inactive":
 			// Inactive: low outstanding or no recent repayment
-			query += " AND (l.total_outstanding <= 2000 OR COALESCE(l.days_since_last_repayment, 0) > 5)"
-			countQuery += " AND (l.total_outstanding <= 2000 OR COALESCE(l.days_since_last_repayment, 0) > 5)"
+			where += " AND " + r.inactiveLoanSQL()
 		case "overdue_15d":
 			// Overdue: DPD strictly greater than 15 days
-			query += " AND l.current_dpd > 15"
-			countQuery += " AND l.current_dpd > 15"
+			where += " AND l.current_dpd > 15"
 		}
 	}
 
@@ -2677,226 +3085,52 @@ func (r *DashboardRepository) GetAllLoans(filters map[string]interface{}) ([]*mo
 		switch rotType {
 		case "early":
 			// Early ROT: young loan with emerging DPD
-			query += " AND (CURRENT_DATE - l.disbursement_date::date) < 7 AND l.current_dpd > 4"
-			countQuery += " AND (CURRENT_DATE - l.disbursement_date::date) < 7 AND l.current_dpd > 4"
+			where += " AND (CURRENT_DATE - l.disbursement_date::date) < 7 AND l.current_dpd > 4"
 		case "late":
 			// Late ROT: older loan with DPD
-			query += " AND (CURRENT_DATE - l.disbursement_date::date) >= 7 AND l.current_dpd > 4"
-			countQuery += " AND (CURRENT_DATE - l.disbursement_date::date) >= 7 AND l.current_dpd > 4"
+			where += " AND (CURRENT_DATE - l.disbursement_date::date) >= 7 AND l.current_dpd > 4"
+		case "unknown_age":
+			// Missing disbursement_date: age unknown, so neither early nor late
+			where += " AND l.disbursement_date IS NULL AND l.current_dpd > 4"
 		}
 	}
 
 	if delayType, ok := filters["delay_type"].(string); ok && delayType != "" {
 		// Risky loans based on repayment delay rate
 		if delayType == "risky" {
-			query += " AND l.status = 'Active' AND l.total_outstanding > 2000 AND l.repayment_delay_rate IS NOT NULL AND l.repayment_delay_rate < 60"
-			countQuery += " AND l.status = 'Active' AND l.total_outstanding > 2000 AND l.repayment_delay_rate IS NOT NULL AND l.repayment_delay_rate < 60"
+			riskyCondition := fmt.Sprintf(" AND UPPER(l.status) = 'ACTIVE' AND l.total_outstanding > 2000 AND l.repayment_delay_rate IS NOT NULL AND l.repayment_delay_rate < $%d", argCount)
+			where += riskyCondition
+			args = append(args, r.cfg.DelayRateRiskyThreshold)
+			argCount++
 		}
 	}
 
-	// Get total count
-	var total int
-	err := r.db.QueryRow(countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Apply sorting
-	sortBy := "l.disbursement_date"
-	if sort, ok := filters["sort_by"].(string); ok && sort != "" {
-		sortBy = "l." + sort
-	}
-	sortDir := "DESC"
-	if dir, ok := filters["sort_dir"].(string); ok && dir != "" {
-		sortDir = dir
-	}
-	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortDir)
-
-	// Apply pagination
-	page := 1
-	limit := 50
-	if p, ok := filters["page"].(int); ok && p > 0 {
-		page = p
-	}
-	if l, ok := filters["limit"].(int); ok && l > 0 {
-		limit = l
-	}
-	offset := (page - 1) * limit
-
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
-	args = append(args, limit, offset)
-
-	// Execute query
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	loans := []*models.AllLoan{}
-	for rows.Next() {
-		loan := &models.AllLoan{}
-		var customerPhone, officerID, firstPaymentDueDate, maturityDate sql.NullString
-		var verticalLeadName, verticalLeadEmail, performanceStatus sql.NullString
-		var loanType, verificationStatus, djangoStatus sql.NullString
-		var repaymentAmount, timelinessScore, repaymentHealth, repaymentDelayRate sql.NullFloat64
-		var dailyRepaymentAmount, repaymentDaysPaid sql.NullFloat64
-		var repaymentsToday sql.NullFloat64
-		var daysSinceLastRepayment, repaymentDaysDueToday, businessDaysSinceDisbursement sql.NullInt64
-		var previousDPD, dpdChange sql.NullInt64
-
-		err := rows.Scan(
-			&loan.LoanID,
-			&loan.CustomerName,
-			&customerPhone,
-			&officerID,
-			&loan.OfficerName,
-			&loan.Region,
-			&loan.Branch,
-			&verticalLeadName,
-			&verticalLeadEmail,
-			&loan.Channel,
-			&loan.LoanAmount,
-			&repaymentAmount,
-			&loan.DisbursementDate,
-			&firstPaymentDueDate,
-			&maturityDate,
-			&loan.LoanTermDays,
-			&loan.CurrentDPD,
-			&previousDPD,
-			&dpdChange,
-			&loan.PrincipalOutstanding,
-			&loan.InterestOutstanding,
-			&loan.FeesOutstanding,
-			&loan.TotalOutstanding,
-			&loan.ActualOutstanding,
-			&loan.TotalRepayments,
-			&loan.Status,
-			&djangoStatus,
-			&performanceStatus,
-			&loan.FIMRTagged,
-			&timelinessScore,
-			&repaymentHealth,
-			&daysSinceLastRepayment,
-			&repaymentDelayRate,
-			&loan.Wave,
-			&dailyRepaymentAmount,
-			&repaymentDaysDueToday,
-			&repaymentDaysPaid,
-			&businessDaysSinceDisbursement,
-			&loanType,
-			&verificationStatus,
-			&repaymentsToday,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
+	return where, args, argCount
+}
 
-		if customerPhone.Valid {
-			loan.CustomerPhone = customerPhone.String
-		}
-		if officerID.Valid {
-			loan.OfficerID = officerID.String
-		}
-		if verticalLeadName.Valid {
-			loan.VerticalLeadName = &verticalLeadName.String
-		}
-		if verticalLeadEmail.Valid {
-			loan.VerticalLeadEmail = &verticalLeadEmail.String
-		}
-		if performanceStatus.Valid {
-			loan.PerformanceStatus = &performanceStatus.String
-		}
-		if djangoStatus.Valid {
-			loan.DjangoStatus = &djangoStatus.String
-		}
-		if previousDPD.Valid {
-			val := int(previousDPD.Int64)
-			loan.PreviousDPD = &val
-		}
-		if dpdChange.Valid {
-			val := int(dpdChange.Int64)
-			loan.DPDChange = &val
-		}
-		if loanType.Valid {
-			loan.LoanType = &loanType.String
-		}
-		if verificationStatus.Valid {
-			loan.VerificationStatus = &verificationStatus.String
-		}
-		if repaymentsToday.Valid {
-			val := repaymentsToday.Float64
-			loan.RepaymentsToday = &val
-		}
-		if repaymentAmount.Valid {
-			val := repaymentAmount.Float64
-			loan.RepaymentAmount = &val
-		}
-		if firstPaymentDueDate.Valid {
-			loan.FirstPaymentDueDate = &firstPaymentDueDate.String
-		}
-		if maturityDate.Valid {
-			loan.MaturityDate = maturityDate.String
-		}
-		if timelinessScore.Valid {
-			val := timelinessScore.Float64
-			loan.TimelinessScore = &val
-		}
-		if repaymentHealth.Valid {
-			val := repaymentHealth.Float64
-			loan.RepaymentHealth = &val
-		}
-		if daysSinceLastRepayment.Valid {
-			val := int(daysSinceLastRepayment.Int64)
-			loan.DaysSinceLastRepayment = &val
-		}
-		if repaymentDelayRate.Valid {
-			val := repaymentDelayRate.Float64
-			loan.RepaymentDelayRate = &val
-		}
-		if dailyRepaymentAmount.Valid {
-			val := dailyRepaymentAmount.Float64
-			loan.DailyRepaymentAmount = &val
-		}
-		if repaymentDaysDueToday.Valid {
-			val := int(repaymentDaysDueToday.Int64)
-			loan.RepaymentDaysDueToday = &val
-		}
-		if repaymentDaysPaid.Valid {
-			val := repaymentDaysPaid.Float64
-			loan.RepaymentDaysPaid = &val
-		}
-		if businessDaysSinceDisbursement.Valid {
-			val := int(businessDaysSinceDisbursement.Int64)
-			loan.BusinessDaysSinceDisbursement = &val
-		}
+// CountLoans returns how many loans match the All Loans filters, running only
+// the count query used by GetAllLoans.
+func (r *DashboardRepository) CountLoans(filters map[string]interface{}) (int, error) {
+	where, args, _ := r.allLoansFilterSQL(filters)
+	countQuery := `
+		SELECT COUNT(*)
+		FROM loans l
+		JOIN officers o ON l.officer_id = o.officer_id
+		WHERE 1=1
+			AND ` + r.userTypeFilter() + `
+	` + where
 
-		loans = append(loans, loan)
+	var total int
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return 0, err
 	}
-
-	return loans, total, nil
+	return total, nil
 }
 
-// GetTopRiskLoans retrieves the top N highest-risk loans for a specific officer
-func (r *DashboardRepository) GetTopRiskLoans(officerID string, limit int) ([]*models.TopRiskLoan, error) {
-	query := `
-		SELECT
-			l.loan_id,
-			l.customer_name,
-			COALESCE(l.customer_phone, '') as customer_phone,
-			l.loan_amount::float as loan_amount,
-			TO_CHAR(l.disbursement_date, 'YYYY-MM-DD') as disbursement_date,
-			l.current_dpd,
-			l.max_dpd_ever,
-			l.total_outstanding::float as total_outstanding,
-			l.principal_outstanding::float as principal_outstanding,
-			l.interest_outstanding::float as interest_outstanding,
-			l.fees_outstanding::float as fees_outstanding,
-			l.status,
-			l.fimr_tagged,
-			l.channel,
-			(CURRENT_DATE - l.disbursement_date::date)::int as days_since_disbursement,
-			-- Calculate risk score based on multiple factors
+// topRiskScoreSQL is the SQL expression used to score loans for the top-risk
+// views. It expects the loans table to be aliased as "l" and yields a score
+// between 0 and 100.
+const topRiskScoreSQL = `
 			(
 				-- DPD weight (40%): Higher DPD = higher risk
 				(CASE
@@ -2933,16 +3167,38 @@ func (r *DashboardRepository) GetTopRiskLoans(officerID string, limit int) ([]*m
 					WHEN (CURRENT_DATE - l.disbursement_date::date) <= 60 AND l.current_dpd > 0 THEN 3
 					ELSE 0
 				END)
-			) as risk_score
-		FROM loans l
-		WHERE l.officer_id = $1
-			AND l.status = 'Active'
-			AND (l.current_dpd > 0 OR l.fimr_tagged = true OR l.total_outstanding > 0)
-		ORDER BY risk_score DESC, l.current_dpd DESC, total_outstanding DESC
-		LIMIT $2
-	`
+			)`
 
-	rows, err := r.db.Query(query, officerID, limit)
+// GetTopRiskLoans retrieves the top N highest-risk loans for a specific officer
+func (r *DashboardRepository) GetTopRiskLoans(officerID string, limit int) ([]*models.TopRiskLoan, error) {
+	query := `
+		SELECT
+			l.loan_id,
+			l.customer_name,
+			COALESCE(l.customer_phone, '') as customer_phone,
+			l.loan_amount::float as loan_amount,
+			COALESCE(TO_CHAR(l.disbursement_date, 'YYYY-MM-DD'), '') as disbursement_date,
+			l.current_dpd,
+			l.max_dpd_ever,
+			l.total_outstanding::float as total_outstanding,
+			l.principal_outstanding::float as principal_outstanding,
+			l.interest_outstanding::float as interest_outstanding,
+			l.fees_outstanding::float as fees_outstanding,
+			l.status,
+			l.fimr_tagged,
+			l.channel,
+			(CURRENT_DATE - l.disbursement_date::date)::int as days_since_disbursement, -- NULL when disbursement_date is missing
+			` + topRiskScoreSQL + ` as risk_score
+		FROM loans l
+		WHERE l.officer_id = $1
+			AND UPPER(l.status) = 'ACTIVE'
+			AND (l.current_dpd > 0 OR l.fimr_tagged = true OR l.total_outstanding > 0)
+			` + r.excludedOfficerFilter() + `
+		ORDER BY risk_score DESC, l.current_dpd DESC, total_outstanding DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, officerID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -2974,22 +3230,344 @@ func (r *DashboardRepository) GetTopRiskLoans(officerID string, limit int) ([]*m
 		}
 
 		// Determine risk category based on risk score
-		if loan.RiskScore >= 80 {
-			loan.RiskCategory = "Critical"
-		} else if loan.RiskScore >= 60 {
-			loan.RiskCategory = "High"
-		} else if loan.RiskScore >= 40 {
-			loan.RiskCategory = "Medium"
-		} else {
-			loan.RiskCategory = "Low"
+		loan.RiskCategory = topRiskCategory(loan.RiskScore)
+
+		loans = append(loans, &loan)
+	}
+
+	return loans, nil
+}
+
+// GetPortfolioTopRiskLoans retrieves the top N highest-risk loans across the
+// whole (filtered) portfolio. Only loans that are already delinquent or FIMR
+// tagged are scored, which keeps the candidate set small and lets the planner
+// use the current_dpd / fimr_tagged indexes.
+func (r *DashboardRepository) GetPortfolioTopRiskLoans(filters map[string]interface{}, limit int) ([]*models.TopRiskLoan, error) {
+	query := `
+		SELECT
+			l.loan_id,
+			l.officer_id,
+			COALESCE(o.officer_name, l.officer_name, '') as officer_name,
+			COALESCE(l.branch, '') as branch,
+			COALESCE(l.region, '') as region,
+			l.customer_name,
+			COALESCE(l.customer_phone, '') as customer_phone,
+			l.loan_amount::float as loan_amount,
+			COALESCE(TO_CHAR(l.disbursement_date, 'YYYY-MM-DD'), '') as disbursement_date,
+			l.current_dpd,
+			l.max_dpd_ever,
+			l.total_outstanding::float as total_outstanding,
+			l.principal_outstanding::float as principal_outstanding,
+			l.interest_outstanding::float as interest_outstanding,
+			l.fees_outstanding::float as fees_outstanding,
+			l.status,
+			l.fimr_tagged,
+			l.channel,
+			(CURRENT_DATE - l.disbursement_date::date)::int as days_since_disbursement, -- NULL when disbursement_date is missing
+			` + topRiskScoreSQL + ` as risk_score
+		FROM loans l
+		LEFT JOIN officers o ON l.officer_id = o.officer_id
+		WHERE UPPER(l.status) = 'ACTIVE'
+			AND (l.current_dpd > 0 OR l.fimr_tagged = true)
+			AND ` + r.userTypeFilter() + `
+	`
+
+	args := []interface{}{}
+	argCount := 1
+
+	// Apply filters
+	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
+		query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
+		args = append(args, officerID)
+		argCount++
+	}
+
+	if branch, ok := filters["branch"].(string); ok && branch != "" {
+		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
+		args = append(args, branch)
+		argCount++
+	}
+
+	if region, ok := filters["region"].(string); ok && region != "" {
+		// Support comma-separated regions for multi-select
+		regions := strings.Split(region, ",")
+		placeholders := []string{}
+		for _, r := range regions {
+			placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+			args = append(args, strings.TrimSpace(r))
+			argCount++
+		}
+		query += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	if channel, ok := filters["channel"].(string); ok && channel != "" {
+		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
+		args = append(args, channel)
+		argCount++
+	}
+
+	if wave, ok := filters["wave"].(string); ok && wave != "" {
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
+		args = append(args, wave)
+		argCount++
+	}
+
+	query += fmt.Sprintf(" ORDER BY risk_score DESC, l.current_dpd DESC, total_outstanding DESC LIMIT $%d", argCount)
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []*models.TopRiskLoan
+	for rows.Next() {
+		var loan models.TopRiskLoan
+		err := rows.Scan(
+			&loan.LoanID,
+			&loan.OfficerID,
+			&loan.OfficerName,
+			&loan.Branch,
+			&loan.Region,
+			&loan.CustomerName,
+			&loan.CustomerPhone,
+			&loan.LoanAmount,
+			&loan.DisbursementDate,
+			&loan.CurrentDPD,
+			&loan.MaxDPDEver,
+			&loan.TotalOutstanding,
+			&loan.PrincipalOutstanding,
+			&loan.InterestOutstanding,
+			&loan.FeesOutstanding,
+			&loan.Status,
+			&loan.FIMRTagged,
+			&loan.Channel,
+			&loan.DaysSinceDisbursement,
+			&loan.RiskScore,
+		)
+		if err != nil {
+			return nil, err
 		}
 
+		loan.RiskCategory = topRiskCategory(loan.RiskScore)
+
 		loans = append(loans, &loan)
 	}
 
 	return loans, nil
 }
 
+// topRiskCategory maps a top-risk score onto its display category
+func topRiskCategory(score float64) string {
+	if score >= 80 {
+		return "Critical"
+	} else if score >= 60 {
+		return "High"
+	} else if score >= 40 {
+		return "Medium"
+	}
+	return "Low"
+}
+
+// GetMultiFlaggedLoans lists open loans that carry every risk signal selected
+// in signals, worst first (highest DPD, then largest balance). Quiet uses the
+// same 6-day definition as the quiet_loans filter and past maturity the same
+// definition as past_maturity_outstanding. Returns the page and the total
+// number of matching loans.
+func (r *DashboardRepository) GetMultiFlaggedLoans(filters map[string]interface{}, signals models.MultiFlagSignals) ([]*models.MultiFlaggedLoan, int, error) {
+	if !signals.Any() {
+		return nil, 0, fmt.Errorf("%w: select at least one of fimr, dpd_over, quiet or past_maturity", ErrInvalidFilter)
+	}
+	limit, offset, err := r.pageBounds(filters, 50)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	loanFilters, args, argCount := buildLoanFilters(filters, 1)
+
+	signalSQL := ""
+	if signals.FIMR {
+		signalSQL += " AND l.fimr_tagged = true"
+	}
+	if signals.DPDOver != nil {
+		signalSQL += fmt.Sprintf(" AND l.current_dpd > $%d", argCount)
+		args = append(args, *signals.DPDOver)
+		argCount++
+	}
+	if signals.Quiet {
+		signalSQL += " AND (l.days_since_last_repayment >= 6 OR l.days_since_last_repayment IS NULL)"
+	}
+	if signals.PastMaturity {
+		signalSQL += " AND l.maturity_date < CURRENT_DATE AND l.actual_outstanding > 0"
+	}
+
+	fromWhere := fmt.Sprintf(`
+		FROM loans l
+		INNER JOIN officers o ON l.officer_id = o.officer_id
+		WHERE %s
+			AND %s
+			%s
+			%s`, r.openStatusFilter(), r.userTypeFilter(), signalSQL, loanFilters)
+
+	// Counted separately so the total is still reported for a page past the
+	// last loan.
+	total := 0
+	if err := r.db.QueryRow("SELECT COUNT(*)"+fromWhere, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count multi-flagged loans: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			l.loan_id,
+			COALESCE(l.customer_name, '') AS customer_name,
+			COALESCE(l.customer_phone, '') AS customer_phone,
+			l.officer_id,
+			COALESCE(o.officer_name, l.officer_name, '') AS officer_name,
+			COALESCE(l.branch, '') AS branch,
+			COALESCE(l.region, '') AS region,
+			COALESCE(l.django_status, '') AS django_status,
+			COALESCE(l.fimr_tagged, false) AS fimr_tagged,
+			COALESCE(l.current_dpd, 0) AS current_dpd,
+			l.days_since_last_repayment,
+			TO_CHAR(l.maturity_date, 'YYYY-MM-DD') AS maturity_date,
+			GREATEST(COALESCE(CURRENT_DATE - l.maturity_date::date, 0), 0) AS days_past_maturity,
+			COALESCE(l.actual_outstanding, 0)::float AS actual_outstanding
+		%s
+		ORDER BY l.current_dpd DESC, l.actual_outstanding DESC, l.loan_id
+		LIMIT $%d OFFSET $%d
+	`, fromWhere, argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve multi-flagged loans: %w", err)
+	}
+	defer rows.Close()
+
+	loans := []*models.MultiFlaggedLoan{}
+	for rows.Next() {
+		loan := &models.MultiFlaggedLoan{}
+		if err := rows.Scan(
+			&loan.LoanID,
+			&loan.CustomerName,
+			&loan.CustomerPhone,
+			&loan.OfficerID,
+			&loan.OfficerName,
+			&loan.Branch,
+			&loan.Region,
+			&loan.DjangoStatus,
+			&loan.FIMRTagged,
+			&loan.CurrentDPD,
+			&loan.DaysSinceLastRepayment,
+			&loan.MaturityDate,
+			&loan.DaysPastMaturity,
+			&loan.ActualOutstanding,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan multi-flagged loan: %w", err)
+		}
+		loans = append(loans, loan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return loans, total, nil
+}
+
+// GetLoanStatusCounts returns loan counts and outstanding balances grouped by
+// the normalised status and, in parallel, by the raw Django status. Both
+// breakdowns come from a single GROUPING SETS query over the filtered loans.
+// Missing status values are reported as MissingValueSentinel so they can be
+// fed straight back into the status / django_status filters.
+func (r *DashboardRepository) GetLoanStatusCounts(filters map[string]interface{}) ([]*models.LoanStatusCount, []*models.LoanStatusCount, error) {
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+
+	query := `
+		SELECT
+			GROUPING(l.status) AS is_django_status,
+			COALESCE(NULLIF(CASE WHEN GROUPING(l.status) = 0 THEN l.status ELSE l.django_status END, ''), '` + MissingValueSentinel + `') AS status,
+			COUNT(*) AS loan_count,
+			COALESCE(SUM(l.total_outstanding), 0) AS outstanding
+		FROM loans l
+		JOIN officers o ON l.officer_id = o.officer_id
+		WHERE ` + r.userTypeFilter() + `
+	` + loanFilters + `
+		GROUP BY GROUPING SETS ((l.status), (l.django_status))
+		ORDER BY is_django_status, loan_count DESC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	byStatus := []*models.LoanStatusCount{}
+	byDjangoStatus := []*models.LoanStatusCount{}
+	for rows.Next() {
+		var isDjangoStatus int
+		sc := &models.LoanStatusCount{}
+		if err := rows.Scan(&isDjangoStatus, &sc.Status, &sc.Count, &sc.Outstanding); err != nil {
+			return nil, nil, err
+		}
+		if isDjangoStatus == 1 {
+			byDjangoStatus = append(byDjangoStatus, sc)
+		} else {
+			byStatus = append(byStatus, sc)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return byStatus, byDjangoStatus, nil
+}
+
+// GetVerificationStatusBreakdown returns loan counts and outstanding balances
+// grouped by verification_status over the filtered loans, largest count
+// first. Loans without a verification status are reported as
+// MissingValueSentinel so the bucket can be fed straight back into the
+// verification_status filter.
+func (r *DashboardRepository) GetVerificationStatusBreakdown(filters map[string]interface{}) ([]*models.VerificationStatusCount, error) {
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+
+	query := `
+		SELECT
+			COALESCE(NULLIF(l.verification_status, ''), '` + MissingValueSentinel + `') AS verification_status,
+			COUNT(*) AS loan_count,
+			COALESCE(SUM(l.total_outstanding), 0) AS outstanding
+		FROM loans l
+		JOIN officers o ON l.officer_id = o.officer_id
+		WHERE ` + r.userTypeFilter() + `
+	` + loanFilters + `
+		GROUP BY 1
+		ORDER BY loan_count DESC, verification_status
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*models.VerificationStatusCount{}
+	for rows.Next() {
+		vc := &models.VerificationStatusCount{}
+		if err := rows.Scan(&vc.VerificationStatus, &vc.Count, &vc.Outstanding); err != nil {
+			return nil, err
+		}
+		counts = append(counts, vc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
 // GetBranches retrieves branch-level aggregated metrics
 func (r *DashboardRepository) GetBranches(filters map[string]interface{}) ([]*models.DashboardBranchMetrics, error) {
 	query := `
@@ -3008,7 +3586,7 @@ func (r *DashboardRepository) GetBranches(filters map[string]interface{}) ([]*mo
 			COALESCE(AVG(l.repayment_delay_rate), 0) as avg_repayment_delay_rate
 		FROM loans l
 		WHERE 1=1
-	`
+	` + r.excludedOfficerFilter()
 
 	args := []interface{}{}
 	argCount := 1
@@ -3052,7 +3630,7 @@ func (r *DashboardRepository) GetBranches(filters map[string]interface{}) ([]*mo
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
@@ -3060,15 +3638,11 @@ func (r *DashboardRepository) GetBranches(filters map[string]interface{}) ([]*mo
 	query += " GROUP BY l.branch, l.region"
 
 	// Apply sorting
-	sortBy := "l.branch"
-	if sort, ok := filters["sort_by"].(string); ok && sort != "" {
-		sortBy = sort
-	}
-	sortDir := "ASC"
-	if dir, ok := filters["sort_dir"].(string); ok && strings.ToUpper(dir) == "DESC" {
-		sortDir = "DESC"
+	orderBy, err := orderByClause(SortEntityBranches, filters, "l.branch", "ASC")
+	if err != nil {
+		return nil, err
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortDir)
+	query += orderBy
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -3104,35 +3678,41 @@ func (r *DashboardRepository) GetBranches(filters map[string]interface{}) ([]*mo
 	return branches, nil
 }
 
-// GetVerticalLeadMetrics retrieves aggregated loan metrics grouped by vertical
-// lead name for the Credit Health by Branch "By Vertical Lead" view.
-func (r *DashboardRepository) GetVerticalLeadMetrics(filters map[string]interface{}) ([]*models.VerticalLeadMetricsRow, error) {
+// GetBranchDPDMatrix returns, for each branch, the loan count and outstanding
+// balance in each DPD bucket (an aging matrix). Only loans with an outstanding
+// balance are included and the buckets are mutually exclusive, so each row's
+// buckets add up to its total. Loans with no current_dpd yet count as current.
+func (r *DashboardRepository) GetBranchDPDMatrix(filters map[string]interface{}) ([]*models.BranchDPDMatrixRow, error) {
 	query := `
 		SELECT
-				COALESCE(NULLIF(l.vertical_lead_name, ''), 'Unassigned Vertical Lead') AS vertical_lead_name,
-				COALESCE(NULLIF(l.vertical_lead_email, ''), 'N/A') AS vertical_lead_email,
-				COUNT(DISTINCT l.branch) AS branches,
-				COUNT(DISTINCT l.officer_id) AS active_los,
-				COUNT(*) AS loans,
-					COALESCE(SUM(l.total_outstanding), 0) AS outstanding,
-					COALESCE(SUM(CASE WHEN l.django_status IN ('OPEN', 'PAST_MATURITY') THEN l.daily_repayment_amount ELSE 0 END), 0) AS daily_target,
-				COALESCE(AVG(l.current_dpd), 0) AS avg_dpd,
-				COALESCE(MAX(l.max_dpd_ever), 0) AS max_dpd,
-				COUNT(CASE WHEN l.current_dpd = 0 THEN 1 END) AS dpd0,
-				COUNT(CASE WHEN l.current_dpd BETWEEN 1 AND 6 THEN 1 END) AS dpd1_6,
-				COUNT(CASE WHEN l.current_dpd BETWEEN 7 AND 14 THEN 1 END) AS dpd7_14,
-				COUNT(CASE WHEN l.current_dpd BETWEEN 14 AND 21 THEN 1 END) AS dpd14_21,
-				COUNT(CASE WHEN l.current_dpd > 21 THEN 1 END) AS dpd21_plus,
-				COUNT(CASE WHEN COALESCE(l.days_since_last_repayment, 0) > 7 THEN 1 END) AS quiet,
-				COALESCE(SUM(CASE WHEN COALESCE(l.days_since_last_repayment, 0) > 7 THEN l.total_outstanding ELSE 0 END), 0) AS quiet_value
+			COALESCE(l.branch, '') AS branch,
+			COALESCE(MODE() WITHIN GROUP (ORDER BY l.region), '') AS region,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) <= 0 THEN 1 END) AS current_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) <= 0 THEN l.total_outstanding END), 0) AS current_outstanding,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 1 AND 6 THEN 1 END) AS dpd1_6_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 1 AND 6 THEN l.total_outstanding END), 0) AS dpd1_6_outstanding,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 7 AND 14 THEN 1 END) AS dpd7_14_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 7 AND 14 THEN l.total_outstanding END), 0) AS dpd7_14_outstanding,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 15 AND 30 THEN 1 END) AS dpd15_30_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 15 AND 30 THEN l.total_outstanding END), 0) AS dpd15_30_outstanding,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 31 AND 60 THEN 1 END) AS dpd31_60_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 31 AND 60 THEN l.total_outstanding END), 0) AS dpd31_60_outstanding,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 61 AND 90 THEN 1 END) AS dpd61_90_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) BETWEEN 61 AND 90 THEN l.total_outstanding END), 0) AS dpd61_90_outstanding,
+			COUNT(CASE WHEN COALESCE(l.current_dpd, 0) > 90 THEN 1 END) AS dpd90_plus_count,
+			COALESCE(SUM(CASE WHEN COALESCE(l.current_dpd, 0) > 90 THEN l.total_outstanding END), 0) AS dpd90_plus_outstanding,
+			COUNT(*) AS total_count,
+			COALESCE(SUM(l.total_outstanding), 0) AS total_outstanding
 		FROM loans l
-		WHERE 1=1
+		JOIN officers o ON l.officer_id = o.officer_id
+		WHERE l.total_outstanding > 0
+			AND ` + r.userTypeFilter() + `
 	`
 
 	args := []interface{}{}
 	argCount := 1
 
-	// Optional filters (kept consistent with GetBranches where relevant)
+	// Apply filters
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
 		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
 		args = append(args, branch)
@@ -3147,7 +3727,6 @@ func (r *DashboardRepository) GetVerticalLeadMetrics(filters map[string]interfac
 			args = append(args, regions[0])
 			argCount++
 		} else {
-			// Build IN clause for multiple regions
 			placeholders := []string{}
 			for _, r := range regions {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
@@ -3158,7 +3737,123 @@ func (r *DashboardRepository) GetVerticalLeadMetrics(filters map[string]interfac
 		}
 	}
 
-	if channel, ok := filters["channel"].(string); ok && channel != "" {
+	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
+		query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
+		args = append(args, officerID)
+		argCount++
+	}
+
+	if channel, ok := filters["channel"].(string); ok && channel != "" {
+		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
+		args = append(args, channel)
+		argCount++
+	}
+
+	if userType, ok := filters["user_type"].(string); ok && userType != "" {
+		query += fmt.Sprintf(" AND o.user_type = $%d", argCount)
+		args = append(args, userType)
+		argCount++
+	}
+
+	if wave, ok := filters["wave"].(string); ok && wave != "" {
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
+		args = append(args, wave)
+		argCount++
+	}
+
+	query += `
+		GROUP BY COALESCE(l.branch, '')
+		ORDER BY COALESCE(l.branch, '')
+	`
+
+	rows, err := r.reportingConn().Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*models.BranchDPDMatrixRow{}
+	for rows.Next() {
+		row := &models.BranchDPDMatrixRow{}
+		if err := rows.Scan(
+			&row.Branch,
+			&row.Region,
+			&row.Current.Count, &row.Current.Outstanding,
+			&row.DPD1to6.Count, &row.DPD1to6.Outstanding,
+			&row.DPD7to14.Count, &row.DPD7to14.Outstanding,
+			&row.DPD15to30.Count, &row.DPD15to30.Outstanding,
+			&row.DPD31to60.Count, &row.DPD31to60.Outstanding,
+			&row.DPD61to90.Count, &row.DPD61to90.Outstanding,
+			&row.DPD90Plus.Count, &row.DPD90Plus.Outstanding,
+			&row.Total.Count, &row.Total.Outstanding,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetVerticalLeadMetrics retrieves aggregated loan metrics grouped by vertical
+// lead name for the Credit Health by Branch "By Vertical Lead" view.
+func (r *DashboardRepository) GetVerticalLeadMetrics(filters map[string]interface{}) ([]*models.VerticalLeadMetricsRow, error) {
+	query := `
+		SELECT
+				COALESCE(NULLIF(l.vertical_lead_name, ''), 'Unassigned Vertical Lead') AS vertical_lead_name,
+				COALESCE(NULLIF(l.vertical_lead_email, ''), 'N/A') AS vertical_lead_email,
+				COUNT(DISTINCT l.branch) AS branches,
+				COUNT(DISTINCT l.officer_id) AS active_los,
+				COUNT(*) AS loans,
+					COALESCE(SUM(l.total_outstanding), 0) AS outstanding,
+					COALESCE(SUM(CASE WHEN ` + r.openStatusFilter() + ` THEN ` + r.dailyRepaymentSQL() + ` ELSE 0 END), 0) AS daily_target,
+				COALESCE(AVG(l.current_dpd), 0) AS avg_dpd,
+				COALESCE(MAX(l.max_dpd_ever), 0) AS max_dpd,
+				COUNT(CASE WHEN l.current_dpd = 0 THEN 1 END) AS dpd0,
+				COUNT(CASE WHEN l.current_dpd BETWEEN 1 AND 6 THEN 1 END) AS dpd1_6,
+				COUNT(CASE WHEN l.current_dpd BETWEEN 7 AND 14 THEN 1 END) AS dpd7_14,
+				COUNT(CASE WHEN l.current_dpd BETWEEN 14 AND 21 THEN 1 END) AS dpd14_21,
+				COUNT(CASE WHEN l.current_dpd > 21 THEN 1 END) AS dpd21_plus,
+				COUNT(CASE WHEN COALESCE(l.days_since_last_repayment, 0) > 7 THEN 1 END) AS quiet,
+				COALESCE(SUM(CASE WHEN COALESCE(l.days_since_last_repayment, 0) > 7 THEN l.total_outstanding ELSE 0 END), 0) AS quiet_value
+		FROM loans l
+		WHERE 1=1
+	` + r.excludedOfficerFilter()
+
+	args := []interface{}{}
+	argCount := 1
+
+	// Optional filters (kept consistent with GetBranches where relevant)
+	if branch, ok := filters["branch"].(string); ok && branch != "" {
+		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
+		args = append(args, branch)
+		argCount++
+	}
+
+	if region, ok := filters["region"].(string); ok && region != "" {
+		// Support comma-separated regions for multi-select
+		regions := strings.Split(region, ",")
+		if len(regions) == 1 {
+			query += fmt.Sprintf(" AND l.region = $%d", argCount)
+			args = append(args, regions[0])
+			argCount++
+		} else {
+			// Build IN clause for multiple regions
+			placeholders := []string{}
+			for _, r := range regions {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+				args = append(args, strings.TrimSpace(r))
+				argCount++
+			}
+			query += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
+		}
+	}
+
+	if channel, ok := filters["channel"].(string); ok && channel != "" {
 		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
 		args = append(args, channel)
 		argCount++
@@ -3171,7 +3866,7 @@ func (r *DashboardRepository) GetVerticalLeadMetrics(filters map[string]interfac
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
@@ -3226,7 +3921,8 @@ func (r *DashboardRepository) GetVerticalLeadMetrics(filters map[string]interfac
 // GetBranchCollectionsLeaderboard returns per-branch collections metrics for the
 // Collections Control Centre "Branch Leaderboard" table. It focuses on
 // "today" collections behaviour (expected due today vs collected today) and
-// a simple NPL proxy based on PAR15 (overdue >= 15 days / portfolio).
+// a simple NPL proxy based on PAR15 (overdue >= 15 days / portfolio). Rows
+// are ordered by collected_today descending, then branch.
 func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string]interface{}) ([]*models.BranchCollectionsLeaderboardRow, error) {
 	// --- First query: loan-based metrics per branch (portfolio, due today, PAR15) ---
 	// NOTE: Group by branch only. Use MODE() to get the most common region for display.
@@ -3235,12 +3931,12 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 			l.branch,
 			MODE() WITHIN GROUP (ORDER BY l.region) AS region,
 			COALESCE(SUM(l.repayment_amount), 0) AS portfolio_total,
-			COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN l.daily_repayment_amount ELSE 0 END), 0) AS due_today,
+			COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN ` + r.dailyRepaymentSQL() + ` ELSE 0 END), 0) AS due_today,
 			COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) AS overdue_15d
 		FROM loans l
 		JOIN officers o ON l.officer_id = o.officer_id
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + r.userTypeFilter() + `
 	`
 
 	loanArgs := []interface{}{}
@@ -3277,7 +3973,7 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		loanQuery += fmt.Sprintf(" AND l.wave = $%d", loanArgCount)
+		loanQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", loanArgCount)
 		loanArgs = append(loanArgs, wave)
 		loanArgCount++
 	}
@@ -3381,8 +4077,8 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 		JOIN loans l ON r.loan_id = l.loan_id
 		JOIN officers o ON l.officer_id = o.officer_id
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-			AND r.is_reversed = FALSE
+			AND ` + r.userTypeFilter() + `
+			AND ` + reversalFilterSQL(false) + `
 			AND r.payment_date::date = CURRENT_DATE
 	`
 
@@ -3419,7 +4115,7 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		repayQuery += fmt.Sprintf(" AND l.wave = $%d", repayArgCount)
+		repayQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", repayArgCount)
 		repayArgs = append(repayArgs, wave)
 		repayArgCount++
 	}
@@ -3506,7 +4202,7 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 	result := make([]*models.BranchCollectionsLeaderboardRow, 0, len(branchMap))
 	for _, row := range branchMap {
 		if row.DueToday > 0 {
-			row.TodayRate = row.CollectedToday / row.DueToday
+			row.TodayRate = safeDivide(row.CollectedToday, row.DueToday)
 			if row.TodayRate < 0 {
 				row.TodayRate = 0
 			}
@@ -3526,11 +4222,7 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 			row.MissedToday = 0
 		}
 
-		if row.PortfolioTotal > 0 {
-			row.NPLRatio = row.Overdue15d / row.PortfolioTotal
-		} else {
-			row.NPLRatio = 0
-		}
+		row.NPLRatio = safeDivide(row.Overdue15d, row.PortfolioTotal)
 
 		// Simple status banding based on NPL ratio (inspired by sample: OK/Watch/Critical).
 		if row.NPLRatio < 0.12 {
@@ -3541,16 +4233,93 @@ func (r *DashboardRepository) GetBranchCollectionsLeaderboard(filters map[string
 			row.Status = "Critical"
 		}
 
+		row.TodayRate = r.roundRatio(row.TodayRate)
+		row.MTDRate = r.roundRatio(row.MTDRate)
+		row.ProgressRate = r.roundRatio(row.ProgressRate)
+		row.NPLRatio = r.roundRatio(row.NPLRatio)
+
 		result = append(result, row)
 	}
 
+	// branchMap iteration order is random; order by collected_today, then
+	// branch name, so identical requests return identical pages.
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CollectedToday != result[j].CollectedToday {
+			return result[i].CollectedToday > result[j].CollectedToday
+		}
+		return result[i].Branch < result[j].Branch
+	})
+
 	return result, nil
 }
 
+// leaderboardRankMetric is a metric the officer collections leaderboard can be
+// ranked by. lowerIsBetter metrics (e.g. missed amounts) rank ascending.
+type leaderboardRankMetric struct {
+	value         func(*models.OfficerCollectionsLeaderboardRow) float64
+	lowerIsBetter bool
+}
+
+// DefaultLeaderboardRankBy is the rank_by metric used when none is given.
+const DefaultLeaderboardRankBy = "collected_today"
+
+// leaderboardRankMetrics lists the rank_by values accepted by
+// GetOfficerCollectionsLeaderboard.
+var leaderboardRankMetrics = map[string]leaderboardRankMetric{
+	"collected_today": {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.CollectedToday }},
+	"due_today":       {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.DueToday }},
+	"today_rate":      {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.TodayRate }},
+	"mtd_rate":        {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.MTDRate }},
+	"progress_rate":   {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.ProgressRate }},
+	"portfolio_total": {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.PortfolioTotal }},
+	"missed_today":    {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.MissedToday }, lowerIsBetter: true},
+	"overdue_15d":     {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.Overdue15d }, lowerIsBetter: true},
+	"npl_ratio":       {value: func(row *models.OfficerCollectionsLeaderboardRow) float64 { return row.NPLRatio }, lowerIsBetter: true},
+}
+
+// rankLeaderboard orders rows best-first by the metric and numbers them.
+// Ties go to the larger portfolio, then the lower officer_id, so ranks are
+// stable across requests.
+func rankLeaderboard(rows []*models.OfficerCollectionsLeaderboardRow, metric leaderboardRankMetric) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := metric.value(rows[i]), metric.value(rows[j])
+		if a != b {
+			if metric.lowerIsBetter {
+				return a < b
+			}
+			return a > b
+		}
+		if rows[i].PortfolioTotal != rows[j].PortfolioTotal {
+			return rows[i].PortfolioTotal > rows[j].PortfolioTotal
+		}
+		return rows[i].OfficerID < rows[j].OfficerID
+	})
+	for i, row := range rows {
+		row.Rank = i + 1
+	}
+}
+
 // GetOfficerCollectionsLeaderboard returns per-officer collections metrics for the
 // Agent/Officer Leaderboard views. It mirrors GetBranchCollectionsLeaderboard but
-// groups by officer instead of branch.
+// groups by officer instead of branch. Rows are ranked by filters["rank_by"]
+// (default collected_today). Officers with fewer than filters["min_loans"]
+// loans or a portfolio_total below filters["min_portfolio"] are left out, so
+// tiny portfolios can't top rate rankings; both default to 0 (everyone).
 func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[string]interface{}) ([]*models.OfficerCollectionsLeaderboardRow, error) {
+	rankBy := DefaultLeaderboardRankBy
+	if v, ok := filters["rank_by"].(string); ok && v != "" {
+		rankBy = v
+	}
+	rankMetric, ok := leaderboardRankMetrics[rankBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported rank_by %q", ErrInvalidFilter, rankBy)
+	}
+	minLoans, _ := filters["min_loans"].(int)
+	minPortfolio, _ := filters["min_portfolio"].(float64)
+	if minLoans < 0 || minPortfolio < 0 {
+		return nil, fmt.Errorf("%w: min_loans and min_portfolio must not be negative", ErrInvalidFilter)
+	}
+
 	// --- First query: loan-based metrics per officer (portfolio, due today, PAR15) ---
 	loanQuery := `
 			SELECT
@@ -3559,13 +4328,14 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 				COALESCE(o.officer_email, '') AS officer_email,
 				MODE() WITHIN GROUP (ORDER BY l.branch) AS branch,
 				MODE() WITHIN GROUP (ORDER BY l.region) AS region,
+				COUNT(DISTINCT l.loan_id) AS loan_count,
 				COALESCE(SUM(l.repayment_amount), 0) AS portfolio_total,
-				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN l.daily_repayment_amount ELSE 0 END), 0) AS due_today,
+				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN ` + r.dailyRepaymentSQL() + ` ELSE 0 END), 0) AS due_today,
 				COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) AS overdue_15d
 			FROM loans l
 			JOIN officers o ON l.officer_id = o.officer_id
 			WHERE 1=1
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+				AND ` + r.userTypeFilter() + `
 		`
 
 	loanArgs := []interface{}{}
@@ -3602,7 +4372,7 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		loanQuery += fmt.Sprintf(" AND l.wave = $%d", loanArgCount)
+		loanQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", loanArgCount)
 		loanArgs = append(loanArgs, wave)
 		loanArgCount++
 	}
@@ -3680,6 +4450,7 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 			&row.OfficerEmail,
 			&row.Branch,
 			&row.Region,
+			&row.LoanCount,
 			&row.PortfolioTotal,
 			&row.DueToday,
 			&row.Overdue15d,
@@ -3707,8 +4478,8 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 			JOIN loans l ON r.loan_id = l.loan_id
 			JOIN officers o ON l.officer_id = o.officer_id
 			WHERE 1=1
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-				AND r.is_reversed = FALSE
+				AND ` + r.userTypeFilter() + `
+				AND ` + reversalFilterSQL(false) + `
 				AND r.payment_date::date = CURRENT_DATE
 		`
 
@@ -3745,7 +4516,7 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		repayQuery += fmt.Sprintf(" AND l.wave = $%d", repayArgCount)
+		repayQuery += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", repayArgCount)
 		repayArgs = append(repayArgs, wave)
 		repayArgCount++
 	}
@@ -3831,8 +4602,12 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 	// --- Finalise metrics: rates, missed amount, NPL proxy & status ---
 	result := make([]*models.OfficerCollectionsLeaderboardRow, 0, len(officerMap))
 	for _, row := range officerMap {
+		if row.LoanCount < minLoans || row.PortfolioTotal < minPortfolio {
+			continue
+		}
+
 		if row.DueToday > 0 {
-			row.TodayRate = row.CollectedToday / row.DueToday
+			row.TodayRate = safeDivide(row.CollectedToday, row.DueToday)
 			if row.TodayRate < 0 {
 				row.TodayRate = 0
 			}
@@ -3850,11 +4625,7 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 			row.MissedToday = 0
 		}
 
-		if row.PortfolioTotal > 0 {
-			row.NPLRatio = row.Overdue15d / row.PortfolioTotal
-		} else {
-			row.NPLRatio = 0
-		}
+		row.NPLRatio = safeDivide(row.Overdue15d, row.PortfolioTotal)
 
 		if row.NPLRatio < 0.12 {
 			row.Status = "OK"
@@ -3864,36 +4635,29 @@ func (r *DashboardRepository) GetOfficerCollectionsLeaderboard(filters map[strin
 			row.Status = "Critical"
 		}
 
+		row.TodayRate = r.roundRatio(row.TodayRate)
+		row.MTDRate = r.roundRatio(row.MTDRate)
+		row.ProgressRate = r.roundRatio(row.ProgressRate)
+		row.NPLRatio = r.roundRatio(row.NPLRatio)
+
 		result = append(result, row)
 	}
 
+	rankLeaderboard(result, rankMetric)
+
 	return result, nil
 }
 
-// GetAgentActivitySummary computes aggregated counts of officers in the
-// Collections Control Centre Agent Activity categories over a rolling 7-day
-// window (past 7 days including today). It respects the same core filters
-// (branch, region, channel, wave, loan_type) used by other collections
-// endpoints and applies the standard officer user_type filter. All date
-// comparisons are based on DATE(r.payment_date).
-func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interface{}) (*models.AgentActivitySummary, error) {
-	query := `
-			WITH filtered_loans AS (
-				SELECT DISTINCT
-					l.loan_id,
-					l.officer_id
-				FROM loans l
-				JOIN officers o ON l.officer_id = o.officer_id
-				WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-		`
-
+// agentActivityLoanFilters builds the loan conditions (branch, region,
+// channel, wave, loan_type) shared by the Agent Activity queries, similar to
+// GetOfficerCollectionsLeaderboard's loanQuery. Placeholders start at $1.
+func agentActivityLoanFilters(filters map[string]interface{}) (string, []interface{}) {
+	where := ""
 	args := []interface{}{}
 	argCount := 1
 
-	// Apply filters (branch, region, channel, wave, loan_type) similar to
-	// GetOfficerCollectionsLeaderboard's loanQuery.
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
-		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
+		where += fmt.Sprintf(" AND l.branch = $%d", argCount)
 		args = append(args, branch)
 		argCount++
 	}
@@ -3901,7 +4665,7 @@ func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interfa
 	if region, ok := filters["region"].(string); ok && region != "" {
 		regions := strings.Split(region, ",")
 		if len(regions) == 1 {
-			query += fmt.Sprintf(" AND l.region = $%d", argCount)
+			where += fmt.Sprintf(" AND l.region = $%d", argCount)
 			args = append(args, regions[0])
 			argCount++
 		} else {
@@ -3911,12 +4675,12 @@ func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interfa
 				args = append(args, strings.TrimSpace(rgn))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
+			where += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	if channel, ok := filters["channel"].(string); ok && channel != "" {
-		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
+		where += fmt.Sprintf(" AND l.channel = $%d", argCount)
 		args = append(args, channel)
 		argCount++
 	}
@@ -3924,24 +4688,24 @@ func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interfa
 	if wave, ok := filters["wave"].(string); ok && strings.TrimSpace(wave) != "" {
 		waves := strings.Split(wave, ",")
 		if len(waves) == 1 {
-			query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+			where += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 			args = append(args, strings.TrimSpace(waves[0]))
 			argCount++
 		} else {
 			placeholders := make([]string, len(waves))
 			for i, w := range waves {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
+				placeholders[i] = fmt.Sprintf("normalize_wave($%d)", argCount)
 				args = append(args, strings.TrimSpace(w))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.wave IN (%s)", strings.Join(placeholders, ", "))
+			where += fmt.Sprintf(" AND l.wave IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
 		loanTypes := strings.Split(loanType, ",")
 		if len(loanTypes) == 1 {
-			query += fmt.Sprintf(" AND l.loan_type = $%d", argCount)
+			where += fmt.Sprintf(" AND l.loan_type = $%d", argCount)
 			args = append(args, strings.TrimSpace(loanTypes[0]))
 			argCount++
 		} else {
@@ -3951,10 +4715,57 @@ func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interfa
 				args = append(args, strings.TrimSpace(lt))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.loan_type IN (%s)", strings.Join(placeholders, ", "))
+			where += fmt.Sprintf(" AND l.loan_type IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
+	return where, args
+}
+
+// GetStartedTodayCount returns only the started_today Agent Activity count:
+// officers in scope with at least one non-reversed collection today. It is the
+// same population and condition as GetAgentActivitySummary's
+// started_today_count, without computing the 7-day categories.
+func (r *DashboardRepository) GetStartedTodayCount(filters map[string]interface{}) (int, error) {
+	where, args := agentActivityLoanFilters(filters)
+
+	query := `
+			SELECT COUNT(DISTINCT l.officer_id)
+			FROM loans l
+			JOIN officers o ON l.officer_id = o.officer_id
+			JOIN repayments r ON r.loan_id = l.loan_id
+			WHERE ` + r.userTypeFilter() + where + `
+				AND ` + reversalFilterSQL(false) + `
+				AND DATE(r.payment_date) = CURRENT_DATE
+		`
+
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count officers started today: %w", err)
+	}
+	return count, nil
+}
+
+// GetAgentActivitySummary computes aggregated counts of officers in the
+// Collections Control Centre Agent Activity categories over a rolling 7-day
+// window (past 7 days including today). It respects the same core filters
+// (branch, region, channel, wave, loan_type) used by other collections
+// endpoints and applies the standard officer user_type filter. All date
+// comparisons are based on DATE(r.payment_date).
+func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interface{}) (*models.AgentActivitySummary, error) {
+	query := `
+			WITH filtered_loans AS (
+				SELECT DISTINCT
+					l.loan_id,
+					l.officer_id
+				FROM loans l
+				JOIN officers o ON l.officer_id = o.officer_id
+				WHERE ` + r.userTypeFilter() + `
+		`
+
+	where, args := agentActivityLoanFilters(filters)
+	query += where
+
 	query += `
 			),
 			officer_base AS (
@@ -3968,7 +4779,7 @@ func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interfa
 					SUM(r.payment_amount) AS amount
 				FROM filtered_loans fl
 				JOIN repayments r ON r.loan_id = fl.loan_id
-				WHERE r.is_reversed = FALSE
+				WHERE ` + reversalFilterSQL(false) + `
 					AND DATE(r.payment_date) >= (CURRENT_DATE - INTERVAL '6 days')
 					AND DATE(r.payment_date) <= CURRENT_DATE
 				GROUP BY fl.officer_id, DATE(r.payment_date)
@@ -3985,13 +4796,15 @@ func (r *DashboardRepository) GetAgentActivitySummary(filters map[string]interfa
 						WHERE r7.payment_date >= (CURRENT_DATE - INTERVAL '2 days')
 							AND r7.payment_date <= CURRENT_DATE
 					), 0) AS amount_last3,
-							-- Count of distinct business days (Mon-Fri) with at least one collection
-							-- in the 7-calendar-day window. Weekends are excluded from this count but
-							-- their repayments are still included in total_7d/amount_first4/amount_last3.
+							-- Count of distinct business days (Mon-Fri, excluding holidays) with at
+							-- least one collection in the 7-calendar-day window. Weekends and holidays
+							-- are excluded from this count but their repayments are still included in
+							-- total_7d/amount_first4/amount_last3.
 							COALESCE(COUNT(DISTINCT r7.payment_date) FILTER (
 								WHERE r7.payment_date >= (CURRENT_DATE - INTERVAL '6 days')
 									AND r7.payment_date <= CURRENT_DATE
 									AND EXTRACT(ISODOW FROM r7.payment_date) BETWEEN 1 AND 5
+									AND NOT EXISTS (SELECT 1 FROM holiday h WHERE h.date = r7.payment_date AND ` + companyHolidaySQL + `)
 							), 0) AS days_with_collection_7d,
 					COALESCE(COUNT(DISTINCT r7.payment_date) FILTER (
 						WHERE r7.payment_date = CURRENT_DATE
@@ -4051,7 +4864,7 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 						l.region
 					FROM loans l
 					JOIN officers o ON l.officer_id = o.officer_id
-					WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+					WHERE ` + r.userTypeFilter() + `
 			`
 
 	args := []interface{}{}
@@ -4091,13 +4904,13 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 	if wave, ok := filters["wave"].(string); ok && strings.TrimSpace(wave) != "" {
 		waves := strings.Split(wave, ",")
 		if len(waves) == 1 {
-			query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+			query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 			args = append(args, strings.TrimSpace(waves[0]))
 			argCount++
 		} else {
 			placeholders := make([]string, len(waves))
 			for i, w := range waves {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
+				placeholders[i] = fmt.Sprintf("normalize_wave($%d)", argCount)
 				args = append(args, strings.TrimSpace(w))
 				argCount++
 			}
@@ -4135,7 +4948,7 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 						SUM(r.payment_amount) AS amount
 					FROM filtered_loans fl
 					JOIN repayments r ON r.loan_id = fl.loan_id
-					WHERE r.is_reversed = FALSE
+					WHERE ` + reversalFilterSQL(false) + `
 						AND DATE(r.payment_date) >= (CURRENT_DATE - INTERVAL '6 days')
 						AND DATE(r.payment_date) <= CURRENT_DATE
 					GROUP BY fl.officer_id, DATE(r.payment_date)
@@ -4152,13 +4965,15 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 							WHERE r7.payment_date >= (CURRENT_DATE - INTERVAL '2 days')
 								AND r7.payment_date <= CURRENT_DATE
 						), 0) AS amount_last3,
-						-- Count of distinct business days (Mon-Fri) with at least one collection
-						-- in the 7-calendar-day window. Weekends are excluded from this count but
-						-- their repayments are still included in total_7d/amount_first4/amount_last3.
+						-- Count of distinct business days (Mon-Fri, excluding holidays) with at
+						-- least one collection in the 7-calendar-day window. Weekends and holidays
+						-- are excluded from this count but their repayments are still included in
+						-- total_7d/amount_first4/amount_last3.
 						COALESCE(COUNT(DISTINCT r7.payment_date) FILTER (
 							WHERE r7.payment_date >= (CURRENT_DATE - INTERVAL '6 days')
 								AND r7.payment_date <= CURRENT_DATE
 								AND EXTRACT(ISODOW FROM r7.payment_date) BETWEEN 1 AND 5
+								AND NOT EXISTS (SELECT 1 FROM holiday h WHERE h.date = r7.payment_date AND ` + companyHolidaySQL + `)
 						), 0) AS days_with_collection_7d,
 						COALESCE(COUNT(DISTINCT r7.payment_date) FILTER (
 							WHERE r7.payment_date = CURRENT_DATE
@@ -4206,11 +5021,13 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 				oi.branch,
 				oi.region,
 				CASE
-							-- Repayment rate is based on business days only. There are always 5
-							-- business days in any 7-calendar-day window, so we divide by 5.0
-							-- instead of 7. Weekend repayments still contribute to total_7d and
-							-- the per-day amounts but do not increase the denominator.
-							WHEN po.days_with_collection_7d > 0 THEN (po.days_with_collection_7d::float / 5.0) * 100.0
+							-- Repayment rate is based on business days only: the 5 weekdays in any
+							-- 7-calendar-day window, less any holidays among them. Weekend and
+							-- holiday repayments still contribute to total_7d and the per-day
+							-- amounts but do not increase the denominator. A collection day
+							-- implies the denominator is at least 1.
+							WHEN po.days_with_collection_7d > 0 THEN (po.days_with_collection_7d::float /
+								count_collection_days((CURRENT_DATE - INTERVAL '6 days')::date, CURRENT_DATE)) * 100.0
 					ELSE 0
 					END AS repayment_rate,
 					po.amount_5d_ago,
@@ -4240,7 +5057,7 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 	case "started_today":
 		query += " WHERE po.days_with_collection_today > 0"
 	default:
-		return nil, fmt.Errorf("unknown agent activity category: %s", category)
+		return nil, fmt.Errorf("%w: unknown agent activity category: %s", ErrInvalidFilter, category)
 	}
 
 	query += " ORDER BY po.total_7d DESC, oi.officer_name ASC"
@@ -4283,8 +5100,8 @@ func (r *DashboardRepository) GetAgentActivityDetail(filters map[string]interfac
 }
 
 // GetRepaymentWatchOfficers computes per-officer Wave 2 repayment performance for the
-// Repayment Watch view. It focuses on Wave 2 loans that are currently OPEN or
-// PAST_MATURITY and counts non-reversed repayments made today. It respects the same
+// Repayment Watch view. It focuses on Wave 2 loans that are currently open
+// (OpenDjangoStatuses) and counts non-reversed repayments made today. It respects the same
 // branch/region/channel/wave/loan_type filters used elsewhere in the Collections
 // Control Centre.
 func (r *DashboardRepository) GetRepaymentWatchOfficers(filters map[string]interface{}) ([]*models.RepaymentWatchOfficerRow, error) {
@@ -4301,11 +5118,11 @@ func (r *DashboardRepository) GetRepaymentWatchOfficers(filters map[string]inter
 				FROM loans l
 				JOIN officers o ON l.officer_id = o.officer_id
 				LEFT JOIN repayments r ON r.loan_id = l.loan_id
-					AND r.is_reversed = FALSE
+					AND ` + reversalFilterSQL(false) + `
 					AND r.payment_date::date = CURRENT_DATE
 				WHERE 1=1
-					AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-					AND l.django_status IN ('OPEN', 'PAST_MATURITY')
+					AND ` + r.userTypeFilter() + `
+					AND ` + r.openStatusFilter() + `
 			`
 
 	args := []interface{}{}
@@ -4347,13 +5164,13 @@ func (r *DashboardRepository) GetRepaymentWatchOfficers(filters map[string]inter
 		// Support comma-separated waves for completeness
 		waves := strings.Split(wave, ",")
 		if len(waves) == 1 {
-			query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+			query += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 			args = append(args, strings.TrimSpace(waves[0]))
 			argCount++
 		} else {
 			placeholders := make([]string, len(waves))
 			for i, w := range waves {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
+				placeholders[i] = fmt.Sprintf("normalize_wave($%d)", argCount)
 				args = append(args, strings.TrimSpace(w))
 				argCount++
 			}
@@ -4361,7 +5178,7 @@ func (r *DashboardRepository) GetRepaymentWatchOfficers(filters map[string]inter
 		}
 	} else {
 		// Default focus: Wave 2 loans only (case-insensitive, supports "wave2" and "wave 2").
-		query += " AND LOWER(COALESCE(l.wave, '')) IN ('wave2', 'wave 2')"
+		query += " AND l.wave = 'Wave 2'"
 	}
 
 	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
@@ -4392,189 +5209,1533 @@ func (r *DashboardRepository) GetRepaymentWatchOfficers(filters map[string]inter
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*models.RepaymentWatchOfficerRow{}
+	for rows.Next() {
+		row := &models.RepaymentWatchOfficerRow{}
+		if err := rows.Scan(
+			&row.OfficerID,
+			&row.OfficerName,
+			&row.OfficerEmail,
+			&row.Branch,
+			&row.Region,
+			&row.TotalWave2OpenLoans,
+			&row.LoansWithRepaymentToday,
+			&row.AmountCollectedToday,
+		); err != nil {
+			return nil, err
+		}
+
+		row.RepaymentRate = safeDivide(float64(row.LoansWithRepaymentToday), float64(row.TotalWave2OpenLoans)) * 100
+
+		result = append(result, row)
+	}
+
+	return result, nil
+}
+
+// GetFilterOptions retrieves filter dropdown options
+//
+// Results are cached for FilterOptionsCacheTTL, keyed by filter type and
+// filters, until InvalidateFilterOptions is called. Setting
+// filters["bypass_cache"] to true reads straight from the database.
+func (r *DashboardRepository) GetFilterOptions(filterType string, filters map[string]interface{}) (interface{}, error) {
+	bypass, _ := filters["bypass_cache"].(bool)
+	key := filterOptionsCacheKey(filterType, filters)
+	if !bypass {
+		if options, ok := r.filterCache.get(key); ok {
+			return options, nil
+		}
+	}
+
+	options, err := r.loadFilterOptions(filterType, filters)
+	if err != nil {
+		return nil, err
+	}
+	r.filterCache.set(key, options)
+	return options, nil
+}
+
+// InvalidateFilterOptions drops all cached filter options. Call it when a
+// sync completes so new branches, officers, etc. show up immediately.
+func (r *DashboardRepository) InvalidateFilterOptions() {
+	r.filterCache.invalidate()
+}
+
+// loadFilterOptions queries the distinct values for a filter type.
+func (r *DashboardRepository) loadFilterOptions(filterType string, filters map[string]interface{}) (interface{}, error) {
+	switch filterType {
+	case "branches":
+		return r.getBranches(filters)
+	case "regions":
+		return r.getRegions()
+	case "waves":
+		return r.getWaves()
+	case "channels":
+		return r.getChannels()
+	case "user-types":
+		return r.getUserTypes()
+	case "officers":
+		return r.getOfficerOptions(filters)
+	case "statuses":
+		return r.getStatuses()
+	case "loan-types":
+		return r.getLoanTypes()
+	case "verification-statuses":
+		return r.getVerificationStatuses()
+	case "django-statuses":
+		return r.getDjangoStatuses()
+	case "vertical-leads":
+		return r.getVerticalLeads()
+	case "supervisors":
+		return r.getSupervisors()
+	default:
+		return nil, fmt.Errorf("%w: unknown filter type: %s", ErrInvalidFilter, filterType)
+	}
+}
+
+func (r *DashboardRepository) getBranches(filters map[string]interface{}) ([]string, error) {
+	// Like getRegions, branches are the union of branches on loans and
+	// branches configured on officers, so a newly opened branch with no
+	// disbursements yet is still selectable.
+	loanRegion, officerRegion := "", ""
+	args := []interface{}{}
+	argCount := 1
+
+	if region, ok := filters["region"].(string); ok && region != "" {
+		// Support comma-separated regions for multi-select
+		regions := strings.Split(region, ",")
+		if len(regions) == 1 {
+			loanRegion = fmt.Sprintf(" AND l.region = $%d", argCount)
+			officerRegion = fmt.Sprintf(" AND o.region = $%d", argCount)
+			args = append(args, regions[0])
+			argCount++
+		} else {
+			// Build IN clause for multiple regions
+			placeholders := []string{}
+			for _, r := range regions {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+				args = append(args, strings.TrimSpace(r))
+				argCount++
+			}
+			loanRegion = fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
+			officerRegion = fmt.Sprintf(" AND o.region IN (%s)", strings.Join(placeholders, ", "))
+		}
+	}
+
+	query := `
+		SELECT DISTINCT branch
+		FROM (
+			SELECT l.branch AS branch
+			FROM loans l
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE ` + r.userTypeFilter() + loanRegion + `
+
+			UNION
+
+			SELECT o.branch AS branch
+			FROM officers o
+			WHERE ` + r.userTypeFilter() + officerRegion + `
+		) branches
+		WHERE branch IS NOT NULL AND branch != ''
+		ORDER BY branch`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	branches := []string{}
+	for rows.Next() {
+		var branch string
+		if err := rows.Scan(&branch); err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+// GetCollectionHeatmap returns collected amounts per calendar day over the last
+// `weeks` ISO weeks (Monday-Sunday, including the current week up to today),
+// tagged with the week start and ISO day of week for a day-of-week x week
+// heatmap. Every day in the range is present, zero-filled when nothing was
+// collected, so weekends are never omitted. Days of the current week carry
+// PartialWeek, since that week only runs up to today.
+func (r *DashboardRepository) GetCollectionHeatmap(filters map[string]interface{}, weeks int) ([]*models.CollectionHeatmapCell, error) {
+	loanFilters, args, _ := buildLoanFilters(filters, 2)
+	args = append([]interface{}{weeks}, args...)
+
+	query := `
+		WITH days AS (
+			SELECT d::date AS day
+			FROM generate_series(
+				DATE_TRUNC('week', CURRENT_DATE)::date - ($1::int - 1) * 7,
+				CURRENT_DATE,
+				INTERVAL '1 day'
+			) AS d
+		),
+		collected AS (
+			SELECT
+				DATE(r.payment_date) AS day,
+				SUM(r.payment_amount) AS collected_amount,
+				COUNT(*) AS repayments_count
+			FROM repayments r
+			INNER JOIN loans l ON r.loan_id = l.loan_id
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE ` + reversalFilterSQL(false) + `
+				AND ` + r.userTypeFilter() + `
+				AND DATE(r.payment_date) >= DATE_TRUNC('week', CURRENT_DATE)::date - ($1::int - 1) * 7
+				AND DATE(r.payment_date) <= CURRENT_DATE
+	` + loanFilters + `
+			GROUP BY DATE(r.payment_date)
+		)
+		SELECT
+			TO_CHAR(DATE_TRUNC('week', d.day), 'YYYY-MM-DD') AS week_start,
+			EXTRACT(ISODOW FROM d.day)::int AS day_of_week,
+			TO_CHAR(d.day, 'YYYY-MM-DD') AS date,
+			COALESCE(c.collected_amount, 0) AS collected_amount,
+			COALESCE(c.repayments_count, 0) AS repayments_count,
+			DATE_TRUNC('week', d.day) = DATE_TRUNC('week', CURRENT_DATE) AS partial_week
+		FROM days d
+		LEFT JOIN collected c ON c.day = d.day
+		ORDER BY d.day
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve collection heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	cells := []*models.CollectionHeatmapCell{}
+	for rows.Next() {
+		cell := &models.CollectionHeatmapCell{}
+		if err := rows.Scan(
+			&cell.WeekStart,
+			&cell.DayOfWeek,
+			&cell.Date,
+			&cell.CollectedAmount,
+			&cell.RepaymentsCount,
+			&cell.PartialWeek,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan collection heatmap row: %w", err)
+		}
+		cells = append(cells, cell)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate collection heatmap rows: %w", err)
+	}
+
+	return cells, nil
+}
+
+// GetCollectionsProgress sums what the filtered loans were expected to pay in
+// the period against what was actually collected from them. See
+// models.CollectionsProgress for how the expected amount is derived. Loans
+// rejected, cancelled or closed before the period starts are excluded. An
+// unknown period is rejected with ErrInvalidFilter (see resolvePeriodRange).
+func (r *DashboardRepository) GetCollectionsProgress(filters map[string]interface{}, period string) (*models.CollectionsProgress, error) {
+	period = strings.ToLower(strings.TrimSpace(period))
+	periodStart, periodEnd, err := resolvePeriodRange(period)
+	if err != nil {
+		return nil, err
+	}
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+
+	query := fmt.Sprintf(`
+		WITH scoped AS (
+			SELECT
+				l.loan_id,
+				COALESCE(%[3]s, 0) AS daily_amount,
+				GREATEST(%[1]s, COALESCE(l.first_payment_due_date, l.disbursement_date)::date) AS due_from,
+				LEAST(%[2]s, COALESCE(l.maturity_date, %[2]s), COALESCE(l.closed_date, %[2]s)) AS due_to
+			FROM loans l
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE %[4]s
+				AND UPPER(l.status) NOT IN ('REJECTED', 'CANCELLED')
+				AND (l.closed_date IS NULL OR l.closed_date >= %[1]s)
+				%[5]s
+		),
+		collected AS (
+			SELECT COALESCE(SUM(r.payment_amount), 0) AS amount
+			FROM repayments r
+			INNER JOIN scoped s ON s.loan_id = r.loan_id
+			WHERE `+reversalFilterSQL(false)+`
+				AND %[6]s
+		)
+		SELECT
+			TO_CHAR(%[1]s, 'YYYY-MM-DD'),
+			TO_CHAR(%[2]s, 'YYYY-MM-DD'),
+			count_collection_days(%[1]s, %[2]s),
+			COUNT(*) FILTER (WHERE count_collection_days(s.due_from, s.due_to) > 0),
+			COALESCE(SUM(s.daily_amount * count_collection_days(s.due_from, s.due_to)), 0),
+			(SELECT amount FROM collected)
+		FROM scoped s
+	`, periodStart, periodEnd, r.dailyRepaymentSQL(), r.userTypeFilter(), loanFilters,
+		periodDateFilter("DATE(r.payment_date)", periodStart, periodEnd))
+
+	progress := &models.CollectionsProgress{Period: period}
+	err = r.db.QueryRow(query, args...).Scan(
+		&progress.PeriodStart,
+		&progress.PeriodEnd,
+		&progress.BusinessDays,
+		&progress.LoansExpected,
+		&progress.ExpectedAmount,
+		&progress.CollectedAmount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve collections progress: %w", err)
+	}
+
+	progress.ProgressRatio = safeDivide(progress.CollectedAmount, progress.ExpectedAmount)
+
+	return progress, nil
+}
+
+// normalizedPaymentMethodSQL maps repayments.payment_method (aliased r) onto
+// the canonical collection methods: AGENT_DEBIT, TRANSFER, ESCROW_DEBIT, and
+// OTHER for everything else, including NULL or blank values.
+const normalizedPaymentMethodSQL = `(CASE
+					WHEN UPPER(TRIM(r.payment_method)) IN ('AGENT_DEBIT', 'TRANSFER', 'ESCROW_DEBIT') THEN UPPER(TRIM(r.payment_method))
+					ELSE 'OTHER'
+				END)`
+
+// collectionPaymentMethods lists the normalised payment methods in display order.
+var collectionPaymentMethods = []string{"AGENT_DEBIT", "TRANSFER", "ESCROW_DEBIT", "OTHER"}
+
+// collectionsPeriodRange resolves a collections period name into SQL
+// expressions for its inclusive start and end dates, relative to today on the
+// server clock. Unrecognised values fall back to "today".
+func collectionsPeriodRange(period string) (string, string) {
+	return collectionsPeriodRangeAt(period, time.Now())
+}
+
+// collectionsPeriodRangeAt is collectionsPeriodRange relative to the calendar
+// date of today. The bounds are computed in Go and rendered as DATE literals.
+func collectionsPeriodRangeAt(period string, today time.Time) (string, string) {
+	start, end := collectionsPeriodBounds(period, today)
+	return dateLiteralSQL(start), dateLiteralSQL(end)
+}
+
+// collectionsPeriodBounds returns the inclusive start and end dates of period
+// relative to the calendar date of today. Weeks start on Monday.
+func collectionsPeriodBounds(period string, today time.Time) (time.Time, time.Time) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	firstOfMonth := today.AddDate(0, 0, 1-today.Day())
+
+	switch period {
+	case "this_week":
+		return monday, today
+	case "this_month":
+		return firstOfMonth, today
+	case "last_month":
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1)
+	case "last_7_days":
+		// Custom period for the Collections Control Centre daily chart:
+		// always show the last 7 calendar days (including today).
+		return today.AddDate(0, 0, -6), today
+	case "yesterday":
+		yesterday := today.AddDate(0, 0, -1)
+		return yesterday, yesterday
+	case "last_week":
+		return monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1)
+	default: // "today" or any unrecognised value
+		return today, today
+	}
+}
+
+// dateLiteralSQL renders date as a SQL DATE literal.
+func dateLiteralSQL(date time.Time) string {
+	return fmt.Sprintf("DATE '%s'", date.Format("2006-01-02"))
+}
+
+// periodDateFilter restricts the date expression column to the inclusive
+// [start, end] range from collectionsPeriodRange or resolvePeriodRange. It is
+// written half-open (>= start AND < the day after end) so nothing from the
+// day after end, e.g. the first of the current month for last_month, can
+// slip in even if column carries a time of day.
+func periodDateFilter(column, start, end string) string {
+	return fmt.Sprintf("(%s >= %s AND %s < (%s) + 1)", column, start, column, end)
+}
+
+// repaymentReversedSQL is whether a repayment aliased r was reversed. Rows
+// synced before is_reversed existed carry NULL, which counts as not reversed.
+const repaymentReversedSQL = "COALESCE(r.is_reversed, false)"
+
+// reversalFilterSQL is the reversal condition for repayments aliased r:
+// reversed repayments are excluded unless includeReversed is set, e.g. for
+// auditors reconciling gross against net collections. A NULL is_reversed is
+// treated as not reversed, so those rows are kept either way.
+func reversalFilterSQL(includeReversed bool) string {
+	if includeReversed {
+		return "TRUE"
+	}
+	return "NOT " + repaymentReversedSQL
+}
+
+// repaymentNotReversedSQL is reversalFilterSQL(false) for repayments under
+// another alias, e.g. in correlated subqueries nested inside an r scope.
+func repaymentNotReversedSQL(alias string) string {
+	return fmt.Sprintf("NOT COALESCE(%s.is_reversed, false)", alias)
+}
+
+// inListFilter turns a comma-separated multi-select filter value into a
+// parenthesised condition on column, numbering placeholders from *argCount.
+// MissingValueSentinel matches NULL or blank values. It returns "" when raw
+// holds no values.
+func inListFilter(column, raw string, argCount *int) (string, []interface{}) {
+	var placeholders []string
+	var args []interface{}
+	includeMissing := false
+
+	for _, v := range strings.Split(raw, ",") {
+		value := strings.TrimSpace(v)
+		if value == "" {
+			continue
+		}
+		if value == MissingValueSentinel {
+			includeMissing = true
+			continue
+		}
+		placeholders = append(placeholders, fmt.Sprintf("$%d", *argCount))
+		args = append(args, value)
+		*argCount++
+	}
+
+	conditions := []string{}
+	if len(placeholders) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ",")))
+	}
+	if includeMissing {
+		conditions = append(conditions, fmt.Sprintf("(%s IS NULL OR %s = '')", column, column))
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// supervisorFilter restricts loans aliased l to those whose officer reports
+// to one of the comma-separated supervisor emails in raw (see inListFilter;
+// MissingValueSentinel matches officers without a supervisor). It goes through
+// a subquery so it works whether or not the officers table is joined.
+func supervisorFilter(raw string, argCount *int) (string, []interface{}) {
+	cond, args := inListFilter("so.supervisor_email", raw, argCount)
+	if cond == "" {
+		return "", nil
+	}
+	return "l.officer_id IN (SELECT so.officer_id FROM officers so WHERE " + cond + ")", args
+}
+
+// CollectionsPeriods lists the period names collectionsPeriodRange resolves.
+var CollectionsPeriods = config.CollectionsPeriods
+
+// resolvePeriodRange is collectionsPeriodRange for callers that must reject
+// unknown periods instead of falling back to today. Besides the named
+// periods it accepts an explicit "YYYY-MM-DD..YYYY-MM-DD" date range.
+func resolvePeriodRange(period string) (string, string, error) {
+	period = strings.ToLower(strings.TrimSpace(period))
+	for _, name := range CollectionsPeriods {
+		if period == name {
+			start, end := collectionsPeriodRange(period)
+			return start, end, nil
+		}
+	}
+
+	from, to, ok := strings.Cut(period, "..")
+	if !ok {
+		return "", "", fmt.Errorf("%w: unsupported period %q", ErrInvalidFilter, period)
+	}
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid period start %q", ErrInvalidFilter, from)
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid period end %q", ErrInvalidFilter, to)
+	}
+	if toDate.Before(fromDate) {
+		return "", "", fmt.Errorf("%w: period %q ends before it starts", ErrInvalidFilter, period)
+	}
+	// Both dates were parsed above, so they are safe to inline.
+	return dateLiteralSQL(fromDate), dateLiteralSQL(toDate), nil
+}
+
+// GetNonBusinessDayRepayments lists non-reversed repayments whose
+// payment_date falls on a Saturday or Sunday within period (see
+// resolvePeriodRange), newest first. With includeHolidays, dates in the
+// company-wide holidays count as non-business days too; with the include_reversed
+// filter, reversed repayments are listed as well, flagged by is_reversed.
+// Returns the page and the total number of matching repayments.
+func (r *DashboardRepository) GetNonBusinessDayRepayments(filters map[string]interface{}, period string, includeHolidays bool) ([]*models.NonBusinessDayRepayment, int, error) {
+	start, end, err := resolvePeriodRange(period)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit, offset, err := r.pageBounds(filters, 50)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	includeReversed, _ := filters["include_reversed"].(bool)
+
+	holidayJoin := ""
+	holidayName := "NULL::text"
+	nonBusinessDay := "EXTRACT(ISODOW FROM r.payment_date) IN (6, 7)"
+	if includeHolidays {
+		holidayJoin = `
+		LEFT JOIN (
+			SELECT h.date, MIN(h.name) AS name
+			FROM holiday h
+			WHERE ` + companyHolidaySQL + `
+			GROUP BY h.date
+		) h ON h.date = r.payment_date`
+		holidayName = "h.name"
+		nonBusinessDay = "(" + nonBusinessDay + " OR h.date IS NOT NULL)"
+	}
+
+	loanFilters, args, argCount := buildLoanFilters(filters, 1)
+
+	fromWhere := fmt.Sprintf(`
+		FROM repayments r
+		INNER JOIN loans l ON r.loan_id = l.loan_id
+		INNER JOIN officers o ON l.officer_id = o.officer_id%s
+		WHERE %s
+			AND %s
+			AND %s
+			AND %s
+			%s`, holidayJoin, reversalFilterSQL(includeReversed), periodDateFilter("r.payment_date", start, end), nonBusinessDay, r.userTypeFilter(), loanFilters)
+
+	// Counted separately so the total is still reported for a page past the
+	// last repayment.
+	total := 0
+	if err := r.db.QueryRow("SELECT COUNT(*)"+fromWhere, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count non-business-day repayments: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			r.repayment_id,
+			r.loan_id,
+			COALESCE(l.customer_name, '') AS customer_name,
+			l.officer_id,
+			o.officer_name,
+			COALESCE(l.branch, '') AS branch,
+			COALESCE(l.region, '') AS region,
+			TO_CHAR(r.payment_date, 'YYYY-MM-DD') AS payment_date,
+			r.payment_amount,
+			COALESCE(r.payment_method, '') AS payment_method,
+			r.payment_channel,
+			COALESCE(r.is_backdated, false) AS is_backdated,
+			COALESCE(r.is_reversed, false) AS is_reversed,
+			CASE EXTRACT(ISODOW FROM r.payment_date)
+				WHEN 6 THEN 'saturday'
+				WHEN 7 THEN 'sunday'
+				ELSE 'holiday'
+			END AS day_type,
+			%s AS holiday_name
+		%s
+		ORDER BY r.payment_date DESC, r.repayment_id DESC
+		LIMIT $%d OFFSET $%d
+	`, holidayName, fromWhere, argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve non-business-day repayments: %w", err)
+	}
+	defer rows.Close()
+
+	repayments := []*models.NonBusinessDayRepayment{}
+	for rows.Next() {
+		rp := &models.NonBusinessDayRepayment{}
+		if err := rows.Scan(
+			&rp.RepaymentID,
+			&rp.LoanID,
+			&rp.CustomerName,
+			&rp.OfficerID,
+			&rp.OfficerName,
+			&rp.Branch,
+			&rp.Region,
+			&rp.PaymentDate,
+			&rp.PaymentAmount,
+			&rp.PaymentMethod,
+			&rp.PaymentChannel,
+			&rp.IsBackdated,
+			&rp.IsReversed,
+			&rp.DayType,
+			&rp.HolidayName,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan non-business-day repayment: %w", err)
+		}
+		repayments = append(repayments, rp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate non-business-day repayments: %w", err)
+	}
+
+	return repayments, total, nil
+}
+
+// companyHolidaySQL restricts the holiday table (aliased h) to company-wide
+// holidays: those without an agent or branch. The table is synced from
+// Django; holidays added through UpsertHoliday carry negative ids.
+const companyHolidaySQL = "h.agent_id IS NULL AND h.branch_id IS NULL"
+
+// UpsertHoliday adds a company-wide holiday to the holiday table, or renames
+// the one previously added on that date. The date must be YYYY-MM-DD. Added
+// holidays take negative ids from holiday_local_id_seq so the Django holiday
+// sync, which only replaces positive ids, keeps them.
+func (r *DashboardRepository) UpsertHoliday(input *models.HolidayInput) (*models.Holiday, error) {
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(input.Date))
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidFilter)
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidFilter)
+	}
+
+	query := `
+		INSERT INTO holiday (id, date, name, type, salary_waver, created_at, updated_at)
+		VALUES (-nextval('holiday_local_id_seq'), $1, $2, 'company', FALSE, NOW(), NOW())
+		ON CONFLICT (date) WHERE id < 0 DO UPDATE
+		SET name = EXCLUDED.name, updated_at = NOW()
+		RETURNING TO_CHAR(date, 'YYYY-MM-DD'), name, created_at, updated_at
+	`
+
+	holiday := &models.Holiday{Source: models.HolidaySourceAPI}
+	err = r.db.QueryRow(query, date.Format("2006-01-02"), name).Scan(
+		&holiday.Date,
+		&holiday.Name,
+		&holiday.CreatedAt,
+		&holiday.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save holiday: %w", err)
+	}
+
+	return holiday, nil
+}
+
+// GetHolidays lists company-wide holidays in date order, limited to one
+// calendar year when year is non-zero. A date can appear twice when it was
+// both synced from Django and added through the API.
+func (r *DashboardRepository) GetHolidays(year int) ([]*models.Holiday, error) {
+	query := `
+		SELECT
+			TO_CHAR(h.date, 'YYYY-MM-DD'),
+			COALESCE(h.name, ''),
+			CASE WHEN h.id < 0 THEN '` + models.HolidaySourceAPI + `' ELSE '` + models.HolidaySourceDjango + `' END,
+			h.created_at,
+			h.updated_at
+		FROM holiday h
+		WHERE h.date IS NOT NULL
+			AND ` + companyHolidaySQL + `
+	`
+	args := []interface{}{}
+	if year != 0 {
+		query += " AND EXTRACT(YEAR FROM h.date) = $1"
+		args = append(args, year)
+	}
+	query += " ORDER BY h.date, h.id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []*models.Holiday{}
+	for rows.Next() {
+		holiday := &models.Holiday{}
+		if err := rows.Scan(&holiday.Date, &holiday.Name, &holiday.Source, &holiday.CreatedAt, &holiday.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays = append(holidays, holiday)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate holidays: %w", err)
+	}
+
+	return holidays, nil
+}
+
+// DeleteHoliday removes the holiday added through the API on date
+// (YYYY-MM-DD). Holidays synced from Django are managed there. Returns
+// ErrNotFound if no API-added holiday exists on that date.
+func (r *DashboardRepository) DeleteHoliday(date string) error {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidFilter)
+	}
+
+	result, err := r.db.Exec("DELETE FROM holiday WHERE date = $1 AND id < 0", parsed.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: no holiday added through the API on %s", ErrNotFound, date)
+	}
+
+	return nil
+}
+
+// CaptureOfficerSnapshots records today's portfolio, overdue and collection
+// figures for every dashboard officer in officer_snapshots, replacing any
+// snapshot already captured today. Portfolio figures cover loans in an open
+// django_status (OpenDjangoStatuses); collections cover all the officer's
+// loans. Returns the number of officers captured.
+func (r *DashboardRepository) CaptureOfficerSnapshots() (int64, error) {
+	query := `
+		INSERT INTO officer_snapshots (
+			snapshot_date, officer_id, active_loans, portfolio_total,
+			total_outstanding, principal_outstanding, overdue_15d, par15_ratio,
+			loans_in_arrears, due_today, collected_today, collected_mtd
+		)
+		SELECT
+			CURRENT_DATE,
+			o.officer_id,
+			COUNT(l.loan_id),
+			COALESCE(SUM(l.repayment_amount), 0),
+			COALESCE(SUM(l.total_outstanding), 0),
+			COALESCE(SUM(l.principal_outstanding), 0),
+			COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END)
+				/ NULLIF(SUM(l.principal_outstanding), 0), 0),
+			COUNT(CASE WHEN l.current_dpd >= 1 THEN 1 END),
+			COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN ` + r.dailyRepaymentSQL() + ` ELSE 0 END), 0),
+			COALESCE(MAX(c.collected_today), 0),
+			COALESCE(MAX(c.collected_mtd), 0)
+		FROM officers o
+		LEFT JOIN loans l ON l.officer_id = o.officer_id
+			AND ` + r.openStatusFilter() + `
+		LEFT JOIN (
+			SELECT
+				cl.officer_id,
+				SUM(CASE WHEN r.payment_date::date = CURRENT_DATE THEN r.payment_amount ELSE 0 END) AS collected_today,
+				SUM(r.payment_amount) AS collected_mtd
+			FROM repayments r
+			INNER JOIN loans cl ON r.loan_id = cl.loan_id
+			WHERE ` + reversalFilterSQL(false) + `
+				AND r.payment_date::date BETWEEN DATE_TRUNC('month', CURRENT_DATE)::date AND CURRENT_DATE
+			GROUP BY cl.officer_id
+		) c ON c.officer_id = o.officer_id
+		WHERE ` + r.userTypeFilter() + `
+		GROUP BY o.officer_id
+		ON CONFLICT (officer_id, snapshot_date) DO UPDATE SET
+			active_loans = EXCLUDED.active_loans,
+			portfolio_total = EXCLUDED.portfolio_total,
+			total_outstanding = EXCLUDED.total_outstanding,
+			principal_outstanding = EXCLUDED.principal_outstanding,
+			overdue_15d = EXCLUDED.overdue_15d,
+			par15_ratio = EXCLUDED.par15_ratio,
+			loans_in_arrears = EXCLUDED.loans_in_arrears,
+			due_today = EXCLUDED.due_today,
+			collected_today = EXCLUDED.collected_today,
+			collected_mtd = EXCLUDED.collected_mtd,
+			captured_at = CURRENT_TIMESTAMP
+	`
+
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to capture officer snapshots: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetOfficerMetricsAsOf returns the snapshot of an officer's figures captured
+// on date (YYYY-MM-DD). Returns ErrNotFound if no snapshot exists for that
+// officer and date.
+func (r *DashboardRepository) GetOfficerMetricsAsOf(officerID, date string) (*models.OfficerSnapshot, error) {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidFilter)
+	}
+
+	query := `
+		SELECT
+			s.officer_id,
+			COALESCE(o.officer_name, '') AS officer_name,
+			TO_CHAR(s.snapshot_date, 'YYYY-MM-DD'),
+			s.active_loans,
+			s.portfolio_total,
+			s.total_outstanding,
+			s.principal_outstanding,
+			s.overdue_15d,
+			s.par15_ratio,
+			s.loans_in_arrears,
+			s.due_today,
+			s.collected_today,
+			s.collected_mtd,
+			s.captured_at
+		FROM officer_snapshots s
+		LEFT JOIN officers o ON o.officer_id = s.officer_id
+		WHERE s.officer_id = $1
+			AND s.snapshot_date = $2
+	`
+
+	snapshot := &models.OfficerSnapshot{}
+	err = r.db.QueryRow(query, officerID, parsed.Format("2006-01-02")).Scan(
+		&snapshot.OfficerID,
+		&snapshot.OfficerName,
+		&snapshot.SnapshotDate,
+		&snapshot.ActiveLoans,
+		&snapshot.PortfolioTotal,
+		&snapshot.TotalOutstanding,
+		&snapshot.PrincipalOutstanding,
+		&snapshot.Overdue15d,
+		&snapshot.PAR15Ratio,
+		&snapshot.LoansInArrears,
+		&snapshot.DueToday,
+		&snapshot.CollectedToday,
+		&snapshot.CollectedMTD,
+		&snapshot.CapturedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: no snapshot for officer %s on %s", ErrNotFound, officerID, date)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve officer snapshot: %w", err)
+	}
+
+	snapshot.PAR15Ratio = r.roundRatio(snapshot.PAR15Ratio)
+	return snapshot, nil
+}
+
+// concentrationEntities maps the concentration "by" values onto the SQL
+// identifying and naming each entity.
+var concentrationEntities = map[string]struct{ id, name string }{
+	"officer": {id: "l.officer_id", name: "COALESCE(MAX(o.officer_name), l.officer_id)"},
+	"branch":  {id: "l.branch", name: "l.branch"},
+}
+
+// GetPortfolioConcentration ranks officers or branches by actual_outstanding
+// across the filtered portfolio and returns the top N, their combined share
+// and the Herfindahl-Hirschman index (sum of squared shares) over all
+// entities.
+func (r *DashboardRepository) GetPortfolioConcentration(filters map[string]interface{}, by string, top int) (*models.PortfolioConcentration, error) {
+	entity, ok := concentrationEntities[by]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported by %q (use officer or branch)", ErrInvalidFilter, by)
+	}
+	if top < 1 {
+		return nil, fmt.Errorf("%w: top must be positive", ErrInvalidFilter)
+	}
+
+	loanFilters, args, argCount := buildLoanFilters(filters, 1)
+	args = append(args, top)
+
+	query := fmt.Sprintf(`
+		WITH per_entity AS (
+			SELECT
+				%[1]s AS entity_id,
+				%[2]s AS entity_name,
+				SUM(l.actual_outstanding) AS outstanding
+			FROM loans l
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE %[3]s
+				AND l.actual_outstanding > 0
+				AND %[1]s IS NOT NULL
+				%[4]s
+			GROUP BY %[1]s
+		),
+		ranked AS (
+			SELECT
+				entity_id,
+				entity_name,
+				outstanding,
+				ROW_NUMBER() OVER (ORDER BY outstanding DESC, entity_id) AS rank,
+				SUM(outstanding) OVER () AS total,
+				COUNT(*) OVER () AS entity_count,
+				SUM(outstanding * outstanding) OVER () / POWER(SUM(outstanding) OVER (), 2) AS hhi
+			FROM per_entity
+		)
+		SELECT entity_id, entity_name, outstanding, rank, total, entity_count, hhi
+		FROM ranked
+		WHERE rank <= $%[5]d
+		ORDER BY rank
+	`, entity.id, entity.name, r.userTypeFilter(), loanFilters, argCount)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve portfolio concentration: %w", err)
+	}
+	defer rows.Close()
+
+	result := &models.PortfolioConcentration{By: by, Top: top, Entities: []*models.ConcentrationEntity{}}
+	for rows.Next() {
+		e := &models.ConcentrationEntity{}
+		if err := rows.Scan(&e.ID, &e.Name, &e.Outstanding, &e.Rank, &result.TotalOutstanding, &result.EntityCount, &result.HHI); err != nil {
+			return nil, fmt.Errorf("failed to scan portfolio concentration row: %w", err)
+		}
+		result.Entities = append(result.Entities, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate portfolio concentration rows: %w", err)
+	}
+
+	for _, e := range result.Entities {
+		e.Share = safeDivide(e.Outstanding, result.TotalOutstanding)
+		result.TopShare += e.Share
+	}
+
+	return result, nil
+}
+
+// rollupDimensions whitelists the GET /metrics/rollup dimensions and maps
+// each onto the SQL grouping the loans (aliased l, officers o) and naming
+// each group. Only these fixed expressions are ever interpolated.
+var rollupDimensions = map[string]struct{ key, name string }{
+	"branch":        {key: "l.branch", name: "MAX(l.branch)"},
+	"region":        {key: "l.region", name: "MAX(l.region)"},
+	"channel":       {key: "l.channel", name: "MAX(l.channel)"},
+	"wave":          {key: "l.wave", name: "MAX(l.wave)"},
+	"vertical_lead": {key: "l.vertical_lead_email", name: "MAX(NULLIF(l.vertical_lead_name, ''))"},
+	"officer":       {key: "l.officer_id", name: "MAX(o.officer_name)"},
+}
+
+// RollupDimensions lists the dimensions GetMetricsRollup accepts.
+var RollupDimensions = []string{"branch", "region", "channel", "wave", "vertical_lead", "officer"}
+
+// GetMetricsRollup groups the filtered loans by dimension and returns each
+// group's active loans, portfolio total, principal outstanding, overdue 15+
+// days, PAR15 and due today, all over the same open (django_status) loans,
+// plus today's collections on any of the group's loans. Loans with no value
+// for the dimension are grouped under MissingValueSentinel. Rows are ordered
+// by portfolio total descending, then key.
+func (r *DashboardRepository) GetMetricsRollup(filters map[string]interface{}, dimension string) (*models.MetricsRollup, error) {
+	dim, ok := rollupDimensions[dimension]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported dimension %q (use one of %s)", ErrInvalidFilter, dimension, strings.Join(RollupDimensions, ", "))
+	}
+
+	loanFilters, args, argCount := buildLoanFilters(filters, 1)
+	args = append(args, MissingValueSentinel)
+	groupKey := fmt.Sprintf("COALESCE(NULLIF(%s::text, ''), $%d)", dim.key, argCount)
+
+	query := fmt.Sprintf(`
+		WITH per_loan AS (
+			SELECT
+				%[1]s AS group_key,
+				%[2]s AS group_name,
+				COUNT(*) AS active_loans,
+				COALESCE(SUM(l.repayment_amount), 0) AS portfolio_total,
+				COALESCE(SUM(l.principal_outstanding), 0) AS principal_outstanding,
+				COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) AS overdue_15d,
+				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN %[4]s ELSE 0 END), 0) AS due_today
+			FROM loans l
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE %[5]s
+				AND %[3]s
+				%[6]s
+			GROUP BY 1
+		),
+		per_repayment AS (
+			SELECT
+				%[1]s AS group_key,
+				COALESCE(SUM(r.payment_amount), 0) AS collected_today
+			FROM repayments r
+			INNER JOIN loans l ON r.loan_id = l.loan_id
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE %[5]s
+				AND `+reversalFilterSQL(false)+`
+				AND r.payment_date::date = CURRENT_DATE
+				%[6]s
+			GROUP BY 1
+		)
+		SELECT
+			pl.group_key,
+			COALESCE(pl.group_name, pl.group_key),
+			pl.active_loans,
+			pl.portfolio_total::float,
+			pl.principal_outstanding::float,
+			pl.overdue_15d::float,
+			pl.due_today::float,
+			COALESCE(pr.collected_today, 0)::float
+		FROM per_loan pl
+		LEFT JOIN per_repayment pr ON pr.group_key = pl.group_key
+		ORDER BY pl.portfolio_total DESC, pl.group_key
+	`, groupKey, dim.name, r.openStatusFilter(), r.dailyRepaymentSQL(), r.userTypeFilter(), loanFilters)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s rollup: %w", dimension, err)
+	}
+	defer rows.Close()
+
+	result := &models.MetricsRollup{Dimension: dimension, Rows: []*models.MetricsRollupRow{}}
+	for rows.Next() {
+		row := &models.MetricsRollupRow{}
+		if err := rows.Scan(
+			&row.Key,
+			&row.Name,
+			&row.ActiveLoans,
+			&row.PortfolioTotal,
+			&row.PrincipalOutstanding,
+			&row.Overdue15d,
+			&row.DueToday,
+			&row.CollectedToday,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan %s rollup row: %w", dimension, err)
+		}
+
+		row.PAR15Ratio = r.roundRatio(safeDivide(row.Overdue15d, row.PrincipalOutstanding))
+		if row.DueToday > 0 {
+			rate := r.roundRatio(row.CollectedToday / row.DueToday)
+			row.CollectionRateToday = &rate
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate %s rollup rows: %w", dimension, err)
+	}
+
+	return result, nil
+}
+
+// loanTermBuckets turns the configured loan term bucket edges into
+// mutually exclusive buckets: up to the first edge, between consecutive
+// edges, and above the last edge. Edges are sorted and non-positive or
+// duplicate edges dropped.
+func loanTermBuckets(edges []int) []*models.LoanTermBucket {
+	sorted := []int{}
+	for _, edge := range edges {
+		if edge > 0 {
+			sorted = append(sorted, edge)
+		}
+	}
+	sort.Ints(sorted)
+
+	buckets := []*models.LoanTermBucket{}
+	lower := 0
+	for _, edge := range sorted {
+		if edge < lower {
+			continue // duplicate edge
+		}
+		minDays, maxDays := lower, edge
+		label := fmt.Sprintf("%d-%dd", minDays, maxDays)
+		if minDays == 0 {
+			label = fmt.Sprintf("<=%dd", maxDays)
+		}
+		buckets = append(buckets, &models.LoanTermBucket{Bucket: label, MinDays: &minDays, MaxDays: &maxDays})
+		lower = edge + 1
+	}
+	minDays := lower
+	buckets = append(buckets, &models.LoanTermBucket{Bucket: fmt.Sprintf("%dd+", minDays), MinDays: &minDays})
+	return buckets
+}
+
+// GetLoanTermDistribution groups the filtered loans into the configured
+// loan_term_days buckets (LoanTermBucketEdges) and returns each bucket's loan
+// count, principal outstanding, overdue 15+ days and PAR15. Every bucket is
+// returned, zero-filled when empty, in tenor order; loans with no term, or a
+// term of zero or less, are reported last under MissingValueSentinel when
+// there are any. Only open loans are counted unless include_closed is set.
+func (r *DashboardRepository) GetLoanTermDistribution(filters map[string]interface{}) ([]*models.LoanTermBucket, error) {
+	buckets := loanTermBuckets(r.cfg.LoanTermBucketEdges)
+
+	// Bucket index per loan; the edges are config integers, not user input.
+	bucketCase := "CASE WHEN l.loan_term_days IS NULL OR l.loan_term_days <= 0 THEN -1"
+	for i, b := range buckets {
+		if b.MaxDays != nil {
+			bucketCase += fmt.Sprintf(" WHEN l.loan_term_days <= %d THEN %d", *b.MaxDays, i)
+		}
+	}
+	bucketCase += fmt.Sprintf(" ELSE %d END", len(buckets)-1)
+
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+	if includeClosed, ok := filters["include_closed"].(bool); !ok || !includeClosed {
+		loanFilters = " AND " + r.openStatusFilter() + loanFilters
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS bucket,
+			COUNT(*) AS loans_count,
+			COALESCE(SUM(l.principal_outstanding), 0) AS principal_outstanding,
+			COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) AS overdue_15d,
+			COUNT(*) FILTER (WHERE l.current_dpd >= 15) AS overdue_15d_count
+		FROM loans l
+		INNER JOIN officers o ON l.officer_id = o.officer_id
+		WHERE %s
+			%s
+		GROUP BY 1
+	`, bucketCase, r.userTypeFilter(), loanFilters)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve loan term distribution: %w", err)
+	}
+	defer rows.Close()
+
+	var missing *models.LoanTermBucket
+	for rows.Next() {
+		var index int
+		row := &models.LoanTermBucket{}
+		if err := rows.Scan(&index, &row.LoansCount, &row.PrincipalOutstanding, &row.Overdue15d, &row.Overdue15dCount); err != nil {
+			return nil, fmt.Errorf("failed to scan loan term distribution row: %w", err)
+		}
+		if index < 0 {
+			row.Bucket = MissingValueSentinel
+			missing = row
+			continue
+		}
+		b := buckets[index]
+		b.LoansCount, b.PrincipalOutstanding, b.Overdue15d, b.Overdue15dCount =
+			row.LoansCount, row.PrincipalOutstanding, row.Overdue15d, row.Overdue15dCount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate loan term distribution rows: %w", err)
+	}
+	if missing != nil {
+		buckets = append(buckets, missing)
+	}
+
+	for _, b := range buckets {
+		overdue, _ := b.Overdue15d.Decimal.Float64()
+		principal, _ := b.PrincipalOutstanding.Decimal.Float64()
+		b.PAR15Ratio = r.roundRatio(safeDivide(overdue, principal))
+	}
+
+	return buckets, nil
+}
+
+// GetRepaymentDelayHistogram counts the filtered loans per repayment_delay_rate
+// bin, splitting 0-100 into bins equal-width bins. Every bin is returned, even
+// when empty. A rate of exactly 100 falls in the last bin.
+func (r *DashboardRepository) GetRepaymentDelayHistogram(filters map[string]interface{}, bins int) (*models.RepaymentDelayHistogram, error) {
+	if bins < 1 {
+		return nil, fmt.Errorf("%w: bins must be positive", ErrInvalidFilter)
+	}
+
+	loanFilters, filterArgs, _ := buildLoanFilters(filters, 2)
+	args := append([]interface{}{bins}, filterArgs...)
+
+	// width_bucket returns 0 below the range and bins+1 at or above 100
+	query := fmt.Sprintf(`
+		SELECT
+			CASE WHEN l.repayment_delay_rate = 100 THEN $1::int
+				ELSE width_bucket(l.repayment_delay_rate, 0, 100, $1::int)
+			END AS bin,
+			COUNT(*) AS loans_count
+		FROM loans l
+		INNER JOIN officers o ON l.officer_id = o.officer_id
+		WHERE %s
+			AND l.repayment_delay_rate IS NOT NULL
+			%s
+		GROUP BY 1
+	`, r.userTypeFilter(), loanFilters)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve repayment delay histogram: %w", err)
+	}
+	defer rows.Close()
+
+	histogram := &models.RepaymentDelayHistogram{Bins: make([]*models.DelayRateBin, bins)}
+	for i := range histogram.Bins {
+		minRate := math.Round(float64(i)*10000/float64(bins)) / 100
+		maxRate := math.Round(float64(i+1)*10000/float64(bins)) / 100
+		histogram.Bins[i] = &models.DelayRateBin{
+			Bin:     fmt.Sprintf("%g-%g", minRate, maxRate),
+			MinRate: minRate,
+			MaxRate: maxRate,
+		}
+	}
+
+	for rows.Next() {
+		var bin, count int
+		if err := rows.Scan(&bin, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan repayment delay histogram row: %w", err)
+		}
+		histogram.TotalLoans += count
+		switch {
+		case bin < 1:
+			histogram.BelowRangeCount += count
+		case bin > bins:
+			histogram.AboveRangeCount += count
+		default:
+			histogram.Bins[bin-1].LoansCount = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate repayment delay histogram rows: %w", err)
+	}
+
+	return histogram, nil
+}
+
+// GetPeriodAggregates returns collections, disbursements and the PAR15 of the
+// period's disbursement cohort for loans matching filters.
+func (r *DashboardRepository) GetPeriodAggregates(filters map[string]interface{}, period string) (*models.PeriodAggregates, error) {
+	periodStart, periodEnd, err := resolvePeriodRange(period)
+	if err != nil {
+		return nil, err
+	}
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+
+	query := fmt.Sprintf(`
+		WITH scoped AS (
+			SELECT l.loan_id, l.loan_amount, l.disbursement_date, l.current_dpd, l.actual_outstanding
+			FROM loans l
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE %[3]s
+				%[4]s
+		),
+		collected AS (
+			SELECT COALESCE(SUM(r.payment_amount), 0) AS amount, COUNT(*) AS repayments
+			FROM repayments r
+			INNER JOIN scoped s ON s.loan_id = r.loan_id
+			WHERE `+reversalFilterSQL(false)+`
+				AND %[5]s
+		),
+		disbursed AS (
+			SELECT
+				COALESCE(SUM(s.loan_amount), 0) AS amount,
+				COUNT(*) AS loans,
+				COALESCE(SUM(CASE WHEN s.current_dpd >= 15 THEN s.actual_outstanding END)
+					/ NULLIF(SUM(s.actual_outstanding), 0), 0) AS par15_ratio
+			FROM scoped s
+			WHERE %[6]s
+		)
+		SELECT
+			TO_CHAR(%[1]s, 'YYYY-MM-DD'),
+			TO_CHAR(%[2]s, 'YYYY-MM-DD'),
+			c.amount, c.repayments,
+			d.amount, d.loans, d.par15_ratio
+		FROM collected c, disbursed d
+	`, periodStart, periodEnd, r.userTypeFilter(), loanFilters,
+		periodDateFilter("DATE(r.payment_date)", periodStart, periodEnd),
+		periodDateFilter("s.disbursement_date::date", periodStart, periodEnd))
+
+	agg := &models.PeriodAggregates{Period: strings.ToLower(strings.TrimSpace(period))}
+	err = r.db.QueryRow(query, args...).Scan(
+		&agg.PeriodStart,
+		&agg.PeriodEnd,
+		&agg.CollectedAmount,
+		&agg.RepaymentsCount,
+		&agg.DisbursedAmount,
+		&agg.DisbursedCount,
+		&agg.PAR15Ratio,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve aggregates for period %s: %w", period, err)
+	}
+
+	return agg, nil
+}
+
+// GetOfficerRepaymentMethods returns how an officer's collections for the given
+// period split across normalised payment methods. Every method is present in
+// the result (zero-filled) so callers can render a stable breakdown.
+func (r *DashboardRepository) GetOfficerRepaymentMethods(officerID string, period string) ([]*models.OfficerCollectionMethod, error) {
+	periodStart, periodEnd := collectionsPeriodRange(strings.ToLower(strings.TrimSpace(period)))
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS method,
+			COALESCE(SUM(r.payment_amount), 0) AS amount,
+			COUNT(*) AS repayments_count
+		FROM repayments r
+		INNER JOIN loans l ON r.loan_id = l.loan_id
+		WHERE `+reversalFilterSQL(false)+`
+			AND l.officer_id = $1
+			AND %s
+		GROUP BY 1
+	`, normalizedPaymentMethodSQL, periodDateFilter("DATE(r.payment_date)", periodStart, periodEnd))
+
+	rows, err := r.db.Query(query, officerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byMethod := make(map[string]*models.OfficerCollectionMethod)
+	total := 0.0
+	for rows.Next() {
+		m := &models.OfficerCollectionMethod{}
+		if err := rows.Scan(&m.Method, &m.Amount, &m.RepaymentsCount); err != nil {
+			return nil, err
+		}
+		byMethod[m.Method] = m
+		total += m.Amount
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	methods := make([]*models.OfficerCollectionMethod, 0, len(collectionPaymentMethods))
+	for _, method := range collectionPaymentMethods {
+		m, ok := byMethod[method]
+		if !ok {
+			m = &models.OfficerCollectionMethod{Method: method}
+		}
+		m.SharePct = safeDivide(m.Amount, total) * 100
+		methods = append(methods, m)
+	}
+
+	return methods, nil
+}
+
+// GetCollectionsByChannel returns non-reversed collections for period (see
+// resolvePeriodRange) grouped by the loan's origination channel, largest
+// first. Loans without a channel are grouped under MissingValueSentinel.
+func (r *DashboardRepository) GetCollectionsByChannel(filters map[string]interface{}, period string) ([]*models.ChannelCollection, error) {
+	start, end, err := resolvePeriodRange(period)
+	if err != nil {
+		return nil, err
+	}
+
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+
+	query := fmt.Sprintf(`
+		SELECT
+			COALESCE(NULLIF(l.channel, ''), '%s') AS channel,
+			COALESCE(SUM(r.payment_amount), 0) AS amount,
+			COUNT(*) AS repayments_count,
+			COUNT(DISTINCT r.loan_id) AS loans_count
+		FROM repayments r
+		INNER JOIN loans l ON r.loan_id = l.loan_id
+		INNER JOIN officers o ON l.officer_id = o.officer_id
+		WHERE `+reversalFilterSQL(false)+`
+			AND %s
+			AND %s
+			%s
+		GROUP BY 1
+		ORDER BY amount DESC, channel
+	`, MissingValueSentinel, periodDateFilter("DATE(r.payment_date)", start, end), r.userTypeFilter(), loanFilters)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve collections by channel: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []*models.ChannelCollection{}
+	total := decimal.Zero
+	for rows.Next() {
+		ch := &models.ChannelCollection{}
+		if err := rows.Scan(&ch.Channel, &ch.Amount, &ch.RepaymentsCount, &ch.LoansCount); err != nil {
+			return nil, fmt.Errorf("failed to scan collections by channel row: %w", err)
+		}
+		channels = append(channels, ch)
+		total = total.Add(ch.Amount.Decimal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating collections by channel rows: %w", err)
+	}
+
+	if total.IsPositive() {
+		for _, ch := range channels {
+			share, _ := ch.Amount.Decimal.Div(total).Mul(decimal.NewFromInt(100)).Float64()
+			ch.SharePct = r.roundPercent(share)
+		}
+	}
+
+	return channels, nil
+}
+
+// GetCollectionsByUserType returns portfolio, due today, collected today,
+// collection rate and the PAR15 NPL proxy grouped by the booking officer's
+// user_type. Officers are restricted by the standard user_type filter, so
+// only dashboard user types (and untyped officers when IncludeNullUserType
+// is set) appear; officers without a user_type are grouped under
+// MissingValueSentinel. Metrics follow GetBranchCollectionsLeaderboard.
+func (r *DashboardRepository) GetCollectionsByUserType(filters map[string]interface{}) ([]*models.UserTypeCollections, error) {
+	loanFilters, args, _ := buildLoanFilters(filters, 1)
+	userType := fmt.Sprintf("COALESCE(NULLIF(o.user_type, ''), '%s')", MissingValueSentinel)
+
+	query := fmt.Sprintf(`
+		WITH loan_totals AS (
+			SELECT
+				%[1]s AS user_type,
+				COUNT(*) AS loans_count,
+				COALESCE(SUM(l.repayment_amount), 0) AS portfolio_total,
+				COALESCE(SUM(CASE WHEN l.current_dpd >= 15 THEN l.principal_outstanding ELSE 0 END), 0) AS overdue_15d,
+				COALESCE(SUM(CASE WHEN l.actual_outstanding > 0 THEN %[2]s ELSE 0 END), 0) AS due_today
+			FROM loans l
+			JOIN officers o ON l.officer_id = o.officer_id
+			WHERE %[3]s
+				%[4]s
+			GROUP BY 1
+		),
+		collected AS (
+			SELECT
+				%[1]s AS user_type,
+				COALESCE(SUM(r.payment_amount), 0) AS collected_today
+			FROM repayments r
+			JOIN loans l ON r.loan_id = l.loan_id
+			JOIN officers o ON l.officer_id = o.officer_id
+			WHERE `+reversalFilterSQL(false)+`
+				AND r.payment_date::date = CURRENT_DATE
+				AND %[3]s
+				%[4]s
+			GROUP BY 1
+		)
+		SELECT
+			COALESCE(lt.user_type, c.user_type) AS user_type,
+			COALESCE(lt.loans_count, 0),
+			COALESCE(lt.portfolio_total, 0),
+			COALESCE(lt.overdue_15d, 0),
+			COALESCE(lt.due_today, 0),
+			COALESCE(c.collected_today, 0) AS collected_today
+		FROM loan_totals lt
+		FULL OUTER JOIN collected c ON lt.user_type = c.user_type
+		ORDER BY collected_today DESC, user_type
+	`, userType, r.dailyRepaymentSQL(), r.userTypeFilter(), loanFilters)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve collections by user type: %w", err)
 	}
 	defer rows.Close()
 
-	result := []*models.RepaymentWatchOfficerRow{}
+	results := []*models.UserTypeCollections{}
 	for rows.Next() {
-		row := &models.RepaymentWatchOfficerRow{}
+		row := &models.UserTypeCollections{}
 		if err := rows.Scan(
-			&row.OfficerID,
-			&row.OfficerName,
-			&row.OfficerEmail,
-			&row.Branch,
-			&row.Region,
-			&row.TotalWave2OpenLoans,
-			&row.LoansWithRepaymentToday,
-			&row.AmountCollectedToday,
+			&row.UserType,
+			&row.LoansCount,
+			&row.PortfolioTotal,
+			&row.Overdue15d,
+			&row.DueToday,
+			&row.CollectedToday,
 		); err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to scan collections by user type row: %w", err)
 		}
 
-		if row.TotalWave2OpenLoans > 0 && row.LoansWithRepaymentToday > 0 {
-			row.RepaymentRate = (float64(row.LoansWithRepaymentToday) / float64(row.TotalWave2OpenLoans)) * 100
-		} else {
-			row.RepaymentRate = 0
+		collected, _ := row.CollectedToday.Decimal.Float64()
+		due, _ := row.DueToday.Decimal.Float64()
+		switch {
+		case due > 0:
+			row.TodayRate = r.roundRatio(safeDivide(collected, due))
+		case collected > 0:
+			// No explicit due but collections recorded; treat as fully collected.
+			row.TodayRate = 1
 		}
 
-		result = append(result, row)
-	}
-
-	return result, nil
-}
+		overdue, _ := row.Overdue15d.Decimal.Float64()
+		portfolio, _ := row.PortfolioTotal.Decimal.Float64()
+		row.NPLRatio = r.roundRatio(safeDivide(overdue, portfolio))
 
-// GetFilterOptions retrieves filter dropdown options
-func (r *DashboardRepository) GetFilterOptions(filterType string, filters map[string]interface{}) (interface{}, error) {
-	switch filterType {
-	case "branches":
-		return r.getBranches(filters)
-	case "regions":
-		return r.getRegions()
-	case "waves":
-		return r.getWaves()
-	case "channels":
-		return r.getChannels()
-	case "user-types":
-		return r.getUserTypes()
-	case "officers":
-		return r.getOfficerOptions(filters)
-	case "statuses":
-		return r.getStatuses()
-	case "loan-types":
-		return r.getLoanTypes()
-	case "verification-statuses":
-		return r.getVerificationStatuses()
-	case "django-statuses":
-		return r.getDjangoStatuses()
-	case "vertical-leads":
-		return r.getVerticalLeads()
-	default:
-		return nil, fmt.Errorf("unknown filter type: %s", filterType)
+		results = append(results, row)
 	}
-}
-
-func (r *DashboardRepository) getBranches(filters map[string]interface{}) ([]string, error) {
-	query := `SELECT DISTINCT l.branch FROM loans l
-		INNER JOIN officers o ON l.officer_id = o.officer_id
-		WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)`
-	args := []interface{}{}
-	argCount := 1
-
-	if region, ok := filters["region"].(string); ok && region != "" {
-		// Support comma-separated regions for multi-select
-		regions := strings.Split(region, ",")
-		if len(regions) == 1 {
-			query += fmt.Sprintf(" AND l.region = $%d", argCount)
-			args = append(args, regions[0])
-			argCount++
-		} else {
-			// Build IN clause for multiple regions
-			placeholders := []string{}
-			for _, r := range regions {
-				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
-				args = append(args, strings.TrimSpace(r))
-				argCount++
-			}
-			query += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
-		}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating collections by user type rows: %w", err)
 	}
 
-	query += " ORDER BY l.branch"
+	return results, nil
+}
+
+// GetLoanCollectionSummary returns a loan's non-reversed collections to date
+// split across normalised payment methods (see normalizedPaymentMethodSQL).
+// Every method is present in the result, zero-filled. Returns ErrNotFound if
+// the loan doesn't exist.
+func (r *DashboardRepository) GetLoanCollectionSummary(loanID string) (*models.LoanCollectionSummary, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS method,
+			COALESCE(SUM(r.payment_amount), 0) AS amount,
+			COUNT(r.repayment_id) AS repayments_count,
+			TO_CHAR(MIN(r.payment_date), 'YYYY-MM-DD') AS first_payment_date,
+			TO_CHAR(MAX(r.payment_date), 'YYYY-MM-DD') AS last_payment_date
+		FROM loans l
+		LEFT JOIN repayments r ON r.loan_id = l.loan_id AND `+reversalFilterSQL(false)+`
+		WHERE l.loan_id = $1
+		GROUP BY 1
+	`, normalizedPaymentMethodSQL)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, loanID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to query loan collection summary: %w", err)
 	}
 	defer rows.Close()
 
-	branches := []string{}
+	summary := &models.LoanCollectionSummary{LoanID: loanID}
+	byMethod := make(map[string]*models.LoanCollectionMethod)
+	found := false
+	total := decimal.Zero
 	for rows.Next() {
-		var branch string
-		if err := rows.Scan(&branch); err != nil {
-			return nil, err
+		found = true
+		m := &models.LoanCollectionMethod{}
+		var firstPaymentDate sql.NullString
+		var lastPaymentDate sql.NullString
+		if err := rows.Scan(&m.Method, &m.Amount, &m.RepaymentsCount, &firstPaymentDate, &lastPaymentDate); err != nil {
+			return nil, fmt.Errorf("failed to scan loan collection summary row: %w", err)
 		}
-		branches = append(branches, branch)
+		if m.RepaymentsCount == 0 {
+			// The LEFT JOIN row of a loan without repayments.
+			continue
+		}
+		if lastPaymentDate.Valid {
+			m.LastPaymentDate = &lastPaymentDate.String
+			if summary.LastPaymentDate == nil || lastPaymentDate.String > *summary.LastPaymentDate {
+				summary.LastPaymentDate = &lastPaymentDate.String
+			}
+		}
+		if firstPaymentDate.Valid && (summary.FirstPaymentDate == nil || firstPaymentDate.String < *summary.FirstPaymentDate) {
+			summary.FirstPaymentDate = &firstPaymentDate.String
+		}
+		byMethod[m.Method] = m
+		total = total.Add(m.Amount.Decimal)
+		summary.RepaymentsCount += m.RepaymentsCount
 	}
-
-	return branches, nil
-}
-
-// GetDailyCollections returns a per-day time series of collections amounts for the
-// Collections Control Centre daily chart. It aggregates repayments by payment_date
-// and applies the same officer and loan filters as other collections metrics.
-func (r *DashboardRepository) GetDailyCollections(filters map[string]interface{}) ([]*models.DailyCollectionsPoint, error) {
-	// Determine requested period, defaulting to "today".
-	period := "today"
-	if p, ok := filters["period"].(string); ok && strings.TrimSpace(p) != "" {
-		period = strings.ToLower(strings.TrimSpace(p))
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loan collection summary rows: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: loan %s", ErrNotFound, loanID)
 	}
 
-	query := `
-			SELECT
-				DATE(r.payment_date) AS payment_date,
-				COALESCE(SUM(r.payment_amount), 0) AS collected_amount,
-				COUNT(*) AS repayments_count,
-				-- Repayment type breakdown (normalised using UPPER(TRIM(payment_method)))
-				COALESCE(SUM(CASE WHEN UPPER(TRIM(r.payment_method)) = 'AGENT_DEBIT' THEN r.payment_amount END), 0) AS agent_debit_amount,
-				COALESCE(SUM(CASE WHEN UPPER(TRIM(r.payment_method)) = 'TRANSFER' THEN r.payment_amount END), 0) AS transfer_amount,
-				COALESCE(SUM(CASE WHEN UPPER(TRIM(r.payment_method)) = 'ESCROW_DEBIT' THEN r.payment_amount END), 0) AS escrow_debit_amount,
-				COALESCE(SUM(CASE
-					WHEN UPPER(TRIM(r.payment_method)) NOT IN ('AGENT_DEBIT', 'TRANSFER', 'ESCROW_DEBIT')
-						OR r.payment_method IS NULL
-						OR TRIM(r.payment_method) = ''
-					THEN r.payment_amount
-				END), 0) AS other_repayments_amount
-			FROM repayments r
-			INNER JOIN loans l ON r.loan_id = l.loan_id
-			INNER JOIN officers o ON l.officer_id = o.officer_id
-			WHERE r.is_reversed = false
-				AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
-	`
-
-	// Apply period restriction on repayment dates.
-	switch period {
-	case "this_week":
-		query += `
-				AND DATE(r.payment_date) >= DATE_TRUNC('week', CURRENT_DATE)::date
-				AND DATE(r.payment_date) <= CURRENT_DATE
-			`
-	case "this_month":
-		query += `
-				AND DATE(r.payment_date) >= DATE_TRUNC('month', CURRENT_DATE)::date
-				AND DATE(r.payment_date) <= CURRENT_DATE
-			`
-	case "last_month":
-		query += `
-				AND DATE(r.payment_date) >= (DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '1 month')::date
-				AND DATE(r.payment_date) < DATE_TRUNC('month', CURRENT_DATE)::date
-			`
-	case "last_7_days":
-		// Custom period for the Collections Control Centre daily chart:
-		// always show the last 7 calendar days (including today).
-		query += `
-				AND DATE(r.payment_date) >= (CURRENT_DATE - INTERVAL '6 days')
-				AND DATE(r.payment_date) <= CURRENT_DATE
-			`
-	default: // "today" or any unrecognised value
-		query += `
-				AND DATE(r.payment_date) = CURRENT_DATE
-			`
+	summary.TotalCollected = models.NewMoney(total)
+	summary.Methods = make([]*models.LoanCollectionMethod, 0, len(collectionPaymentMethods))
+	for _, method := range collectionPaymentMethods {
+		m, ok := byMethod[method]
+		if !ok {
+			m = &models.LoanCollectionMethod{Method: method}
+		}
+		if total.IsPositive() {
+			share, _ := m.Amount.Decimal.Div(total).Mul(decimal.NewFromInt(100)).Float64()
+			m.SharePct = r.roundPercent(share)
+		}
+		summary.Methods = append(summary.Methods, m)
 	}
 
+	return summary, nil
+}
+
+// buildLoanFilters builds the standard loan filter conditions (officer, branch,
+// region, channel, user type, status, django_status, performance_status, wave,
+// customer phone, vertical lead, supervisor, loan type, verification status and
+// DPD range)
+// as a string of " AND ..." clauses over loans aliased l and officers aliased o.
+// Placeholders are numbered from argCount; the bound args and the next free
+// placeholder number are returned.
+func buildLoanFilters(filters map[string]interface{}, argCount int) (string, []interface{}, int) {
 	args := []interface{}{}
-	argCount := 1
+	loanFilters := ""
 
-	// Apply the same filters as other collections repayments aggregations so that
-	// the chart stays aligned with the KPI cards.
 	if officerID, ok := filters["officer_id"].(string); ok && officerID != "" {
-		query += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
+		loanFilters += fmt.Sprintf(" AND l.officer_id = $%d", argCount)
 		args = append(args, officerID)
 		argCount++
 	}
 
 	if branch, ok := filters["branch"].(string); ok && branch != "" {
-		query += fmt.Sprintf(" AND l.branch = $%d", argCount)
+		loanFilters += fmt.Sprintf(" AND l.branch = $%d", argCount)
 		args = append(args, branch)
 		argCount++
 	}
@@ -4582,7 +6743,7 @@ func (r *DashboardRepository) GetDailyCollections(filters map[string]interface{}
 	if region, ok := filters["region"].(string); ok && region != "" {
 		regions := strings.Split(region, ",")
 		if len(regions) == 1 {
-			query += fmt.Sprintf(" AND l.region = $%d", argCount)
+			loanFilters += fmt.Sprintf(" AND l.region = $%d", argCount)
 			args = append(args, regions[0])
 			argCount++
 		} else {
@@ -4592,204 +6753,170 @@ func (r *DashboardRepository) GetDailyCollections(filters map[string]interface{}
 				args = append(args, strings.TrimSpace(rgn))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
+			loanFilters += fmt.Sprintf(" AND l.region IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	if channel, ok := filters["channel"].(string); ok && channel != "" {
-		query += fmt.Sprintf(" AND l.channel = $%d", argCount)
+		loanFilters += fmt.Sprintf(" AND l.channel = $%d", argCount)
 		args = append(args, channel)
 		argCount++
 	}
 
+	if userType, ok := filters["user_type"].(string); ok && userType != "" {
+		loanFilters += fmt.Sprintf(" AND o.user_type = $%d", argCount)
+		args = append(args, userType)
+		argCount++
+	}
+
 	if status, ok := filters["status"].(string); ok && status != "" {
 		statuses := strings.Split(status, ",")
 		if len(statuses) == 1 {
-			query += fmt.Sprintf(" AND l.status = $%d", argCount)
+			loanFilters += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", argCount)
 			args = append(args, statuses[0])
 			argCount++
 		} else {
 			placeholders := []string{}
 			for _, s := range statuses {
 				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
-				args = append(args, strings.TrimSpace(s))
+				args = append(args, strings.ToUpper(strings.TrimSpace(s)))
 				argCount++
 			}
-			query += fmt.Sprintf(" AND l.status IN (%s)", strings.Join(placeholders, ", "))
+			loanFilters += fmt.Sprintf(" AND UPPER(l.status) IN (%s)", strings.Join(placeholders, ", "))
 		}
 	}
 
 	// Django status filter with MissingValueSentinel support.
 	if djangoStatus, ok := filters["django_status"].(string); ok && djangoStatus != "" {
-		statuses := strings.Split(djangoStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, s := range statuses {
-			value := strings.TrimSpace(s)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
-
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.django_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, s := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, s)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.django_status IN (%s)", strings.Join(placeholders, ",")))
-		}
-
-		if includeMissing {
-			conditions = append(conditions, "(l.django_status IS NULL OR l.django_status = '')")
-		}
-
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+		if cond, condArgs := inListFilter("l.django_status", djangoStatus, &argCount); cond != "" {
+			loanFilters += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if performanceStatus, ok := filters["performance_status"].(string); ok && performanceStatus != "" {
-		performanceStatuses := strings.Split(performanceStatus, ",")
-		if len(performanceStatuses) == 1 {
-			query += fmt.Sprintf(" AND l.performance_status = $%d", argCount)
-			args = append(args, performanceStatuses[0])
-			argCount++
-		} else {
-			placeholders := []string{}
-			for _, ps := range performanceStatuses {
-				placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
-				args = append(args, strings.TrimSpace(ps))
-				argCount++
-			}
-			query += fmt.Sprintf(" AND l.performance_status IN (%s)", strings.Join(placeholders, ", "))
+		if cond, condArgs := inListFilter("l.performance_status", performanceStatus, &argCount); cond != "" {
+			loanFilters += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
 	if wave, ok := filters["wave"].(string); ok && wave != "" {
-		query += fmt.Sprintf(" AND l.wave = $%d", argCount)
+		loanFilters += fmt.Sprintf(" AND l.wave = normalize_wave($%d)", argCount)
 		args = append(args, wave)
 		argCount++
 	}
 
 	if customerPhone, ok := filters["customer_phone"].(string); ok && customerPhone != "" {
-		query += fmt.Sprintf(" AND l.customer_phone LIKE $%d", argCount)
+		loanFilters += fmt.Sprintf(" AND l.customer_phone LIKE $%d", argCount)
 		args = append(args, "%"+customerPhone+"%")
 		argCount++
 	}
 
 	if verticalLeadEmail, ok := filters["vertical_lead_email"].(string); ok && verticalLeadEmail != "" {
-		query += fmt.Sprintf(" AND l.vertical_lead_email = $%d", argCount)
+		loanFilters += fmt.Sprintf(" AND l.vertical_lead_email = $%d", argCount)
 		args = append(args, verticalLeadEmail)
 		argCount++
 	}
 
-	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
-		loanTypes := strings.Split(loanType, ",")
-		nonMissing := []string{}
-		includeMissing := false
-
-		for _, lt := range loanTypes {
-			value := strings.TrimSpace(lt)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
+	if supervisorEmail, ok := filters["supervisor_email"].(string); ok && supervisorEmail != "" {
+		if cond, condArgs := supervisorFilter(supervisorEmail, &argCount); cond != "" {
+			loanFilters += " AND " + cond
+			args = append(args, condArgs...)
 		}
+	}
 
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.loan_type = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, lt := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, lt)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.loan_type IN (%s)", strings.Join(placeholders, ",")))
-		}
+	if verticalLeadName, ok := filters["vertical_lead_name"].(string); ok && verticalLeadName != "" {
+		clause, nameArgs, next := verticalLeadNameFilter(verticalLeadName, argCount)
+		loanFilters += clause
+		args = append(args, nameArgs...)
+		argCount = next
+	}
 
-		if includeMissing {
-			conditions = append(conditions, "(l.loan_type IS NULL OR l.loan_type = '')")
+	if loanType, ok := filters["loan_type"].(string); ok && loanType != "" {
+		if cond, condArgs := inListFilter("l.loan_type", loanType, &argCount); cond != "" {
+			loanFilters += " AND " + cond
+			args = append(args, condArgs...)
 		}
+	}
 
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
+		if cond, condArgs := inListFilter("l.verification_status", verificationStatus, &argCount); cond != "" {
+			loanFilters += " AND " + cond
+			args = append(args, condArgs...)
 		}
 	}
 
-	if verificationStatus, ok := filters["verification_status"].(string); ok && verificationStatus != "" {
-		verificationStatuses := strings.Split(verificationStatus, ",")
-		nonMissing := []string{}
-		includeMissing := false
+	if dpdMin, ok := filters["dpd_min"].(int); ok {
+		loanFilters += fmt.Sprintf(" AND l.current_dpd >= $%d", argCount)
+		args = append(args, dpdMin)
+		argCount++
+	}
 
-		for _, vs := range verificationStatuses {
-			value := strings.TrimSpace(vs)
-			if value == "" {
-				continue
-			}
-			if value == MissingValueSentinel {
-				includeMissing = true
-			} else {
-				nonMissing = append(nonMissing, value)
-			}
-		}
+	if dpdMax, ok := filters["dpd_max"].(int); ok {
+		loanFilters += fmt.Sprintf(" AND l.current_dpd <= $%d", argCount)
+		args = append(args, dpdMax)
+		argCount++
+	}
+
+	return loanFilters, args, argCount
+}
+
+// GetDailyCollections returns a per-day time series of collections amounts for the
+// Collections Control Centre daily chart. It aggregates repayments by payment_date
+// and applies the same officer and loan filters as other collections metrics.
+// The series has one point per day of the period, in date order; days without
+// collections or dues are zero points. With the include_reversed filter the
+// collected amounts are gross of reversals and each point also reports the
+// reversed amount and count; missed amounts are always net of reversals.
+func (r *DashboardRepository) GetDailyCollections(filters map[string]interface{}) ([]*models.DailyCollectionsPoint, error) {
+	// Determine requested period, defaulting to "today".
+	period := "today"
+	if p, ok := filters["period"].(string); ok && strings.TrimSpace(p) != "" {
+		period = strings.ToLower(strings.TrimSpace(p))
+	}
+	includeReversed, _ := filters["include_reversed"].(bool)
+
+	query := `
+			SELECT
+				DATE(r.payment_date) AS payment_date,
+				COALESCE(SUM(r.payment_amount), 0) AS collected_amount,
+				COUNT(*) AS repayments_count,
+				COUNT(DISTINCT r.loan_id) AS distinct_loans_paid,
+				COUNT(DISTINCT l.customer_id) AS distinct_customers_paid,
+				-- Repayment type breakdown (see normalizedPaymentMethodSQL)
+				COALESCE(SUM(CASE WHEN ` + normalizedPaymentMethodSQL + ` = 'AGENT_DEBIT' THEN r.payment_amount END), 0) AS agent_debit_amount,
+				COALESCE(SUM(CASE WHEN ` + normalizedPaymentMethodSQL + ` = 'TRANSFER' THEN r.payment_amount END), 0) AS transfer_amount,
+				COALESCE(SUM(CASE WHEN ` + normalizedPaymentMethodSQL + ` = 'ESCROW_DEBIT' THEN r.payment_amount END), 0) AS escrow_debit_amount,
+				COALESCE(SUM(CASE WHEN ` + normalizedPaymentMethodSQL + ` = 'OTHER' THEN r.payment_amount END), 0) AS other_repayments_amount,
+				COALESCE(SUM(r.payment_amount) FILTER (WHERE ` + repaymentReversedSQL + `), 0) AS reversed_amount,
+				COUNT(*) FILTER (WHERE ` + repaymentReversedSQL + `) AS reversed_count
+			FROM repayments r
+			INNER JOIN loans l ON r.loan_id = l.loan_id
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE ` + reversalFilterSQL(includeReversed) + `
+				AND ` + r.userTypeFilter() + `
+	`
 
-		conditions := []string{}
-		if len(nonMissing) == 1 {
-			conditions = append(conditions, fmt.Sprintf("l.verification_status = $%d", argCount))
-			args = append(args, nonMissing[0])
-			argCount++
-		} else if len(nonMissing) > 1 {
-			placeholders := make([]string, len(nonMissing))
-			for i, vs := range nonMissing {
-				placeholders[i] = fmt.Sprintf("$%d", argCount)
-				args = append(args, vs)
-				argCount++
-			}
-			conditions = append(conditions, fmt.Sprintf("l.verification_status IN (%s)", strings.Join(placeholders, ",")))
-		}
+	// Resolve the requested period into an inclusive [periodStart, periodEnd]
+	// date range. The same range drives both the repayments aggregation and the
+	// expected (due) amounts so the two series line up day by day.
+	periodStart, periodEnd := collectionsPeriodRange(period)
 
-		if includeMissing {
-			conditions = append(conditions, "(l.verification_status IS NULL OR l.verification_status = '')")
-		}
+	query += " AND " + periodDateFilter("DATE(r.payment_date)", periodStart, periodEnd)
 
-		if len(conditions) > 0 {
-			query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	// Apply the same filters as other collections repayments aggregations so that
+	// the chart stays aligned with the KPI cards. Those don't filter on the
+	// officer's user_type, so neither does the chart.
+	chartFilters := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != "user_type" {
+			chartFilters[key] = value
 		}
 	}
+	loanFilters, args, _ := buildLoanFilters(chartFilters, 1)
 
-	if dpdMin, ok := filters["dpd_min"].(int); ok {
-		query += fmt.Sprintf(" AND l.current_dpd >= $%d", argCount)
-		args = append(args, dpdMin)
-		argCount++
-	}
-
-	if dpdMax, ok := filters["dpd_max"].(int); ok {
-		query += fmt.Sprintf(" AND l.current_dpd <= $%d", argCount)
-		args = append(args, dpdMax)
-		argCount++
-	}
-
+	query += loanFilters
 	query += `
 		GROUP BY DATE(r.payment_date)
 		ORDER BY DATE(r.payment_date)
@@ -4802,25 +6929,134 @@ func (r *DashboardRepository) GetDailyCollections(filters map[string]interface{}
 	defer rows.Close()
 
 	results := []*models.DailyCollectionsPoint{}
+	reversedByDate := make(map[string]models.Money)
 	for rows.Next() {
 		point := &models.DailyCollectionsPoint{}
+		var reversedAmount models.Money
+		var reversedCount int
 		if err := rows.Scan(
 			&point.Date,
 			&point.CollectedAmount,
 			&point.RepaymentsCount,
+			&point.DistinctLoansPaid,
+			&point.DistinctCustomersPaid,
 			&point.AgentDebitAmount,
 			&point.TransferAmount,
 			&point.EscrowDebitAmount,
 			&point.OtherRepaymentsAmount,
+			&reversedAmount,
+			&reversedCount,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan daily collections row: %w", err)
 		}
+		if includeReversed {
+			point.ReversedAmount = &reversedAmount
+			point.ReversedCount = &reversedCount
+			reversedByDate[point.Date] = reversedAmount
+		}
 		results = append(results, point)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("failed to iterate daily collections rows: %w", err)
 	}
 
+	// Expected (due) amount per day.
+	//
+	// We do not keep a historical snapshot of each loan's schedule, so "due" for a
+	// past day is approximated as the sum of the loan's current
+	// daily_repayment_amount over loans that were in their repayment window on
+	// that day: first payment due (or the day after disbursement when unknown)
+	// on or before the day, maturity on or after the day, and not closed before
+	// the day. Loan filters (including current_dpd / status) are evaluated on the
+	// loan's current state, exactly as for the collected amounts above.
+	dueQuery := fmt.Sprintf(`
+			SELECT
+				d.day::date AS due_date,
+				COALESCE(SUM(`+r.dailyRepaymentSQL()+`), 0) AS due_amount
+			FROM generate_series(%s, %s, INTERVAL '1 day') AS d(day)
+			INNER JOIN loans l
+				ON COALESCE(l.first_payment_due_date, l.disbursement_date::date + 1) <= d.day::date
+				AND l.maturity_date >= d.day::date
+				AND (l.closed_date IS NULL OR l.closed_date > d.day::date)
+			INNER JOIN officers o ON l.officer_id = o.officer_id
+			WHERE `+r.userTypeFilter()+`
+		`, periodStart, periodEnd)
+	dueQuery += loanFilters
+	dueQuery += `
+			GROUP BY d.day
+		`
+
+	dueRows, err := r.db.Query(dueQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve daily due amounts: %w", err)
+	}
+	defer dueRows.Close()
+
+	pointsByDate := make(map[string]*models.DailyCollectionsPoint, len(results))
+	for _, point := range results {
+		pointsByDate[point.Date] = point
+	}
+
+	for dueRows.Next() {
+		var date string
+		var dueAmount models.Money
+		if err := dueRows.Scan(&date, &dueAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan daily due amount row: %w", err)
+		}
+
+		point, ok := pointsByDate[date]
+		if !ok {
+			// A day with expected repayments but no collections at all.
+			point = &models.DailyCollectionsPoint{Date: date}
+			pointsByDate[date] = point
+			results = append(results, point)
+		}
+		point.DueAmount = dueAmount
+	}
+	if err := dueRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate daily due amount rows: %w", err)
+	}
+
+	// Zero-fill days with neither collections nor dues so consumers get a
+	// continuous series. Dates are scanned the same way as above so they match
+	// the keys of pointsByDate.
+	dayRows, err := r.db.Query(fmt.Sprintf(`
+			SELECT d.day::date
+			FROM generate_series(%s, %s, INTERVAL '1 day') AS d(day)
+		`, periodStart, periodEnd))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve daily collections days: %w", err)
+	}
+	defer dayRows.Close()
+
+	for dayRows.Next() {
+		var date string
+		if err := dayRows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("failed to scan daily collections day: %w", err)
+		}
+		if _, ok := pointsByDate[date]; !ok {
+			point := &models.DailyCollectionsPoint{Date: date}
+			pointsByDate[date] = point
+			results = append(results, point)
+		}
+	}
+	if err := dayRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate daily collections days: %w", err)
+	}
+
+	for _, point := range results {
+		if includeReversed && point.ReversedAmount == nil {
+			zeroAmount, zeroCount := models.NewMoney(decimal.Zero), 0
+			point.ReversedAmount, point.ReversedCount = &zeroAmount, &zeroCount
+		}
+		netCollected := point.CollectedAmount.Sub(reversedByDate[point.Date].Decimal)
+		point.MissedAmount = models.NewMoney(decimal.Max(decimal.Zero, point.DueAmount.Sub(netCollected)))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Date < results[j].Date
+	})
+
 	return results, nil
 }
 
@@ -4838,13 +7074,13 @@ func (r *DashboardRepository) getRegions() ([]string, error) {
 			SELECT l.region AS region
 			FROM loans l
 			INNER JOIN officers o ON l.officer_id = o.officer_id
-			WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			WHERE ` + r.userTypeFilter() + `
 
 			UNION
 
 			SELECT o.region AS region
 			FROM officers o
-			WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			WHERE ` + r.userTypeFilter() + `
 		) regions
 		WHERE region IS NOT NULL AND region != ''
 		ORDER BY region`
@@ -4872,7 +7108,7 @@ func (r *DashboardRepository) getWaves() ([]string, error) {
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.wave IS NOT NULL
 		AND l.wave != ''
-		AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		AND ` + r.userTypeFilter() + `
 		ORDER BY l.wave`
 
 	rows, err := r.db.Query(query)
@@ -4896,7 +7132,7 @@ func (r *DashboardRepository) getWaves() ([]string, error) {
 func (r *DashboardRepository) getChannels() ([]string, error) {
 	query := `SELECT DISTINCT l.channel FROM loans l
 		INNER JOIN officers o ON l.officer_id = o.officer_id
-		WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		WHERE ` + r.userTypeFilter() + `
 		ORDER BY l.channel`
 
 	rows, err := r.db.Query(query)
@@ -4947,7 +7183,7 @@ func (r *DashboardRepository) getStatuses() ([]string, error) {
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.status IS NOT NULL
 		AND l.status != ''
-		AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		AND ` + r.userTypeFilter() + `
 		ORDER BY l.status`
 
 	rows, err := r.db.Query(query)
@@ -4973,7 +7209,7 @@ func (r *DashboardRepository) getLoanTypes() ([]string, error) {
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.loan_type IS NOT NULL
 		AND l.loan_type != ''
-		AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		AND ` + r.userTypeFilter() + `
 		ORDER BY l.loan_type`
 
 	rows, err := r.db.Query(query)
@@ -4999,7 +7235,7 @@ func (r *DashboardRepository) getVerificationStatuses() ([]string, error) {
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.verification_status IS NOT NULL
 		AND l.verification_status != ''
-		AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		AND ` + r.userTypeFilter() + `
 		ORDER BY l.verification_status`
 
 	rows, err := r.db.Query(query)
@@ -5026,7 +7262,7 @@ func (r *DashboardRepository) getVerticalLeads() ([]string, error) {
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.vertical_lead_email IS NOT NULL
 		AND l.vertical_lead_email != ''
-		AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		AND ` + r.userTypeFilter() + `
 		ORDER BY l.vertical_lead_email`
 
 	rows, err := r.db.Query(query)
@@ -5047,6 +7283,71 @@ func (r *DashboardRepository) getVerticalLeads() ([]string, error) {
 	return verticalLeads, nil
 }
 
+// getSupervisors returns the distinct supervisors configured on officers, one
+// per email. When an email appears with several spellings of the name, the
+// alphabetically first is used.
+func (r *DashboardRepository) getSupervisors() ([]*models.SupervisorOption, error) {
+	query := `SELECT o.supervisor_email, MIN(NULLIF(o.supervisor_name, '')) FROM officers o
+		WHERE o.supervisor_email IS NOT NULL
+		AND o.supervisor_email != ''
+		AND ` + r.userTypeFilter() + `
+		GROUP BY o.supervisor_email
+		ORDER BY o.supervisor_email`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	supervisors := []*models.SupervisorOption{}
+	for rows.Next() {
+		supervisor := &models.SupervisorOption{}
+		if err := rows.Scan(&supervisor.Email, &supervisor.Name); err != nil {
+			return nil, err
+		}
+		supervisors = append(supervisors, supervisor)
+	}
+
+	return supervisors, nil
+}
+
+// verticalLeadNameFilter builds an " AND (...)" clause for a comma-separated
+// list of vertical lead names. UnassignedVerticalLead matches loans whose
+// vertical_lead_name is NULL or blank, mirroring how GetVerticalLeadMetrics
+// and GetVerticalLeadNames bucket them.
+func verticalLeadNameFilter(raw string, argCount int) (string, []interface{}, int) {
+	args := []interface{}{}
+	placeholders := []string{}
+	includeUnassigned := false
+
+	for _, n := range strings.Split(raw, ",") {
+		name := strings.TrimSpace(n)
+		if name == "" {
+			continue
+		}
+		if name == UnassignedVerticalLead {
+			includeUnassigned = true
+			continue
+		}
+		placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+		args = append(args, name)
+		argCount++
+	}
+
+	conditions := []string{}
+	if len(placeholders) > 0 {
+		conditions = append(conditions, fmt.Sprintf("l.vertical_lead_name IN (%s)", strings.Join(placeholders, ", ")))
+	}
+	if includeUnassigned {
+		conditions = append(conditions, "(l.vertical_lead_name IS NULL OR l.vertical_lead_name = '')")
+	}
+	if len(conditions) == 0 {
+		return "", args, argCount
+	}
+	return " AND (" + strings.Join(conditions, " OR ") + ")", args, argCount
+}
+
 // GetVerticalLeadNames returns the distinct vertical lead names used on loans.
 //
 // It includes a synthetic "Unassigned Vertical Lead" bucket for loans where
@@ -5057,6 +7358,7 @@ func (r *DashboardRepository) GetVerticalLeadNames() ([]string, error) {
 		SELECT DISTINCT
 			COALESCE(NULLIF(l.vertical_lead_name, ''), 'Unassigned Vertical Lead') AS vertical_lead_name
 		FROM loans l
+		WHERE 1=1` + r.excludedOfficerFilter() + `
 		ORDER BY vertical_lead_name`
 
 	rows, err := r.db.Query(query)
@@ -5087,7 +7389,7 @@ func (r *DashboardRepository) getDjangoStatuses() ([]string, error) {
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE l.django_status IS NOT NULL
 		AND l.django_status != ''
-		AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+		AND ` + r.userTypeFilter() + `
 		ORDER BY l.django_status`
 
 	rows, err := r.db.Query(query)
@@ -5111,7 +7413,7 @@ func (r *DashboardRepository) getDjangoStatuses() ([]string, error) {
 func (r *DashboardRepository) getOfficerOptions(filters map[string]interface{}) ([]*models.OfficerOption, error) {
 	query := `SELECT DISTINCT l.officer_id, l.officer_name, o.officer_email, l.branch, l.region FROM loans l
 		INNER JOIN officers o ON l.officer_id = o.officer_id
-		WHERE (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)`
+		WHERE ` + r.userTypeFilter() + ``
 	args := []interface{}{}
 	argCount := 1
 
@@ -5248,19 +7550,86 @@ func (r *DashboardRepository) GetOfficerAuditHistory(officerID string, limit int
 	return history, nil
 }
 
-// UpdatePastMaturityStatus updates django_status to 'PAST_MATURITY' for eligible loans.
-// It only affects loans that are currently marked as OPEN and have a maturity_date
-// earlier than the current date. Other django_status values (COMPLETED, DECLINED, etc.)
-// are left unchanged. Returns the count of loans updated.
-func (r *DashboardRepository) UpdatePastMaturityStatus() (int64, error) {
+// RecomputeFIMRTags re-derives fimr_tagged for every loan with a
+// first_payment_due_date, instead of trusting the upstream tag. A loan is FIMR
+// when its first payment arrived more than gracePeriodDays after the due date,
+// or has not arrived and that grace period is over. Loans without a due date
+// keep their tag. recalculate_all_loan_fields() applies its own (zero-grace)
+// rule, so RecalculateAllLoanFields runs this right after it. Returns the
+// count of loans whose tag changed.
+func (r *DashboardRepository) RecomputeFIMRTags(gracePeriodDays int) (int64, error) {
+	if gracePeriodDays < 0 {
+		return 0, fmt.Errorf("%w: grace period must not be negative", ErrInvalidFilter)
+	}
+
+	query := `
+		WITH recomputed AS (
+			SELECT
+				loan_id,
+				CASE
+					WHEN first_payment_received_date IS NOT NULL
+						THEN first_payment_received_date > first_payment_due_date + $1::int
+					ELSE CURRENT_DATE > first_payment_due_date + $1::int
+				END AS fimr
+			FROM loans
+			WHERE first_payment_due_date IS NOT NULL
+		)
+		UPDATE loans l
+		SET fimr_tagged = rc.fimr
+		FROM recomputed rc
+		WHERE l.loan_id = rc.loan_id
+		  AND l.fimr_tagged IS DISTINCT FROM rc.fimr
+	`
+
+	result, err := r.db.Exec(query, gracePeriodDays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute FIMR tags: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// UpdatePastMaturityStatus updates django_status to 'PAST_MATURITY' for eligible loans,
+// and status to the value DjangoStatusMapping gives it, so the loans don't show
+// up in GetStatusMismatches. It only affects loans that are currently marked as
+// OPEN and have a maturity_date earlier than the current date. Other django_status
+// values (COMPLETED, DECLINED, etc.) are left unchanged. The optional branch and
+// region (comma-separated) filters scope the update; without them every eligible
+// loan is updated. Returns the count of loans updated.
+func (r *DashboardRepository) UpdatePastMaturityStatus(filters map[string]interface{}) (int64, error) {
 	query := `
 		UPDATE loans
-		SET django_status = 'PAST_MATURITY'
+		SET django_status = 'PAST_MATURITY',
+			status = $1
 		WHERE maturity_date < CURRENT_DATE
 		  AND django_status = 'OPEN'
 	`
 
-	result, err := r.db.Exec(query)
+	args := []interface{}{DjangoStatusMapping["PAST_MATURITY"]}
+	argCount := 2
+
+	if branch, ok := filters["branch"].(string); ok && branch != "" {
+		query += fmt.Sprintf(" AND branch = $%d", argCount)
+		args = append(args, branch)
+		argCount++
+	}
+
+	if region, ok := filters["region"].(string); ok && region != "" {
+		placeholders := []string{}
+		for _, rgn := range strings.Split(region, ",") {
+			placeholders = append(placeholders, fmt.Sprintf("$%d", argCount))
+			args = append(args, strings.TrimSpace(rgn))
+			argCount++
+		}
+		query += fmt.Sprintf(" AND region IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	result, err := r.db.Exec(query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to update past maturity status: %w", err)
 	}
@@ -5272,3 +7641,473 @@ func (r *DashboardRepository) UpdatePastMaturityStatus() (int64, error) {
 
 	return rowsAffected, nil
 }
+
+// GetDataFreshness reports how current the synced data is: the latest
+// repayment payment_date, the highest numeric repayment_id (the incremental
+// sync watermark, see maxNumericRepaymentIDSQL), the most recent sync run and
+// the finish time of the last completed one. Everything is read in one round
+// trip: the two MAXes come from idx_repayments_payment_date and
+// idx_repayments_numeric_id, so the frontend can poll it cheaply.
+// IsStale is set when no sync has completed within StaleDataAfterHours,
+// compared against the database clock that stamped the runs.
+func (r *DashboardRepository) GetDataFreshness() (*models.DataFreshness, error) {
+	query := `
+		SELECT
+			(SELECT MAX(payment_date) FROM repayments) AS latest_payment_date,
+			(` + maxNumericRepaymentIDSQL + `) AS max_repayment_id,
+			lc.finished_at AS last_completed_sync_at,
+			$2::int > 0 AND COALESCE(lc.finished_at < NOW() - make_interval(hours => $2::int), TRUE) AS is_stale,
+			lr.run_id,
+			lr.sync_type,
+			lr.scope,
+			lr.status,
+			lr.total_synced,
+			lr.total_errors,
+			lr.started_at,
+			lr.finished_at
+		FROM (SELECT MAX(finished_at) AS finished_at FROM sync_runs WHERE status = $1) lc
+		LEFT JOIN LATERAL (
+			SELECT run_id, sync_type, scope, status, total_synced, total_errors, started_at, finished_at
+			FROM sync_runs
+			ORDER BY started_at DESC, run_id DESC
+			LIMIT 1
+		) lr ON TRUE
+	`
+
+	freshness := &models.DataFreshness{StaleAfterHours: r.cfg.StaleDataAfterHours}
+	var (
+		runID       sql.NullInt64
+		syncType    sql.NullString
+		scope       sql.NullString
+		status      sql.NullString
+		totalSynced sql.NullInt64
+		totalErrors sql.NullInt64
+		startedAt   sql.NullTime
+		finishedAt  sql.NullTime
+	)
+
+	err := r.db.QueryRow(query, SyncRunCompleted, r.cfg.StaleDataAfterHours).Scan(
+		&freshness.LatestPaymentDate,
+		&freshness.MaxRepaymentID,
+		&freshness.LastCompletedSyncAt,
+		&freshness.IsStale,
+		&runID,
+		&syncType,
+		&scope,
+		&status,
+		&totalSynced,
+		&totalErrors,
+		&startedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data freshness: %w", err)
+	}
+
+	if runID.Valid {
+		run := &models.SyncRun{
+			RunID:       runID.Int64,
+			SyncType:    syncType.String,
+			Status:      status.String,
+			TotalSynced: int(totalSynced.Int64),
+			TotalErrors: int(totalErrors.Int64),
+			StartedAt:   startedAt.Time,
+		}
+		if scope.Valid {
+			run.Scope = &scope.String
+		}
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		freshness.LastSyncRun = run
+	}
+
+	return freshness, nil
+}
+
+// GetOrphans reports loans whose officer_id is missing from officers and
+// repayments whose loan_id is missing from loans, with up to sampleLimit
+// examples of each (largest balance / newest payment first). Both kinds of
+// row vanish from every metric that INNER JOINs them.
+func (r *DashboardRepository) GetOrphans(sampleLimit int) (*models.OrphanReport, error) {
+	report := &models.OrphanReport{
+		LoanSamples:      []*models.OrphanedLoan{},
+		RepaymentSamples: []*models.OrphanedRepayment{},
+		SampleLimit:      sampleLimit,
+	}
+
+	err := r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM loans l
+			 WHERE NOT EXISTS (SELECT 1 FROM officers o WHERE o.officer_id = l.officer_id)),
+			(SELECT COALESCE(SUM(l.actual_outstanding), 0)::float FROM loans l
+			 WHERE NOT EXISTS (SELECT 1 FROM officers o WHERE o.officer_id = l.officer_id)),
+			(SELECT COUNT(*) FROM repayments r
+			 WHERE NOT EXISTS (SELECT 1 FROM loans l WHERE l.loan_id = r.loan_id)),
+			(SELECT COALESCE(SUM(r.payment_amount), 0)::float FROM repayments r
+			 WHERE NOT EXISTS (SELECT 1 FROM loans l WHERE l.loan_id = r.loan_id))
+	`).Scan(
+		&report.LoansWithoutOfficer,
+		&report.LoansWithoutOfficerOutstanding,
+		&report.RepaymentsWithoutLoan,
+		&report.RepaymentsWithoutLoanAmount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orphaned rows: %w", err)
+	}
+
+	if report.LoansWithoutOfficer > 0 {
+		rows, err := r.db.Query(`
+			SELECT
+				l.loan_id,
+				COALESCE(l.officer_id, '') AS officer_id,
+				COALESCE(l.customer_name, '') AS customer_name,
+				COALESCE(l.django_status, '') AS django_status,
+				COALESCE(l.actual_outstanding, 0)::float AS actual_outstanding
+			FROM loans l
+			WHERE NOT EXISTS (SELECT 1 FROM officers o WHERE o.officer_id = l.officer_id)
+			ORDER BY l.actual_outstanding DESC NULLS LAST, l.loan_id
+			LIMIT $1
+		`, sampleLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample orphaned loans: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			loan := &models.OrphanedLoan{}
+			if err := rows.Scan(&loan.LoanID, &loan.OfficerID, &loan.CustomerName, &loan.DjangoStatus, &loan.ActualOutstanding); err != nil {
+				return nil, fmt.Errorf("failed to scan orphaned loan: %w", err)
+			}
+			report.LoanSamples = append(report.LoanSamples, loan)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if report.RepaymentsWithoutLoan > 0 {
+		rows, err := r.db.Query(`
+			SELECT
+				r.repayment_id,
+				r.loan_id,
+				TO_CHAR(r.payment_date, 'YYYY-MM-DD') AS payment_date,
+				r.payment_amount::float AS payment_amount
+			FROM repayments r
+			WHERE NOT EXISTS (SELECT 1 FROM loans l WHERE l.loan_id = r.loan_id)
+			ORDER BY r.payment_date DESC, r.repayment_id
+			LIMIT $1
+		`, sampleLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample orphaned repayments: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			rp := &models.OrphanedRepayment{}
+			if err := rows.Scan(&rp.RepaymentID, &rp.LoanID, &rp.PaymentDate, &rp.PaymentAmount); err != nil {
+				return nil, fmt.Errorf("failed to scan orphaned repayment: %w", err)
+			}
+			report.RepaymentSamples = append(report.RepaymentSamples, rp)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// Status mismatch types reported by GetStatusMismatches.
+const (
+	// StatusMismatchUnmapped: the django_status is not in DjangoStatusMapping,
+	// so the sync fell back to its default status.
+	StatusMismatchUnmapped = "unmapped_django_status"
+	// StatusMismatchConflict: the django_status is mapped but the loan's
+	// status differs from the mapped value, e.g. a stale sync.
+	StatusMismatchConflict = "status_mismatch"
+)
+
+// DjangoStatusMapping is the documented raw Django loan status to normalized
+// loans.status mapping. The DjangoRepository loan query generates its status
+// CASE from it (see djangoStatusCaseSQL).
+var DjangoStatusMapping = map[string]string{
+	"COMPLETED":              "Closed",
+	"CLOSED":                 "Closed",
+	"OPEN":                   "Active",
+	"OPEN_TO_SUPERVISOR":     "Active",
+	"APPROVED":               "Active",
+	"ACTIVE":                 "Active",
+	"PAST_MATURITY":          "Defaulted",
+	"DEFAULTED":              "Defaulted",
+	"DECLINED_BY_SUPERVISOR": "Rejected",
+	"REJECTED":               "Rejected",
+	"NOT_TAKEN":              "Cancelled",
+}
+
+// GetStatusMismatches reports loans whose status disagrees with their
+// django_status under DjangoStatusMapping: loans with a django_status missing
+// from the mapping, and loans whose status differs from the mapped value.
+// Counts are broken down by mismatch type and status combination, with up to
+// sampleLimit example loans (largest balance first). Loans without a
+// django_status are not checked.
+func (r *DashboardRepository) GetStatusMismatches(sampleLimit int) (*models.StatusMismatchReport, error) {
+	djangoStatuses := make([]string, 0, len(DjangoStatusMapping))
+	for djangoStatus := range DjangoStatusMapping {
+		djangoStatuses = append(djangoStatuses, djangoStatus)
+	}
+	sort.Strings(djangoStatuses)
+
+	args := []interface{}{}
+	values := make([]string, len(djangoStatuses))
+	for i, djangoStatus := range djangoStatuses {
+		values[i] = fmt.Sprintf("($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, djangoStatus, DjangoStatusMapping[djangoStatus])
+	}
+
+	mismatchCTE := fmt.Sprintf(`
+		WITH mapping(django_status, expected_status) AS (
+			VALUES %s
+		),
+		mismatched AS (
+			SELECT
+				l.loan_id,
+				COALESCE(l.officer_id, '') AS officer_id,
+				COALESCE(l.customer_name, '') AS customer_name,
+				l.django_status,
+				COALESCE(l.status, '') AS status,
+				COALESCE(m.expected_status, '') AS expected_status,
+				CASE WHEN m.expected_status IS NULL THEN '%s' ELSE '%s' END AS mismatch_type,
+				COALESCE(l.actual_outstanding, 0)::float AS actual_outstanding
+			FROM loans l
+			LEFT JOIN mapping m ON m.django_status = l.django_status
+			WHERE NULLIF(l.django_status, '') IS NOT NULL
+				AND (m.expected_status IS NULL OR l.status IS DISTINCT FROM m.expected_status)
+		)
+	`, strings.Join(values, ", "), StatusMismatchUnmapped, StatusMismatchConflict)
+
+	report := &models.StatusMismatchReport{
+		CountsByType: map[string]int{StatusMismatchUnmapped: 0, StatusMismatchConflict: 0},
+		Breakdown:    []*models.StatusMismatchCount{},
+		LoanSamples:  []*models.StatusMismatchLoan{},
+		SampleLimit:  sampleLimit,
+		Mapping:      DjangoStatusMapping,
+	}
+
+	rows, err := r.db.Query(mismatchCTE+`
+		SELECT mismatch_type, django_status, status, expected_status, COUNT(*)
+		FROM mismatched
+		GROUP BY 1, 2, 3, 4
+		ORDER BY 5 DESC, 2, 3
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count status mismatches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		count := &models.StatusMismatchCount{}
+		if err := rows.Scan(&count.MismatchType, &count.DjangoStatus, &count.Status, &count.ExpectedStatus, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan status mismatch count: %w", err)
+		}
+		report.Breakdown = append(report.Breakdown, count)
+		report.CountsByType[count.MismatchType] += count.Count
+		report.TotalMismatches += count.Count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if report.TotalMismatches == 0 || sampleLimit == 0 {
+		return report, nil
+	}
+
+	sampleRows, err := r.db.Query(mismatchCTE+fmt.Sprintf(`
+		SELECT loan_id, officer_id, customer_name, django_status, status, expected_status, mismatch_type, actual_outstanding
+		FROM mismatched
+		ORDER BY actual_outstanding DESC, loan_id
+		LIMIT $%d
+	`, len(args)+1), append(args, sampleLimit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample status mismatches: %w", err)
+	}
+	defer sampleRows.Close()
+
+	for sampleRows.Next() {
+		loan := &models.StatusMismatchLoan{}
+		if err := sampleRows.Scan(&loan.LoanID, &loan.OfficerID, &loan.CustomerName, &loan.DjangoStatus,
+			&loan.Status, &loan.ExpectedStatus, &loan.MismatchType, &loan.ActualOutstanding); err != nil {
+			return nil, fmt.Errorf("failed to scan status mismatch loan: %w", err)
+		}
+		report.LoanSamples = append(report.LoanSamples, loan)
+	}
+	if err := sampleRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// reviewFlaggedLoanSQL restricts loans (aliased l) to those on the manual
+// review worklist.
+const reviewFlaggedLoanSQL = "EXISTS (SELECT 1 FROM loan_review_flags f WHERE f.loan_id = l.loan_id)"
+
+// maxReviewFlagLoans caps how many loans one FlagLoansForReview call accepts.
+const maxReviewFlagLoans = 500
+
+// FlagLoansForReview adds the loans in input to the manual review worklist,
+// replacing the note and assignee of loans already on it. It returns the
+// flagged loan IDs and the requested IDs that matched no loan.
+func (r *DashboardRepository) FlagLoansForReview(input *models.LoanReviewFlagInput) ([]string, []string, error) {
+	seen := make(map[string]bool)
+	loanIDs := []string{}
+	for _, id := range input.LoanIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		loanIDs = append(loanIDs, id)
+	}
+	if len(loanIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: loan_ids must contain at least one loan ID", ErrInvalidFilter)
+	}
+	if len(loanIDs) > maxReviewFlagLoans {
+		return nil, nil, fmt.Errorf("%w: at most %d loan_ids can be flagged at once", ErrInvalidFilter, maxReviewFlagLoans)
+	}
+
+	args := []interface{}{strings.TrimSpace(input.Note), strings.TrimSpace(input.Assignee)}
+	placeholders := make([]string, len(loanIDs))
+	for i, id := range loanIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO loan_review_flags (loan_id, note, assignee, created_at, updated_at)
+		SELECT l.loan_id, $1, $2, NOW(), NOW()
+		FROM loans l
+		WHERE l.loan_id IN (%s)
+		ON CONFLICT (loan_id) DO UPDATE
+		SET note = EXCLUDED.note, assignee = EXCLUDED.assignee, updated_at = NOW()
+		RETURNING loan_id
+	`, strings.Join(placeholders, ", "))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to flag loans for review: %w", err)
+	}
+	defer rows.Close()
+
+	flagged := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan flagged loan: %w", err)
+		}
+		flagged[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to flag loans for review: %w", err)
+	}
+
+	flaggedIDs := []string{}
+	unknownIDs := []string{}
+	for _, id := range loanIDs {
+		if flagged[id] {
+			flaggedIDs = append(flaggedIDs, id)
+		} else {
+			unknownIDs = append(unknownIDs, id)
+		}
+	}
+
+	return flaggedIDs, unknownIDs, nil
+}
+
+// GetLoanReviewFlags lists the manual review worklist, most recently flagged
+// first. filters takes the standard loan filters (see buildLoanFilters) plus
+// assignee, page and limit.
+func (r *DashboardRepository) GetLoanReviewFlags(filters map[string]interface{}) ([]*models.LoanReviewFlag, int, error) {
+	limit, offset, err := r.pageBounds(filters, 50)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	loanFilters, args, argCount := buildLoanFilters(filters, 1)
+	if assignee, ok := filters["assignee"].(string); ok && assignee != "" {
+		loanFilters += fmt.Sprintf(" AND f.assignee = $%d", argCount)
+		args = append(args, assignee)
+		argCount++
+	}
+
+	fromWhere := fmt.Sprintf(`
+		FROM loan_review_flags f
+		INNER JOIN loans l ON f.loan_id = l.loan_id
+		INNER JOIN officers o ON l.officer_id = o.officer_id
+		WHERE %s
+			%s`, r.userTypeFilter(), loanFilters)
+
+	// Counted separately so the total is still reported for a page past the
+	// last flag.
+	total := 0
+	if err := r.db.QueryRow("SELECT COUNT(*)"+fromWhere, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count loan review flags: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			f.loan_id,
+			f.note,
+			f.assignee,
+			f.created_at,
+			f.updated_at,
+			COALESCE(l.customer_name, '') AS customer_name,
+			COALESCE(l.customer_phone, '') AS customer_phone,
+			l.officer_id,
+			COALESCE(o.officer_name, l.officer_name, '') AS officer_name,
+			COALESCE(l.branch, '') AS branch,
+			COALESCE(l.region, '') AS region,
+			COALESCE(l.django_status, '') AS django_status,
+			COALESCE(l.current_dpd, 0) AS current_dpd,
+			COALESCE(l.actual_outstanding, 0)::float AS actual_outstanding
+		%s
+		ORDER BY f.updated_at DESC, f.loan_id
+		LIMIT $%d OFFSET $%d
+	`, fromWhere, argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve loan review flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := []*models.LoanReviewFlag{}
+	for rows.Next() {
+		flag := &models.LoanReviewFlag{}
+		if err := rows.Scan(
+			&flag.LoanID,
+			&flag.Note,
+			&flag.Assignee,
+			&flag.FlaggedAt,
+			&flag.UpdatedAt,
+			&flag.CustomerName,
+			&flag.CustomerPhone,
+			&flag.OfficerID,
+			&flag.OfficerName,
+			&flag.Branch,
+			&flag.Region,
+			&flag.DjangoStatus,
+			&flag.CurrentDPD,
+			&flag.ActualOutstanding,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan loan review flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate loan review flags: %w", err)
+	}
+
+	return flags, total, nil
+}
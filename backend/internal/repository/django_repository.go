@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/seeds-metrics/analytics-backend/internal/models"
@@ -337,10 +340,32 @@ func (r *DjangoRepository) GetLoansCount(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-// GetLoans retrieves loans from Django database with pagination
-// Returns basic loan data that will be used to create LoanInput
-func (r *DjangoRepository) GetLoans(ctx context.Context, limit, offset int) ([]map[string]interface{}, error) {
-	query := `
+// djangoStatusFallback is the loans.status given to Django statuses missing
+// from DjangoStatusMapping.
+const djangoStatusFallback = "Active"
+
+// djangoStatusCaseSQL returns a CASE expression mapping the Django status in
+// column to loans.status, generated from DjangoStatusMapping so the sync and
+// GET /integrity/status-mismatch cannot drift apart.
+func djangoStatusCaseSQL(column string) string {
+	djangoStatuses := make([]string, 0, len(DjangoStatusMapping))
+	for djangoStatus := range DjangoStatusMapping {
+		djangoStatuses = append(djangoStatuses, djangoStatus)
+	}
+	sort.Strings(djangoStatuses)
+
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, djangoStatus := range djangoStatuses {
+		fmt.Fprintf(&b, "\n\t\t\t\tWHEN %s = '%s' THEN '%s'", column, djangoStatus, DjangoStatusMapping[djangoStatus])
+	}
+	fmt.Fprintf(&b, "\n\t\t\t\tELSE '%s'\n\t\t\tEND", djangoStatusFallback)
+	return b.String()
+}
+
+// djangoLoanSelect is the column list and joins shared by the Django loan
+// queries. Rows are read back with scanDjangoLoans.
+var djangoLoanSelect = `
 		SELECT
 			l.id::VARCHAR(50) as loan_id,
 			l.borrower_id::VARCHAR(50) as customer_id,
@@ -365,29 +390,7 @@ func (r *DjangoRepository) GetLoans(ctx context.Context, limit, offset int) ([]m
 			l.date_disbursed as disbursement_date,
 			l.start_date as first_payment_due_date,
 				l.end_date as maturity_date,
-				CASE
-				-- Completed/Closed loans
-				WHEN l.status = 'COMPLETED' THEN 'Closed'
-				WHEN l.status = 'CLOSED' THEN 'Closed'
-
-				-- Active/Open loans (loans in progress)
-				WHEN l.status = 'OPEN' THEN 'Active'
-				WHEN l.status = 'OPEN_TO_SUPERVISOR' THEN 'Active'
-				WHEN l.status = 'APPROVED' THEN 'Active'
-				WHEN l.status = 'ACTIVE' THEN 'Active'
-
-				-- Defaulted/Past Due loans
-				WHEN l.status = 'PAST_MATURITY' THEN 'Defaulted'
-				WHEN l.status = 'DEFAULTED' THEN 'Defaulted'
-
-				-- Rejected/Cancelled loans (loans that were not disbursed or were declined)
-				WHEN l.status = 'DECLINED_BY_SUPERVISOR' THEN 'Rejected'
-				WHEN l.status = 'REJECTED' THEN 'Rejected'
-				WHEN l.status = 'NOT_TAKEN' THEN 'Cancelled'
-
-					-- Default fallback
-					ELSE 'Active'
-				END as status,
+				` + djangoStatusCaseSQL("l.status") + ` as status,
 				l.status as django_status,
 				l.performance_status,
 			l.loan_type,
@@ -397,6 +400,12 @@ func (r *DjangoRepository) GetLoans(ctx context.Context, limit, offset int) ([]m
 		FROM loans_ajoloan l
 		LEFT JOIN accounts_customuser u ON l.agent_id = u.id
 		LEFT JOIN ajo_ajouser c ON l.borrower_id = c.id
+`
+
+// GetLoans retrieves loans from Django database with pagination
+// Returns basic loan data that will be used to create LoanInput
+func (r *DjangoRepository) GetLoans(ctx context.Context, limit, offset int) ([]map[string]interface{}, error) {
+	query := djangoLoanSelect + `
 		WHERE l.is_disbursed = TRUE
 		ORDER BY l.date_disbursed DESC
 		LIMIT $1 OFFSET $2
@@ -408,6 +417,39 @@ func (r *DjangoRepository) GetLoans(ctx context.Context, limit, offset int) ([]m
 	}
 	defer rows.Close()
 
+	return scanDjangoLoans(rows)
+}
+
+// GetLoansByIDs retrieves the disbursed Django loans with the given IDs.
+// IDs that are not numeric or not found in Django are simply absent from
+// the result.
+func (r *DjangoRepository) GetLoansByIDs(ctx context.Context, loanIDs []string) ([]map[string]interface{}, error) {
+	ids := make([]string, 0, len(loanIDs))
+	for _, id := range loanIDs {
+		if _, err := strconv.ParseInt(id, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := djangoLoanSelect + `
+		WHERE l.is_disbursed = TRUE
+			AND l.id = ANY(string_to_array($1, ',')::BIGINT[])
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, strings.Join(ids, ","))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loans from Django: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDjangoLoans(rows)
+}
+
+// scanDjangoLoans reads rows produced by djangoLoanSelect into loan maps.
+func scanDjangoLoans(rows *sql.Rows) ([]map[string]interface{}, error) {
 	var loans []map[string]interface{}
 	for rows.Next() {
 		var loanID, customerID, customerName, officerID, officerName, branch, region, status string
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDjangoStatusCaseCoversMapping checks that the sync's status CASE maps
+// every Django status the way DjangoStatusMapping documents it.
+func TestDjangoStatusCaseCoversMapping(t *testing.T) {
+	caseSQL := djangoStatusCaseSQL("l.status")
+	for djangoStatus, status := range DjangoStatusMapping {
+		assert.Contains(t, caseSQL, fmt.Sprintf("WHEN l.status = '%s' THEN '%s'", djangoStatus, status))
+	}
+	assert.Equal(t, len(DjangoStatusMapping), strings.Count(caseSQL, "WHEN "))
+	assert.Contains(t, djangoLoanSelect, caseSQL)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBranchDPDMatrixCountsMissingDPDAsCurrent checks that every bucket reads
+// current_dpd through COALESCE, so loans without one still land in a bucket
+// and the buckets add up to the total.
+func TestBranchDPDMatrixCountsMissingDPDAsCurrent(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	_, err := repo.GetBranchDPDMatrix(map[string]interface{}{})
+	require.NoError(t, err)
+
+	queries := rec.Queries()
+	require.Len(t, queries, 1)
+	bare := strings.Count(queries[0], "l.current_dpd")
+	assert.Equal(t, bare, strings.Count(queries[0], "COALESCE(l.current_dpd, 0)"))
+}
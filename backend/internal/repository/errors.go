@@ -0,0 +1,12 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrInvalidFilter is returned (wrapped) when a caller passes a filter or
+	// category value the repository does not understand.
+	ErrInvalidFilter = errors.New("invalid filter")
+
+	// ErrNotFound is returned (wrapped) when the requested record does not exist.
+	ErrNotFound = errors.New("not found")
+)
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/seeds-metrics/analytics-backend/internal/models"
+	"github.com/seeds-metrics/analytics-backend/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExcludedOfficersLeftOutOfLoanQueries checks that every loan query
+// behind the dashboard endpoints restricts officer_id to officers outside
+// ExcludedOfficerIDs.
+func TestExcludedOfficersLeftOutOfLoanQueries(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	cfg := config.DashboardConfig{ExcludedOfficerIDs: []string{"TEST-OFFICER"}}
+	repo := NewDashboardRepository(db, cfg)
+	loanRepo := NewLoanRepository(&database.DB{DB: db})
+	loanRepo.SetOfficerScope(cfg)
+
+	endpoints := map[string]func(){
+		"branches":                 func() { repo.GetBranches(map[string]interface{}{}) },
+		"fimr loans":               func() { repo.GetFIMRLoans(map[string]interface{}{}) },
+		"early indicator loans":    func() { repo.GetEarlyIndicatorLoans(map[string]interface{}{}) },
+		"early indicator summary":  func() { repo.GetEarlyIndicatorSummaryMetrics(map[string]interface{}{}) },
+		"vertical lead metrics":    func() { repo.GetVerticalLeadMetrics(map[string]interface{}{}) },
+		"vertical lead names":      func() { repo.GetVerticalLeadNames() },
+		"officer top risk loans":   func() { repo.GetTopRiskLoans("OFFICER-1", 10) },
+		"portfolio top risk loans": func() { repo.GetPortfolioTopRiskLoans(map[string]interface{}{}, 10) },
+		"all loans":                func() { repo.GetAllLoans(map[string]interface{}{}) },
+		"loan list":                func() { loanRepo.List(context.Background(), &models.LoanFilter{}) },
+	}
+
+	for name, call := range endpoints {
+		rec.Reset()
+		call()
+
+		loanQueries := 0
+		for _, query := range rec.Queries() {
+			if !strings.Contains(query, "FROM loans") {
+				continue
+			}
+			loanQueries++
+			assert.Regexp(t, `[lo]\.officer_id NOT IN \('TEST-OFFICER'\)`, query, name)
+		}
+		require.NotZero(t, loanQueries, "%s ran no loan query", name)
+	}
+}
+
+// TestLoanListFollowsIncludeNullUserType checks that LoanRepository.List
+// counts officers without a user_type only when the dashboard does.
+func TestLoanListFollowsIncludeNullUserType(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	loanRepo := NewLoanRepository(&database.DB{DB: db})
+
+	for _, includeNull := range []bool{true, false} {
+		rec.Reset()
+		loanRepo.SetOfficerScope(config.DashboardConfig{IncludeNullUserType: includeNull})
+		loanRepo.List(context.Background(), &models.LoanFilter{})
+
+		queries := rec.Queries()
+		require.NotEmpty(t, queries)
+		for _, query := range queries {
+			assert.Equal(t, includeNull, strings.Contains(query, "o.user_type IS NULL"), "include_null_user_type=%v", includeNull)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// filterOptionsCache is an in-memory TTL cache of filter option lists keyed
+// by filter type and the filters that scope it. Filter options only change
+// when a sync lands new loans or officers, so entries are also dropped
+// wholesale by invalidate when a sync completes.
+type filterOptionsCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]filterOptionsEntry
+}
+
+type filterOptionsEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// newFilterOptionsCache returns a cache holding entries for ttl. A ttl of 0
+// disables caching.
+func newFilterOptionsCache(ttl time.Duration) *filterOptionsCache {
+	return &filterOptionsCache{ttl: ttl, entries: make(map[string]filterOptionsEntry)}
+}
+
+func (c *filterOptionsCache) enabled() bool {
+	return c != nil && c.ttl > 0
+}
+
+// get returns the cached value for key if it has not expired.
+func (c *filterOptionsCache) get(key string) (interface{}, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set stores value under key for the cache's ttl.
+func (c *filterOptionsCache) set(key string, value interface{}) {
+	if !c.enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = filterOptionsEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// invalidate drops every cached entry.
+func (c *filterOptionsCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]filterOptionsEntry)
+}
+
+// filterOptionsCacheKey identifies a filter options request: the filter type
+// plus its scoping filters in a stable order.
+func filterOptionsCacheKey(filterType string, filters map[string]interface{}) string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(filterType)
+	for _, k := range keys {
+		if s, ok := filters[k].(string); ok {
+			b.WriteString("|" + k + "=" + s)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInListFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantCond  string
+		wantArgs  []interface{}
+		wantCount int
+	}{
+		{"single value", "active", "(l.django_status IN ($3))", []interface{}{"active"}, 4},
+		{"several values", "active, past_due ,", "(l.django_status IN ($3,$4))", []interface{}{"active", "past_due"}, 5},
+		{"missing only", MissingValueSentinel, "((l.django_status IS NULL OR l.django_status = ''))", nil, 3},
+		{"values and missing", "active," + MissingValueSentinel, "(l.django_status IN ($3) OR (l.django_status IS NULL OR l.django_status = ''))", []interface{}{"active"}, 4},
+		{"blank", " , ", "", nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argCount := 3
+			cond, args := inListFilter("l.django_status", tt.raw, &argCount)
+			assert.Equal(t, tt.wantCond, cond)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, tt.wantCount, argCount)
+		})
+	}
+}
+
+func TestSupervisorFilter(t *testing.T) {
+	argCount := 2
+	cond, args := supervisorFilter("a@x.com, b@x.com", &argCount)
+	assert.Equal(t, "l.officer_id IN (SELECT so.officer_id FROM officers so WHERE (so.supervisor_email IN ($2,$3)))", cond)
+	assert.Equal(t, []interface{}{"a@x.com", "b@x.com"}, args)
+	assert.Equal(t, 4, argCount)
+
+	cond, args = supervisorFilter(" ", &argCount)
+	assert.Empty(t, cond)
+	assert.Nil(t, args)
+
+	clauses, args, _ := buildLoanFilters(map[string]interface{}{"supervisor_email": "a@x.com"}, 1)
+	assert.Contains(t, clauses, " AND l.officer_id IN (SELECT so.officer_id FROM officers so WHERE (so.supervisor_email IN ($1)))")
+	assert.Equal(t, []interface{}{"a@x.com"}, args)
+}
+
+func TestPerformanceStatusFilterMatchesMissing(t *testing.T) {
+	filters := map[string]interface{}{"performance_status": "PERFORMING," + MissingValueSentinel}
+
+	clauses, args, _ := buildLoanFilters(filters, 1)
+	assert.Contains(t, clauses, " AND (l.performance_status IN ($1) OR (l.performance_status IS NULL OR l.performance_status = ''))")
+	assert.Equal(t, []interface{}{"PERFORMING"}, args)
+
+	db, rec := newRecordingDB(t)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+	_, _ = repo.GetLoansSummaryMetrics(filters)
+	for _, q := range rec.Queries() {
+		if strings.Contains(q, "l.performance_status IN") {
+			assert.Contains(t, q, "l.performance_status IS NULL", q)
+		}
+	}
+}
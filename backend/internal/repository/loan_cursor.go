@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Loan list cursors identify the last loan of a keyset page by its position
+// in the stable (disbursement_date DESC NULLS LAST, loan_id DESC) order. They
+// are opaque to clients: base64url of "YYYY-MM-DD|loan_id", with
+// loanCursorNullDate in place of the date for loans without one.
+
+// loanCursorNullDate marks a cursor loan with no disbursement_date.
+const loanCursorNullDate = "null"
+
+// EncodeLoanCursor builds the cursor that resumes GetAllLoans after the loan
+// with the given disbursement date (YYYY-MM-DD, or "" when it has none) and ID.
+func EncodeLoanCursor(disbursementDate, loanID string) string {
+	if disbursementDate == "" {
+		disbursementDate = loanCursorNullDate
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(disbursementDate + "|" + loanID))
+}
+
+// decodeLoanCursor is the inverse of EncodeLoanCursor. The date is "" for a
+// cursor loan without a disbursement_date.
+func decodeLoanCursor(cursor string) (string, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+	disbursementDate, loanID, ok := strings.Cut(string(raw), "|")
+	if !ok || loanID == "" {
+		return "", "", fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+	if disbursementDate == loanCursorNullDate {
+		return "", loanID, nil
+	}
+	if _, err := time.Parse("2006-01-02", disbursementDate); err != nil {
+		return "", "", fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+	return disbursementDate, loanID, nil
+}
+
+// loanCursorCondition returns the keyset condition selecting the loans after
+// the cursor loan in (disbursement_date DESC NULLS LAST, loan_id DESC) order,
+// with placeholders numbered from argCount, and its args. Loans without a
+// disbursement_date come after every dated loan, so a dated cursor keeps them
+// and a NULL-date cursor only moves on by loan_id among them.
+func loanCursorCondition(disbursementDate, loanID string, argCount int) (string, []interface{}) {
+	if disbursementDate == "" {
+		return fmt.Sprintf("(l.disbursement_date IS NULL AND l.loan_id < $%d)", argCount), []interface{}{loanID}
+	}
+	return fmt.Sprintf("(l.disbursement_date < $%[1]d::date"+
+		" OR (l.disbursement_date = $%[1]d::date AND l.loan_id < $%[2]d)"+
+		" OR l.disbursement_date IS NULL)", argCount, argCount+1), []interface{}{disbursementDate, loanID}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoanCursorRoundTrip(t *testing.T) {
+	date, loanID, err := decodeLoanCursor(EncodeLoanCursor("2026-01-31", "L-9"))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-31", date)
+	assert.Equal(t, "L-9", loanID)
+
+	date, loanID, err = decodeLoanCursor(EncodeLoanCursor("", "L-3"))
+	require.NoError(t, err)
+	assert.Empty(t, date)
+	assert.Equal(t, "L-3", loanID)
+
+	_, _, err = decodeLoanCursor(EncodeLoanCursor("31/01/2026", "L-9"))
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+// TestAllLoansKeysetPagesAcrossUndatedLoans walks cursors from a dated loan
+// into the undated ones: the dated cursor must keep undated loans in reach
+// and the undated cursor must page among them by loan_id.
+func TestAllLoansKeysetPagesAcrossUndatedLoans(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(3)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	pageQuery := func(cursor string) string {
+		rec.Reset()
+		_, _, err := repo.GetAllLoans(map[string]interface{}{"cursor": cursor, "limit": 1})
+		require.NoError(t, err)
+		for _, query := range rec.Queries() {
+			if strings.Contains(query, "ORDER BY l.disbursement_date") {
+				return query
+			}
+		}
+		t.Fatal("no page query recorded")
+		return ""
+	}
+
+	first := pageQuery("")
+	assert.Contains(t, first, "ORDER BY l.disbursement_date DESC NULLS LAST, l.loan_id DESC")
+
+	dated := pageQuery(EncodeLoanCursor("2026-01-31", "L-9"))
+	assert.Contains(t, dated, "OR l.disbursement_date IS NULL)")
+
+	undated := pageQuery(EncodeLoanCursor("", "L-3"))
+	assert.Contains(t, undated, "(l.disbursement_date IS NULL AND l.loan_id < $")
+	assert.NotContains(t, undated, "l.disbursement_date <")
+}
@@ -4,18 +4,31 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/models"
 	"github.com/seeds-metrics/analytics-backend/pkg/database"
 )
 
 type LoanRepository struct {
 	db *database.DB
+
+	includeNullUserType bool
+	excludedOfficerIDs  []string
 }
 
 func NewLoanRepository(db *database.DB) *LoanRepository {
-	return &LoanRepository{db: db}
+	return &LoanRepository{db: db, includeNullUserType: true}
+}
+
+// SetOfficerScope applies the dashboard officer scope (IncludeNullUserType
+// and ExcludedOfficerIDs) to List, so the loans it returns match the
+// dashboard totals.
+func (r *LoanRepository) SetOfficerScope(cfg config.DashboardConfig) {
+	r.includeNullUserType = cfg.IncludeNullUserType
+	r.excludedOfficerIDs = cfg.ExcludedOfficerIDs
 }
 
 // Create inserts a new loan or updates an existing one (ETL fields only).
@@ -35,13 +48,13 @@ func (r *LoanRepository) Create(ctx context.Context, input *models.LoanInput) er
 					channel, channel_partner,
 					status, django_status, performance_status, closed_date, wave,
 					loan_type, verification_status,
-					created_at, updated_at
+					created_at, updated_at, last_synced_at
 				) VALUES (
 					$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
 					$11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
 					$21, $22, $23, $24, COALESCE($25, 'Wave 2'),
 					$26, $27,
-					NOW(), NOW()
+					NOW(), NOW(), NOW()
 				)
 				ON CONFLICT (loan_id) DO UPDATE SET
 					customer_id = EXCLUDED.customer_id,
@@ -68,7 +81,8 @@ func (r *LoanRepository) Create(ctx context.Context, input *models.LoanInput) er
 					wave = EXCLUDED.wave,
 					loan_type = EXCLUDED.loan_type,
 					verification_status = EXCLUDED.verification_status,
-					updated_at = NOW()
+					updated_at = NOW(),
+					last_synced_at = NOW()
 			`
 
 	disbursementDate, err := time.Parse("2006-01-02", input.DisbursementDate)
@@ -99,6 +113,13 @@ func (r *LoanRepository) Create(ctx context.Context, input *models.LoanInput) er
 		closedDate = &parsed
 	}
 
+	// Store waves in canonical form so wave filters match consistently
+	var wave *string
+	if input.Wave != nil && strings.TrimSpace(*input.Wave) != "" {
+		normalized := NormalizeWave(*input.Wave)
+		wave = &normalized
+	}
+
 	_, err = r.db.ExecContext(ctx, query,
 		input.LoanID, input.CustomerID, input.CustomerName, input.CustomerPhone,
 		input.OfficerID, input.OfficerName, input.OfficerPhone,
@@ -106,13 +127,43 @@ func (r *LoanRepository) Create(ctx context.Context, input *models.LoanInput) er
 		input.LoanAmount, input.RepaymentAmount, disbursementDate, firstPaymentDueDate, maturityDate, input.LoanTermDays,
 		input.InterestRate, input.FeeAmount,
 		input.Channel, input.ChannelPartner,
-		input.Status, input.DjangoStatus, input.PerformanceStatus, closedDate, input.Wave,
+		input.Status, input.DjangoStatus, input.PerformanceStatus, closedDate, wave,
 		input.LoanType, input.VerificationStatus,
 	)
 
 	return err
 }
 
+// GetStaleLoanIDs returns the IDs of loans that have not been synced from
+// Django in the last olderThanDays days (or have never been synced), oldest
+// first.
+func (r *LoanRepository) GetStaleLoanIDs(ctx context.Context, olderThanDays int) ([]string, error) {
+	query := `
+		SELECT loan_id
+		FROM loans
+		WHERE last_synced_at IS NULL
+			OR last_synced_at < NOW() - ($1 * INTERVAL '1 day')
+		ORDER BY last_synced_at ASC NULLS FIRST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, olderThanDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale loans: %w", err)
+	}
+	defer rows.Close()
+
+	var loanIDs []string
+	for rows.Next() {
+		var loanID string
+		if err := rows.Scan(&loanID); err != nil {
+			return nil, fmt.Errorf("failed to scan stale loan: %w", err)
+		}
+		loanIDs = append(loanIDs, loanID)
+	}
+
+	return loanIDs, rows.Err()
+}
+
 // GetByID retrieves a loan by ID
 func (r *LoanRepository) GetByID(ctx context.Context, loanID string) (*models.Loan, error) {
 	query := `
@@ -174,12 +225,12 @@ func (r *LoanRepository) List(ctx context.Context, filter *models.LoanFilter) ([
 		FROM loans l
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)
+			AND ` + officerScopeSQL(r.includeNullUserType, r.excludedOfficerIDs) + `
 	`
 	countQuery := `SELECT COUNT(*) FROM loans l
 		INNER JOIN officers o ON l.officer_id = o.officer_id
 		WHERE 1=1
-			AND (o.user_type IN ('AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT') OR o.user_type IS NULL)`
+			AND ` + officerScopeSQL(r.includeNullUserType, r.excludedOfficerIDs) + ``
 	args := []interface{}{}
 	argCount := 1
 
@@ -206,8 +257,8 @@ func (r *LoanRepository) List(ctx context.Context, filter *models.LoanFilter) ([
 	}
 
 	if filter.Status != nil {
-		query += fmt.Sprintf(" AND l.status = $%d", argCount)
-		countQuery += fmt.Sprintf(" AND l.status = $%d", argCount)
+		query += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", argCount)
+		countQuery += fmt.Sprintf(" AND UPPER(l.status) = UPPER($%d)", argCount)
 		args = append(args, *filter.Status)
 		argCount++
 	}
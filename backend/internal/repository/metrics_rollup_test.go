@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricsRollupScopesPortfolioToOpenLoans checks that the rollup's loan
+// aggregates (active loans, portfolio total, PAR15 inputs, due today) all
+// cover the same open loans, so portfolio_total no longer includes closed
+// loans that active_loans leaves out.
+func TestMetricsRollupScopesPortfolioToOpenLoans(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	repo := NewDashboardRepository(db, config.DashboardConfig{OpenDjangoStatuses: []string{"OPEN", "PAST_MATURITY"}})
+
+	_, err := repo.GetMetricsRollup(map[string]interface{}{}, "branch")
+	require.NoError(t, err)
+
+	queries := rec.Queries()
+	require.Len(t, queries, 1)
+	perLoan, _, found := strings.Cut(queries[0], "per_repayment AS")
+	require.True(t, found)
+
+	assert.Contains(t, perLoan, "portfolio_total")
+	assert.Contains(t, perLoan, "AND "+repo.openStatusFilter())
+	assert.NotContains(t, perLoan, "FILTER", "active_loans must not be filtered separately from the other aggregates")
+}
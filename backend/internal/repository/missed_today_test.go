@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMissedTodaySharedBySummaryAndAllLoans checks that the per-loan
+// missed_today flag and the summary's missed repayments use the same
+// condition.
+func TestMissedTodaySharedBySummaryAndAllLoans(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = func(query string) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "SELECT COUNT(*)") {
+			return []string{"count"}, [][]driver.Value{{int64(1)}}
+		}
+		return nil, nil
+	}
+	repo := NewDashboardRepository(db, config.DashboardConfig{DailyRepaymentFallback: true})
+	missed := repo.missedTodaySQL()
+
+	usesMissed := func() bool {
+		for _, query := range rec.Queries() {
+			if strings.Contains(query, missed) {
+				return true
+			}
+		}
+		return false
+	}
+
+	repo.GetAllLoans(map[string]interface{}{})
+	assert.True(t, usesMissed(), "all loans page query does not use missedTodaySQL")
+
+	rec.Reset()
+	repo.GetLoansSummaryMetrics(map[string]interface{}{})
+	assert.True(t, usesMissed(), "loans summary does not use missedTodaySQL")
+
+	for _, part := range []string{"l.actual_outstanding > 0", "l.first_payment_due_date <= CURRENT_DATE", "count_collection_days(CURRENT_DATE, CURRENT_DATE) > 0"} {
+		assert.Contains(t, missed, part)
+	}
+}
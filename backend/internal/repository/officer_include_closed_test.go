@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfficersCountOpenLoansByDefault checks that officer metrics aggregate
+// over open loans only unless include_closed=true asks for all loans.
+func TestOfficersCountOpenLoansByDefault(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(0)
+	repo := NewDashboardRepository(db, config.DashboardConfig{OpenDjangoStatuses: []string{"OPEN"}})
+	openJoin := "l.officer_id AND " + repo.openStatusFilter()
+
+	_, _, err := repo.GetOfficers(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, rec.Queries()[0], openJoin)
+
+	rec.Reset()
+	_, _, err = repo.GetOfficers(map[string]interface{}{"include_closed": true})
+	require.NoError(t, err)
+	for _, query := range rec.Queries() {
+		assert.NotContains(t, query, openJoin)
+	}
+}
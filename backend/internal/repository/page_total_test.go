@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/seeds-metrics/analytics-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pastLastPage asks for a page well past the last row.
+var pastLastPage = map[string]interface{}{"page": 10, "limit": 50}
+
+// TestOfficersTotalPastLastPage checks that the officer total still comes
+// back when the requested page is empty.
+func TestOfficersTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(9)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	officers, total, err := repo.GetOfficers(pastLastPage)
+	require.NoError(t, err)
+	assert.Empty(t, officers)
+	assert.Equal(t, 9, total)
+}
+
+// TestUpcomingFIMRLoansTotalPastLastPage checks that the loan total still
+// comes back when the requested page is empty.
+func TestUpcomingFIMRLoansTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(4)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	loans, total, err := repo.GetUpcomingFIMRLoans(pastLastPage, 7)
+	require.NoError(t, err)
+	assert.Empty(t, loans)
+	assert.Equal(t, 4, total)
+}
+
+// TestMultiFlaggedLoansTotalPastLastPage checks that the loan total still
+// comes back when the requested page is empty.
+func TestMultiFlaggedLoansTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(6)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	loans, total, err := repo.GetMultiFlaggedLoans(pastLastPage, models.MultiFlagSignals{FIMR: true})
+	require.NoError(t, err)
+	assert.Empty(t, loans)
+	assert.Equal(t, 6, total)
+}
+
+// TestNonBusinessDayRepaymentsTotalPastLastPage checks that the repayment
+// total still comes back when the requested page is empty.
+func TestNonBusinessDayRepaymentsTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(5)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	repayments, total, err := repo.GetNonBusinessDayRepayments(pastLastPage, "this_month", true)
+	require.NoError(t, err)
+	assert.Empty(t, repayments)
+	assert.Equal(t, 5, total)
+}
+
+// TestLoanReviewFlagsTotalPastLastPage checks that the flag total still comes
+// back when the requested page is empty.
+func TestLoanReviewFlagsTotalPastLastPage(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(3)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+
+	flags, total, err := repo.GetLoanReviewFlags(pastLastPage)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+	assert.Equal(t, 3, total)
+}
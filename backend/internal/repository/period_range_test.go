@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeriodDateFilterIsHalfOpen checks every period renders as
+// ">= start AND < end + 1" so the day after a period never leaks in.
+func TestPeriodDateFilterIsHalfOpen(t *testing.T) {
+	for _, period := range CollectionsPeriods {
+		start, end := collectionsPeriodRange(period)
+		filter := periodDateFilter("DATE(r.payment_date)", start, end)
+
+		assert.Contains(t, filter, "DATE(r.payment_date) >= "+start, period)
+		assert.Contains(t, filter, "DATE(r.payment_date) < ("+end+") + 1", period)
+		assert.NotContains(t, filter, "<=", period)
+		assert.NotContains(t, strings.ToUpper(filter), "BETWEEN", period)
+	}
+}
+
+// TestCollectionsPeriodRangeAtEdges evaluates period bounds against fixed
+// dates on month, year and leap-year boundaries.
+func TestCollectionsPeriodRangeAtEdges(t *testing.T) {
+	cases := []struct {
+		today, period string
+		start, end    string
+	}{
+		{"2025-01-01", "last_month", "2024-12-01", "2024-12-31"},
+		{"2025-01-31", "last_month", "2024-12-01", "2024-12-31"},
+		{"2024-03-01", "last_month", "2024-02-01", "2024-02-29"},
+		{"2024-03-31", "last_month", "2024-02-01", "2024-02-29"},
+		{"2025-01-01", "this_month", "2025-01-01", "2025-01-01"},
+		{"2025-01-31", "this_month", "2025-01-01", "2025-01-31"},
+		{"2025-01-01", "yesterday", "2024-12-31", "2024-12-31"},
+		{"2025-01-01", "this_week", "2024-12-30", "2025-01-01"},
+		{"2025-01-05", "this_week", "2024-12-30", "2025-01-05"},
+		{"2025-01-06", "this_week", "2025-01-06", "2025-01-06"},
+		{"2025-01-01", "last_week", "2024-12-23", "2024-12-29"},
+		{"2025-01-01", "last_7_days", "2024-12-26", "2025-01-01"},
+		{"2025-01-01", "today", "2025-01-01", "2025-01-01"},
+		{"2025-01-01", "unknown", "2025-01-01", "2025-01-01"},
+	}
+
+	for _, tc := range cases {
+		today, err := time.Parse("2006-01-02", tc.today)
+		require.NoError(t, err)
+		// A late-evening clock must not push the bounds to the next day
+		today = today.Add(23*time.Hour + 59*time.Minute)
+
+		start, end := collectionsPeriodRangeAt(tc.period, today)
+
+		name := tc.period + " on " + tc.today
+		assert.Equal(t, "DATE '"+tc.start+"'", start, name)
+		assert.Equal(t, "DATE '"+tc.end+"'", end, name)
+	}
+}
+
+// TestCollectionsPeriodsResolve checks every advertised period resolves to
+// a range that starts on or before it ends.
+func TestCollectionsPeriodsResolve(t *testing.T) {
+	today := time.Date(2025, time.March, 12, 10, 0, 0, 0, time.UTC)
+	for _, period := range CollectionsPeriods {
+		start, end := collectionsPeriodBounds(period, today)
+		assert.False(t, end.Before(start), period)
+		assert.False(t, end.After(today), period)
+
+		_, _, err := resolvePeriodRange(period)
+		assert.NoError(t, err, period)
+	}
+}
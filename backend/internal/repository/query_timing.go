@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// observeQuery records how long the named dashboard sub-query took, measured
+// from start. With LogQueryTiming on, every sub-query's duration is logged.
+// A query at or above SlowQueryThreshold is logged as slow and, with
+// ExplainSlowQueries on, its plan is logged as well so the missing index can
+// be identified.
+func (r *DashboardRepository) observeQuery(name, query string, args []interface{}, start time.Time) {
+	elapsed := time.Since(start)
+	if r.cfg.LogQueryTiming {
+		log.Printf("⏱️  [debug] %s took %s", name, elapsed)
+	}
+
+	threshold := r.cfg.SlowQueryThreshold
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+	log.Printf("🐢 Slow query %s took %s (threshold %s)", name, elapsed, threshold)
+	if r.cfg.ExplainSlowQueries {
+		r.logQueryPlan(name, query, args)
+	}
+}
+
+// logQueryPlan logs the planner's EXPLAIN output for query. ANALYZE is off so
+// the query isn't executed a second time, and the EXPLAIN is cancelled after
+// ExplainTimeout so planning can't hold up the request.
+func (r *DashboardRepository) logQueryPlan(name, query string, args []interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.ExplainTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, "EXPLAIN (ANALYZE off) "+query, args...)
+	if err != nil {
+		log.Printf("⚠️  Failed to EXPLAIN %s: %v", name, err)
+		return
+	}
+	defer rows.Close()
+
+	plan := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			log.Printf("⚠️  Failed to read EXPLAIN output for %s: %v", name, err)
+			return
+		}
+		plan = append(plan, line)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("⚠️  Failed to read EXPLAIN output for %s: %v", name, err)
+		return
+	}
+
+	log.Printf("📋 Query plan for %s:\n%s", name, strings.Join(plan, "\n"))
+}
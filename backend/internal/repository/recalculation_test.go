@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecalculationRecomputesFIMRAfterStoredFunction checks that the
+// recalculation re-derives FIMR tags with the grace-aware rule right after
+// recalculate_all_loan_fields(), which applies a zero-grace rule of its own.
+func TestRecalculationRecomputesFIMRAfterStoredFunction(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = func(query string) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "COUNT(*)") {
+			return []string{"count"}, [][]driver.Value{{int64(0)}}
+		}
+		return nil, nil
+	}
+	repo := NewDashboardRepository(db, config.DashboardConfig{FIMRGracePeriodDays: 3})
+
+	result, err := repo.RecalculateAllLoanFields()
+	require.NoError(t, err)
+	assert.True(t, result.FIMRRecomputeRan)
+
+	queries := rec.Queries()
+	storedFn, fimr := -1, -1
+	for i, q := range queries {
+		if strings.Contains(q, "recalculate_all_loan_fields()") {
+			storedFn = i
+		}
+		if strings.Contains(q, "SET fimr_tagged") {
+			fimr = i
+		}
+	}
+	require.NotEqual(t, -1, storedFn)
+	require.NotEqual(t, -1, fimr, "the FIMR tags must be recomputed")
+	assert.Greater(t, fimr, storedFn)
+	assert.Contains(t, queries[fimr], "first_payment_due_date + $1::int")
+}
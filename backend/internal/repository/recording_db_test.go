@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// recordingDriver is a database/sql driver that answers every query with the
+// rows returned by its rows func (none by default) and records the SQL it was
+// sent, so tests can check what a repository method asks Postgres for
+// without a database.
+type recordingDriver struct {
+	mu      sync.Mutex
+	queries []string
+	rows    func(query string) ([]string, [][]driver.Value)
+}
+
+var recordingDriverCount atomic.Int64
+
+// newRecordingDB opens a *sql.DB backed by a fresh recordingDriver.
+func newRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	name := fmt.Sprintf("recording-%d", recordingDriverCount.Add(1))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+// Queries returns the SQL recorded since the last Reset.
+func (d *recordingDriver) Queries() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.queries...)
+}
+
+// Reset forgets the recorded SQL.
+func (d *recordingDriver) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = nil
+}
+
+func (d *recordingDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{d: c.d, query: query}, nil
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query)
+	return c.d.result(query), nil
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+func (d *recordingDriver) result(query string) driver.Rows {
+	if d.rows == nil {
+		return &recordingRows{}
+	}
+	columns, values := d.rows(query)
+	return &recordingRows{columns: columns, values: values}
+}
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+func (s *recordingStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.d.record(s.query)
+	return driver.RowsAffected(0), nil
+}
+func (s *recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.record(s.query)
+	return s.d.result(s.query), nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+func (r *recordingRows) Close() error      { return nil }
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -18,8 +18,54 @@ func NewRepaymentRepository(db *database.DB) *RepaymentRepository {
 	return &RepaymentRepository{db: db}
 }
 
+// repaymentExecer is the subset of *database.DB and *sql.Tx used to write a
+// repayment, so the same upsert runs standalone or inside a batch.
+type repaymentExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
 // Create inserts a new repayment
 func (r *RepaymentRepository) Create(ctx context.Context, input *models.RepaymentInput) error {
+	return upsertRepayment(ctx, r.db, input)
+}
+
+// CreateBatch upserts inputs in a single transaction that is committed once
+// the whole batch is written. Each row runs under its own savepoint, so a
+// row that fails is rolled back on its own and reported in failed, keyed by
+// its index in inputs, without aborting the rest of the batch. err is only
+// set when the transaction itself fails, in which case nothing was written.
+func (r *RepaymentRepository) CreateBatch(ctx context.Context, inputs []*models.RepaymentInput) (failed map[int]error, err error) {
+	tx, err := r.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin repayment batch: %w", err)
+	}
+	defer tx.Rollback()
+
+	failed = make(map[int]error)
+	for i, input := range inputs {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT repayment_row"); err != nil {
+			return nil, fmt.Errorf("failed to set savepoint: %w", err)
+		}
+		if rowErr := upsertRepayment(ctx, tx, input); rowErr != nil {
+			failed[i] = rowErr
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT repayment_row"); err != nil {
+				return nil, fmt.Errorf("failed to roll back repayment %s: %w", input.RepaymentID, err)
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT repayment_row"); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit repayment batch: %w", err)
+	}
+	return failed, nil
+}
+
+// upsertRepayment validates input and inserts or updates it through exec.
+func upsertRepayment(ctx context.Context, exec repaymentExecer, input *models.RepaymentInput) error {
 	query := `
 		INSERT INTO repayments (
 			repayment_id, loan_id, payment_date, payment_amount,
@@ -76,7 +122,7 @@ func (r *RepaymentRepository) Create(ctx context.Context, input *models.Repaymen
 		return fmt.Errorf("payment_amount must equal sum of principal_paid + interest_paid + fees_paid + penalty_paid")
 	}
 
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = exec.ExecContext(ctx, query,
 		input.RepaymentID, input.LoanID, paymentDate, input.PaymentAmount,
 		input.PrincipalPaid, input.InterestPaid, input.FeesPaid, input.PenaltyPaid,
 		input.PaymentMethod, input.PaymentReference, input.PaymentChannel,
@@ -167,17 +213,76 @@ func (r *RepaymentRepository) GetByLoanID(ctx context.Context, loanID string) ([
 	return repayments, nil
 }
 
-// GetMaxRepaymentID returns the highest repayment_id (as integer) currently in the database
-// This is used for incremental sync to determine which repayments are new
-func (r *RepaymentRepository) GetMaxRepaymentID(ctx context.Context) (int64, error) {
+// GetTimelineByCustomerID retrieves the non-reversed repayments across all of a
+// customer's loans in chronological order. Each entry carries the resulting
+// balance of its loan: the loan's expected repayment_amount less all of that
+// loan's repayments up to and including this one, floored at zero. With
+// includeReversed, reversed repayments are listed too, flagged by is_reversed;
+// they never reduce the balance.
+func (r *RepaymentRepository) GetTimelineByCustomerID(ctx context.Context, customerID string, includeReversed bool) ([]*models.CustomerRepaymentTimelineEntry, error) {
 	query := `
-		SELECT COALESCE(MAX(CAST(repayment_id AS BIGINT)), 0)
-		FROM repayments
-		WHERE repayment_id ~ '^[0-9]+$'
+		SELECT
+			r.repayment_id,
+			r.loan_id,
+			r.payment_date,
+			r.payment_amount,
+			COALESCE(r.payment_method, '') as payment_method,
+			r.payment_channel,
+			` + repaymentReversedSQL + ` as is_reversed,
+			GREATEST(
+				COALESCE(l.repayment_amount, 0) - SUM(CASE WHEN ` + repaymentReversedSQL + ` THEN 0 ELSE r.payment_amount END) OVER (
+					PARTITION BY r.loan_id
+					ORDER BY r.payment_date, r.repayment_id
+					ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+				),
+				0
+			) as balance_after_payment
+		FROM repayments r
+		INNER JOIN loans l ON r.loan_id = l.loan_id
+		WHERE l.customer_id = $1
+			AND ` + reversalFilterSQL(includeReversed) + `
+		ORDER BY r.payment_date, r.loan_id, r.repayment_id
 	`
 
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timeline := []*models.CustomerRepaymentTimelineEntry{}
+	for rows.Next() {
+		var entry models.CustomerRepaymentTimelineEntry
+		err := rows.Scan(
+			&entry.RepaymentID, &entry.LoanID, &entry.PaymentDate, &entry.PaymentAmount,
+			&entry.PaymentMethod, &entry.PaymentChannel, &entry.IsReversed, &entry.BalanceAfterPayment,
+		)
+		if err != nil {
+			return nil, err
+		}
+		timeline = append(timeline, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return timeline, nil
+}
+
+// maxNumericRepaymentIDSQL selects the highest numeric repayment_id, the
+// incremental sync watermark. The expression and predicate match the partial
+// index idx_repayments_numeric_id (migration 050), so the MAX is read from the
+// index instead of scanning repayments; keep the two identical.
+const maxNumericRepaymentIDSQL = `SELECT COALESCE(MAX(CAST(repayment_id AS BIGINT)), 0)
+		FROM repayments
+		WHERE repayment_id ~ '^[0-9]+$'`
+
+// GetMaxRepaymentID returns the highest repayment_id (as integer) currently in the database
+// This is used for incremental sync to determine which repayments are new
+func (r *RepaymentRepository) GetMaxRepaymentID(ctx context.Context) (int64, error) {
 	var maxID int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&maxID)
+	err := r.db.QueryRowContext(ctx, maxNumericRepaymentIDSQL).Scan(&maxID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get max repayment ID: %w", err)
 	}
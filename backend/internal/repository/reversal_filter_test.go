@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReversalFilterKeepsNullRows checks that the reversal helpers treat a
+// NULL is_reversed as not reversed under any alias.
+func TestReversalFilterKeepsNullRows(t *testing.T) {
+	assert.Equal(t, "NOT COALESCE(r.is_reversed, false)", reversalFilterSQL(false))
+	assert.Equal(t, "TRUE", reversalFilterSQL(true))
+	assert.Equal(t, reversalFilterSQL(false), repaymentNotReversedSQL("r"))
+	assert.Equal(t, "NOT COALESCE(rt.is_reversed, false)", repaymentNotReversedSQL("rt"))
+	assert.Contains(t, collectedTodaySQL, repaymentNotReversedSQL("rt"))
+}
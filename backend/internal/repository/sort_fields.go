@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sortable list endpoints, used as keys into sortColumns.
+const (
+	SortEntityLoans    = "loans"
+	SortEntityOfficers = "officers"
+	SortEntityBranches = "branches"
+)
+
+// sortColumns whitelists the sort_by values each list query accepts, mapping
+// the API field name to the SQL expression it orders by. sort_by is spliced
+// into ORDER BY, so anything not listed here is rejected.
+var sortColumns = map[string]map[string]string{
+	SortEntityLoans: {
+		"loan_id":                          "l.loan_id",
+		"customer_name":                    "l.customer_name",
+		"customer_phone":                   "l.customer_phone",
+		"officer_name":                     "o.officer_name",
+		"region":                           "l.region",
+		"branch":                           "l.branch",
+		"vertical_lead_name":               "l.vertical_lead_name",
+		"vertical_lead_email":              "l.vertical_lead_email",
+		"channel":                          "l.channel",
+		"loan_type":                        "l.loan_type",
+		"verification_status":              "l.verification_status",
+		"loan_amount":                      "l.loan_amount",
+		"repayment_amount":                 "l.repayment_amount",
+		"disbursement_date":                "l.disbursement_date",
+		"first_payment_due_date":           "l.first_payment_due_date",
+		"maturity_date":                    "l.maturity_date",
+		"loan_term_days":                   "l.loan_term_days",
+		"current_dpd":                      "l.current_dpd",
+		"principal_outstanding":            "l.principal_outstanding",
+		"total_outstanding":                "l.total_outstanding",
+		"actual_outstanding":               "l.actual_outstanding",
+		"total_repayments":                 "l.total_repayments",
+		"status":                           "l.status",
+		"django_status":                    "l.django_status",
+		"performance_status":               "l.performance_status",
+		"timeliness_score":                 "l.timeliness_score",
+		"repayment_health":                 "l.repayment_health",
+		"days_since_last_repayment":        "l.days_since_last_repayment",
+		"repayment_delay_rate":             "l.repayment_delay_rate",
+		"wave":                             "l.wave",
+		"daily_repayment_amount":           "l.daily_repayment_amount",
+		"repayment_days_due_today":         "l.repayment_days_due_today",
+		"repayment_days_paid":              "l.repayment_days_paid",
+		"business_days_since_disbursement": "l.business_days_since_disbursement",
+	},
+	SortEntityOfficers: {
+		"officer_name":    "o.officer_name",
+		"officer_email":   "o.officer_email",
+		"region":          "o.region",
+		"branch":          "o.branch",
+		"primary_channel": "o.primary_channel",
+		"hire_date":       "o.hire_date",
+		"total_portfolio": "total_portfolio",
+		"overdue_15d":     "overdue_15d",
+		"active_loans":    "active_loans_count",
+		"disbursed":       "disbursed",
+		"dpd1to6_bal":     "dpd1to6_bal",
+		"first_miss":      "first_miss",
+	},
+	SortEntityBranches: {
+		"branch":                   "l.branch",
+		"region":                   "l.region",
+		"portfolio_total":          "portfolio_total",
+		"overdue_15d":              "overdue_15d",
+		"par15_ratio":              "par15_ratio",
+		"active_loans":             "active_loans",
+		"total_officers":           "total_officers",
+		"avg_repayment_delay_rate": "avg_repayment_delay_rate",
+	},
+}
+
+// SortableFields returns the sort_by values accepted for entity, in
+// alphabetical order.
+func SortableFields(entity string) []string {
+	fields := make([]string, 0, len(sortColumns[entity]))
+	for field := range sortColumns[entity] {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// orderByClause validates sort_by/sort_dir from filters against the entity's
+// whitelist and returns the ORDER BY clause, falling back to the defaults
+// when they are not set.
+func orderByClause(entity string, filters map[string]interface{}, defaultColumn, defaultDir string) (string, error) {
+	column := defaultColumn
+	if field, ok := filters["sort_by"].(string); ok && field != "" {
+		expr, ok := sortColumns[entity][field]
+		if !ok {
+			return "", fmt.Errorf("%w: unsupported sort_by %q", ErrInvalidFilter, field)
+		}
+		column = expr
+	}
+
+	dir := defaultDir
+	if d, ok := filters["sort_dir"].(string); ok && d != "" {
+		switch strings.ToUpper(d) {
+		case "ASC", "DESC":
+			dir = strings.ToUpper(d)
+		default:
+			return "", fmt.Errorf("%w: unsupported sort_dir %q", ErrInvalidFilter, d)
+		}
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s", column, dir), nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFailOrphanedJobsFinishesRunningJobs checks that the startup clean-up
+// only touches running jobs and marks them finished, so GET /sync/jobs stops
+// reporting them as in progress.
+func TestFailOrphanedJobsFinishesRunningJobs(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	repo := NewSyncRepository(&database.DB{DB: db})
+
+	_, err := repo.FailOrphanedJobs(context.Background())
+	require.NoError(t, err)
+
+	queries := rec.Queries()
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "UPDATE sync_jobs")
+	assert.Contains(t, queries[0], "WHERE status = $2")
+	assert.Contains(t, queries[0], "finished_at = NOW()")
+}
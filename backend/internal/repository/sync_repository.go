@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/seeds-metrics/analytics-backend/internal/models"
+	"github.com/seeds-metrics/analytics-backend/pkg/database"
+)
+
+// Sync run statuses stored in sync_runs.status
+const (
+	SyncRunRunning   = "running"
+	SyncRunCompleted = "completed"
+	SyncRunFailed    = "failed"
+)
+
+// Sync job statuses stored in sync_jobs.status
+const (
+	SyncJobRunning = "running"
+	SyncJobDone    = "done"
+	SyncJobFailed  = "failed"
+)
+
+type SyncRepository struct {
+	db *database.DB
+}
+
+func NewSyncRepository(db *database.DB) *SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// StartRun inserts a new sync run in the running state and returns its id
+func (r *SyncRepository) StartRun(ctx context.Context, syncType string, scope string) (int64, error) {
+	query := `
+		INSERT INTO sync_runs (sync_type, scope, status, started_at)
+		VALUES ($1, NULLIF($2, ''), $3, NOW())
+		RETURNING run_id
+	`
+
+	var runID int64
+	if err := r.db.QueryRowContext(ctx, query, syncType, scope, SyncRunRunning).Scan(&runID); err != nil {
+		return 0, fmt.Errorf("failed to start sync run: %w", err)
+	}
+
+	return runID, nil
+}
+
+// FinishRun records the final counts and status of a sync run
+func (r *SyncRepository) FinishRun(ctx context.Context, runID int64, status string, totalSynced, totalErrors int) error {
+	query := `
+		UPDATE sync_runs
+		SET status = $2, total_synced = $3, total_errors = $4, finished_at = NOW()
+		WHERE run_id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, runID, status, totalSynced, totalErrors); err != nil {
+		return fmt.Errorf("failed to finish sync run: %w", err)
+	}
+
+	return nil
+}
+
+// RecordError stores a single failed record against a sync run. attempts is
+// how many times the record was tried before giving up, including tries made
+// by earlier runs when the record is being retried.
+func (r *SyncRepository) RecordError(ctx context.Context, runID int64, entityType, recordID, loanID, message string, attempts int) error {
+	query := `
+		INSERT INTO sync_errors (run_id, entity_type, record_id, loan_id, error_message, attempts, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, NOW())
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, runID, entityType, recordID, loanID, message, attempts); err != nil {
+		return fmt.Errorf("failed to record sync error: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingErrors retrieves the dead-letter records that no retry run has
+// picked up yet and that are not marked dead, oldest first and at most limit
+// of them. A record that failed in several runs is returned once, with its
+// latest error and the total attempts made. Errors without a record id, and
+// repayment errors without a loan id, cannot be refetched from Django and are
+// left out.
+func (r *SyncRepository) GetPendingErrors(ctx context.Context, limit int) ([]*models.SyncError, error) {
+	query := `
+		WITH pending AS (
+			SELECT DISTINCT ON (entity_type, record_id)
+				error_id, run_id, entity_type, record_id, loan_id, error_message,
+				SUM(attempts) OVER (PARTITION BY entity_type, record_id) AS attempts,
+				retried_at, resolved, dead, created_at
+			FROM sync_errors
+			WHERE retried_at IS NULL
+				AND NOT dead
+				AND record_id IS NOT NULL
+				AND (entity_type <> 'repayment' OR loan_id IS NOT NULL)
+			ORDER BY entity_type, record_id, error_id DESC
+		)
+		SELECT error_id, run_id, entity_type, record_id, loan_id, error_message,
+			attempts, retried_at, resolved, dead, created_at
+		FROM pending
+		ORDER BY error_id
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSyncErrors(rows)
+}
+
+// MarkErrorsRetried stamps every pending error of a record as picked up by a
+// retry run, and records whether the retry succeeded.
+func (r *SyncRepository) MarkErrorsRetried(ctx context.Context, entityType, recordID string, resolved bool) error {
+	query := `
+		UPDATE sync_errors
+		SET retried_at = NOW(), resolved = $3
+		WHERE entity_type = $1 AND record_id = $2 AND retried_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, entityType, recordID, resolved); err != nil {
+		return fmt.Errorf("failed to mark sync errors retried: %w", err)
+	}
+
+	return nil
+}
+
+// MarkErrorsDead marks the pending errors of a record dead, so retry runs
+// stop picking it up.
+func (r *SyncRepository) MarkErrorsDead(ctx context.Context, entityType, recordID string) error {
+	query := `
+		UPDATE sync_errors
+		SET dead = TRUE
+		WHERE entity_type = $1 AND record_id = $2 AND retried_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, entityType, recordID); err != nil {
+		return fmt.Errorf("failed to mark sync errors dead: %w", err)
+	}
+
+	return nil
+}
+
+// GetRun retrieves a sync run by id. When runID is 0 the most recent run is
+// returned. Returns ErrNotFound if there is no matching run.
+func (r *SyncRepository) GetRun(ctx context.Context, runID int64) (*models.SyncRun, error) {
+	query := `
+		SELECT run_id, sync_type, scope, status, total_synced, total_errors, started_at, finished_at
+		FROM sync_runs
+	`
+	args := []interface{}{}
+	if runID > 0 {
+		query += " WHERE run_id = $1"
+		args = append(args, runID)
+	} else {
+		query += " ORDER BY started_at DESC, run_id DESC LIMIT 1"
+	}
+
+	run := &models.SyncRun{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&run.RunID,
+		&run.SyncType,
+		&run.Scope,
+		&run.Status,
+		&run.TotalSynced,
+		&run.TotalErrors,
+		&run.StartedAt,
+		&run.FinishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: sync run %d", ErrNotFound, runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// GetErrors retrieves the failed records of a sync run in the order they occurred
+func (r *SyncRepository) GetErrors(ctx context.Context, runID int64) ([]*models.SyncError, error) {
+	query := `
+		SELECT error_id, run_id, entity_type, record_id, loan_id, error_message,
+			attempts, retried_at, resolved, dead, created_at
+		FROM sync_errors
+		WHERE run_id = $1
+		ORDER BY error_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSyncErrors(rows)
+}
+
+// scanSyncErrors reads sync_errors rows selected in the column order used by
+// GetErrors and GetPendingErrors.
+func scanSyncErrors(rows *sql.Rows) ([]*models.SyncError, error) {
+	syncErrors := []*models.SyncError{}
+	for rows.Next() {
+		se := &models.SyncError{}
+		if err := rows.Scan(
+			&se.ErrorID,
+			&se.RunID,
+			&se.EntityType,
+			&se.RecordID,
+			&se.LoanID,
+			&se.ErrorMessage,
+			&se.Attempts,
+			&se.RetriedAt,
+			&se.Resolved,
+			&se.Dead,
+			&se.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		syncErrors = append(syncErrors, se)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return syncErrors, nil
+}
+
+// CreateJob inserts a new background sync job in the running state
+func (r *SyncRepository) CreateJob(ctx context.Context, jobID, jobType string, totalRecords int) error {
+	query := `
+		INSERT INTO sync_jobs (job_id, job_type, status, total_records, started_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, jobID, jobType, SyncJobRunning, totalRecords); err != nil {
+		return fmt.Errorf("failed to create sync job: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateJob stores the progress and running counts of a sync job. When status
+// is not SyncJobRunning the job is also marked finished.
+func (r *SyncRepository) UpdateJob(ctx context.Context, job *models.SyncJob) error {
+	query := `
+		UPDATE sync_jobs
+		SET status = $2,
+			processed_records = $3,
+			loans_inserted = $4,
+			loans_failed = $5,
+			repayments_inserted = $6,
+			repayments_failed = $7,
+			error_message = $8,
+			result = $10,
+			updated_at = NOW(),
+			finished_at = CASE WHEN $9 THEN NOW() ELSE NULL END
+		WHERE job_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.JobID,
+		job.Status,
+		job.ProcessedRecords,
+		job.LoansInserted,
+		job.LoansFailed,
+		job.RepaymentsInserted,
+		job.RepaymentsFailed,
+		job.ErrorMessage,
+		job.Status != SyncJobRunning,
+		nullableJSON(job.Result),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update sync job: %w", err)
+	}
+
+	return nil
+}
+
+// FailOrphanedJobs marks every job still running as failed. Jobs run in the
+// API process, so at startup any running job was cut off by a restart and
+// would otherwise stay running forever. Returns the number of jobs marked.
+func (r *SyncRepository) FailOrphanedJobs(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE sync_jobs
+		SET status = $1,
+			error_message = COALESCE(error_message, 'interrupted by a server restart'),
+			updated_at = NOW(),
+			finished_at = NOW()
+		WHERE status = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, SyncJobFailed, SyncJobRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail orphaned sync jobs: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetJob retrieves a sync job by id. Returns ErrNotFound if there is none.
+func (r *SyncRepository) GetJob(ctx context.Context, jobID string) (*models.SyncJob, error) {
+	query := `
+		SELECT job_id, job_type, status, total_records, processed_records,
+			loans_inserted, loans_failed, repayments_inserted, repayments_failed,
+			error_message, result, started_at, updated_at, finished_at
+		FROM sync_jobs
+		WHERE job_id = $1
+	`
+
+	job := &models.SyncJob{}
+	err := r.db.QueryRowContext(ctx, query, jobID).Scan(
+		&job.JobID,
+		&job.JobType,
+		&job.Status,
+		&job.TotalRecords,
+		&job.ProcessedRecords,
+		&job.LoansInserted,
+		&job.LoansFailed,
+		&job.RepaymentsInserted,
+		&job.RepaymentsFailed,
+		&job.ErrorMessage,
+		&job.Result,
+		&job.StartedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: sync job %s", ErrNotFound, jobID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// nullableJSON stores an empty JSON document as NULL rather than as invalid
+// JSONB input.
+func nullableJSON(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoanTermDistributionScopesAndBuckets checks that the tenor buckets
+// cover open loans unless include_closed is set, put non-positive terms with
+// the missing ones, and keep the amounts exact.
+func TestLoanTermDistributionScopesAndBuckets(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = func(string) ([]string, [][]driver.Value) {
+		return []string{"bucket", "loans_count", "principal_outstanding", "overdue_15d", "overdue_15d_count"},
+			[][]driver.Value{
+				{int64(0), int64(2), "200.10", "100.05", int64(1)},
+				{int64(-1), int64(1), "50.00", "0", int64(0)},
+			}
+	}
+	repo := NewDashboardRepository(db, config.DashboardConfig{
+		OpenDjangoStatuses:  []string{"OPEN"},
+		LoanTermBucketEdges: []int{30, 90},
+	})
+
+	buckets, err := repo.GetLoanTermDistribution(map[string]interface{}{})
+	require.NoError(t, err)
+	require.Len(t, buckets, 4)
+	assert.Equal(t, "200.1", buckets[0].PrincipalOutstanding.String())
+	assert.Equal(t, "100.05", buckets[0].Overdue15d.String())
+	assert.Equal(t, MissingValueSentinel, buckets[3].Bucket)
+	assert.Equal(t, 1, buckets[3].LoansCount)
+
+	queries := rec.Queries()
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "l.loan_term_days IS NULL OR l.loan_term_days <= 0 THEN -1")
+	assert.Contains(t, queries[0], repo.openStatusFilter())
+
+	rec.Reset()
+	_, err = repo.GetLoanTermDistribution(map[string]interface{}{"include_closed": true})
+	require.NoError(t, err)
+	assert.NotContains(t, rec.Queries()[0], repo.openStatusFilter())
+}
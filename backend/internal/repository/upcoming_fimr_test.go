@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpcomingFIMRInstallmentUsesDailyRepayment checks that the first
+// installment is the same daily repayment used everywhere else, not just the
+// principal share.
+func TestUpcomingFIMRInstallmentUsesDailyRepayment(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.rows = countOnlyRows(0)
+	repo := NewDashboardRepository(db, config.DashboardConfig{DailyRepaymentFallback: true})
+
+	_, _, err := repo.GetUpcomingFIMRLoans(map[string]interface{}{}, 7)
+	require.NoError(t, err)
+
+	queries := rec.Queries()
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[1], "COALESCE("+repo.dailyRepaymentSQL()+", 0)::float AS amount_due_1st_installment")
+	assert.NotContains(t, queries[1], "l.loan_amount / l.loan_term_days")
+}
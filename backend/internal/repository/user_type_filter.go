@@ -0,0 +1,67 @@
+package repository
+
+import "strings"
+
+// dashboardUserTypes lists the officer user types whose loans feed the
+// dashboard metrics.
+const dashboardUserTypes = "'AGENT', 'AJO_AGENT', 'DMO_AGENT', 'MERCHANT', 'MERCHANT_AGENT', 'MICRO_SAVER', 'PERSONAL', 'PROSPER_AGENT', 'STAFF_AGENT'"
+
+// userTypeFilterSQL returns the standard officer user_type restriction over
+// officers aliased o. Officers with no user_type are counted as well unless
+// includeNull is false.
+func userTypeFilterSQL(includeNull bool) string {
+	if includeNull {
+		return "(o.user_type IN (" + dashboardUserTypes + ") OR o.user_type IS NULL)"
+	}
+	return "(o.user_type IN (" + dashboardUserTypes + "))"
+}
+
+// excludedOfficersSQL returns a NOT IN restriction of column (o.officer_id,
+// or l.officer_id for queries without an officers join) to officers outside
+// the configured exclusion list, or "" when the list is empty.
+func excludedOfficersSQL(column string, officerIDs []string) string {
+	list := officerIDListSQL(officerIDs)
+	if list == "" {
+		return ""
+	}
+	return column + " NOT IN (" + list + ")"
+}
+
+// officerScopeSQL returns the standard officer restriction for officers
+// aliased o: the user_type filter plus the excluded officer list.
+func officerScopeSQL(includeNullUserType bool, excludedOfficerIDs []string) string {
+	excluded := excludedOfficersSQL("o.officer_id", excludedOfficerIDs)
+	if excluded == "" {
+		return userTypeFilterSQL(includeNullUserType)
+	}
+	return "(" + userTypeFilterSQL(includeNullUserType) + " AND " + excluded + ")"
+}
+
+// defaultOpenDjangoStatuses is the open/active django_status set used when
+// none is configured.
+var defaultOpenDjangoStatuses = []string{"OPEN", "PAST_MATURITY"}
+
+// openDjangoStatusSQL returns the restriction over loans aliased l to the
+// django_status values that count as open (active) loans.
+func openDjangoStatusSQL(statuses []string) string {
+	list := officerIDListSQL(statuses)
+	if list == "" {
+		list = officerIDListSQL(defaultOpenDjangoStatuses)
+	}
+	return "l.django_status IN (" + list + ")"
+}
+
+// officerIDListSQL renders officer IDs (or other configured values) as a
+// quoted SQL literal list. The values come from configuration, not requests,
+// but quotes are still escaped.
+func officerIDListSQL(officerIDs []string) string {
+	quoted := []string{}
+	for _, id := range officerIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(id, "'", "''")+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/seeds-metrics/analytics-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserTypeFilterScope checks that the user_type loan filter narrows the
+// status breakdown it was added for, but not the daily collections chart.
+func TestUserTypeFilterScope(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	repo := NewDashboardRepository(db, config.DashboardConfig{})
+	filters := map[string]interface{}{"user_type": "MERCHANT", "period": "today"}
+
+	_, err := repo.GetDailyCollections(filters)
+	require.NoError(t, err)
+	for _, q := range rec.Queries() {
+		assert.NotContains(t, q, "o.user_type = $")
+	}
+
+	rec.Reset()
+	_, _, err = repo.GetLoanStatusCounts(filters)
+	require.NoError(t, err)
+	require.Len(t, rec.Queries(), 1)
+	assert.Contains(t, rec.Queries()[0], "o.user_type = $")
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// wavePattern matches the spellings of a wave label seen upstream, e.g.
+// "Wave 2", "wave2", "WAVE_2" or "wave-2".
+var wavePattern = regexp.MustCompile(`(?i)^wave[\s_-]*([0-9]+)$`)
+
+// NormalizeWave returns the canonical "Wave <n>" form of a wave label. Labels
+// that don't look like a numbered wave are only trimmed. It mirrors the
+// normalize_wave() SQL function (migration 044).
+func NormalizeWave(wave string) string {
+	wave = strings.TrimSpace(wave)
+	m := wavePattern.FindStringSubmatch(wave)
+	if m == nil {
+		return wave
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return wave
+	}
+	return "Wave " + strconv.Itoa(n)
+}
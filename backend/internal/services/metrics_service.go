@@ -1,17 +1,118 @@
 package services
 
 import (
+	"fmt"
 	"math"
+	"strings"
 
+	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/models"
 )
 
+// At-risk officer rules (DashboardConfig.AtRiskOfficerRule).
+const (
+	AtRiskRuleActivity = "activity"
+	AtRiskRuleRiskBand = "risk_band"
+	AtRiskRulePAR15    = "par15"
+)
+
 // MetricsService handles metric calculations
-type MetricsService struct{}
+type MetricsService struct {
+	cfg         config.DashboardConfig
+	riskWeights models.RiskWeights
+}
 
 // NewMetricsService creates a new metrics service
-func NewMetricsService() *MetricsService {
-	return &MetricsService{}
+func NewMetricsService(cfg config.DashboardConfig) *MetricsService {
+	return &MetricsService{cfg: cfg, riskWeights: DefaultRiskWeights()}
+}
+
+// DefaultRiskWeights returns the risk score penalty weights behind every
+// reported risk score and band.
+func DefaultRiskWeights() models.RiskWeights {
+	return models.RiskWeights{
+		PORR:               0.20,
+		FIMR:               0.15,
+		Roll:               0.10,
+		RepaymentDelayRate: 0.40,
+		AYR:                0.15,
+	}
+}
+
+// ResolveRiskWeights applies override on top of DefaultRiskWeights. Each
+// weight must be between 0 and 1.
+func ResolveRiskWeights(override *models.RiskWeightsOverride) (models.RiskWeights, error) {
+	w := DefaultRiskWeights()
+	if override == nil {
+		return w, nil
+	}
+	for _, o := range []struct {
+		name  string
+		value *float64
+		dst   *float64
+	}{
+		{"porr", override.PORR, &w.PORR},
+		{"fimr", override.FIMR, &w.FIMR},
+		{"roll", override.Roll, &w.Roll},
+		{"repayment_delay_rate", override.RepaymentDelayRate, &w.RepaymentDelayRate},
+		{"ayr", override.AYR, &w.AYR},
+	} {
+		if o.value == nil {
+			continue
+		}
+		if *o.value < 0 || *o.value > 1 || math.IsNaN(*o.value) {
+			return w, fmt.Errorf("%s weight must be between 0 and 1", o.name)
+		}
+		*o.dst = *o.value
+	}
+	return w, nil
+}
+
+// WithRiskWeights returns a copy of the service that scores risk with
+// weights instead of the defaults, for what-if analysis. The receiver is
+// unchanged.
+func (s *MetricsService) WithRiskWeights(weights models.RiskWeights) *MetricsService {
+	clone := *s
+	clone.riskWeights = weights
+	return &clone
+}
+
+// atRiskRule returns the configured at-risk officer rule, falling back to
+// AtRiskRuleActivity for unknown values.
+func (s *MetricsService) atRiskRule() string {
+	switch rule := strings.ToLower(strings.TrimSpace(s.cfg.AtRiskOfficerRule)); rule {
+	case AtRiskRuleRiskBand, AtRiskRulePAR15:
+		return rule
+	default:
+		return AtRiskRuleActivity
+	}
+}
+
+// IsAtRiskOfficer reports whether officer is at risk under the configured
+// rule (see DashboardConfig.AtRiskOfficerRule). Officers without calculated
+// metrics are never at risk.
+func (s *MetricsService) IsAtRiskOfficer(officer *models.DashboardOfficerMetrics) bool {
+	calc := officer.CalculatedMetrics
+	if calc == nil {
+		return false
+	}
+
+	switch s.atRiskRule() {
+	case AtRiskRuleRiskBand:
+		band := models.GetRiskBand(calc.RiskScore)
+		for _, atRisk := range s.cfg.AtRiskOfficerBands {
+			if strings.EqualFold(strings.TrimSpace(atRisk), band) {
+				return true
+			}
+		}
+		return false
+	case AtRiskRulePAR15:
+		// PORR is overdue_15d / total portfolio, i.e. the officer's PAR15
+		return calc.PORR > s.cfg.AtRiskOfficerPAR15Threshold
+	default:
+		// avg days since last repayment stands in for DPD
+		return calc.AvgDaysSinceLastRepayment > 10 && calc.AvgLoanAge > 14
+	}
 }
 
 // CalculateOfficerMetrics calculates all metrics for an officer
@@ -19,29 +120,19 @@ func (s *MetricsService) CalculateOfficerMetrics(raw *models.RawMetrics) *models
 	calculated := &models.CalculatedMetrics{}
 
 	// FIMR = firstMiss / disbursed
-	if raw.Disbursed > 0 {
-		calculated.FIMR = float64(raw.FirstMiss) / float64(raw.Disbursed)
-	}
+	calculated.FIMR = SafeDivide(float64(raw.FirstMiss), float64(raw.Disbursed))
 
 	// D0-6 Slippage = dpd1to6Bal / amountDue7d
-	if raw.AmountDue7d > 0 {
-		calculated.Slippage = raw.Dpd1to6Bal / raw.AmountDue7d
-	}
+	calculated.Slippage = SafeDivide(raw.Dpd1to6Bal, raw.AmountDue7d)
 
 	// Roll = movedTo7to30 / prevDpd1to6Bal
-	if raw.PrevDpd1to6Bal > 0 {
-		calculated.Roll = raw.MovedTo7to30 / raw.PrevDpd1to6Bal
-	}
+	calculated.Roll = SafeDivide(raw.MovedTo7to30, raw.PrevDpd1to6Bal)
 
 	// FRR = feesCollected / feesDue
-	if raw.FeesDue > 0 {
-		calculated.FRR = raw.FeesCollected / raw.FeesDue
-	}
+	calculated.FRR = SafeDivide(raw.FeesCollected, raw.FeesDue)
 
 	// AYR = (interestCollected + feesCollected) / par15MidMonth
-	if raw.Par15MidMonth > 0 {
-		calculated.AYR = (raw.InterestCollected + raw.FeesCollected) / raw.Par15MidMonth
-	}
+	calculated.AYR = SafeDivide(raw.InterestCollected+raw.FeesCollected, raw.Par15MidMonth)
 
 	// Yield = interestCollected + feesCollected
 	calculated.Yield = raw.InterestCollected + raw.FeesCollected
@@ -50,9 +141,7 @@ func (s *MetricsService) CalculateOfficerMetrics(raw *models.RawMetrics) *models
 	calculated.Overdue15dVolume = raw.Overdue15d
 
 	// PORR = overdue15d / totalPortfolio
-	if raw.TotalPortfolio > 0 {
-		calculated.PORR = raw.Overdue15d / raw.TotalPortfolio
-	}
+	calculated.PORR = SafeDivide(raw.Overdue15d, raw.TotalPortfolio)
 
 	// Channel Purity (simplified - assume 1.0 for now, should be calculated from actual data)
 	calculated.ChannelPurity = 1.0
@@ -84,7 +173,7 @@ func (s *MetricsService) CalculateOfficerMetrics(raw *models.RawMetrics) *models
 	// - If avg_loan_age = 0, return 0 (NULL would be better but we'll use 0 for simplicity)
 	// - Allow negative values (as per user requirement)
 	if raw.AvgLoanAge > 0 {
-		ratio := raw.AvgDaysSinceLastRepayment / raw.AvgLoanAge
+		ratio := SafeDivide(raw.AvgDaysSinceLastRepayment, raw.AvgLoanAge)
 		normalizedRatio := ratio / 0.25
 		calculated.RepaymentDelayRate = (1.0 - normalizedRatio) * 100
 	} else {
@@ -97,7 +186,7 @@ func (s *MetricsService) CalculateOfficerMetrics(raw *models.RawMetrics) *models
 // CalculateRiskScoreNorm calculates normalized risk score (0-1)
 func (s *MetricsService) CalculateRiskScoreNorm(raw *models.RawMetrics, calc *models.CalculatedMetrics) float64 {
 	// NEW Risk Score Formula = 1 - (weighted penalties)
-	// Penalties:
+	// Penalties (with DefaultRiskWeights):
 	// - PORR: 20 points max (0.20 weight)
 	// - FIMR: 15 points max (0.15 weight)
 	// - Roll: 10 points max (0.10 weight)
@@ -105,16 +194,43 @@ func (s *MetricsService) CalculateRiskScoreNorm(raw *models.RawMetrics, calc *mo
 	// - AYR: 15 points max (0.15 weight)
 	// Total: 100 points max
 
-	score := 1.0
+	p := riskScorePenalties(calc, s.riskWeights)
+	score := 1.0 - p.PORR - p.FIMR - p.Roll - p.RepaymentDelayRate - p.AYR
+
+	// Ensure score is between 0 and 1
+	if score < 0 {
+		score = 0
+	}
+	if score > 1 {
+		score = 1
+	}
+
+	return score
+}
+
+// riskPenalties holds the weighted penalties CalculateRiskScoreNorm
+// subtracts from a perfect score of 1.
+type riskPenalties struct {
+	PORR               float64
+	FIMR               float64
+	Roll               float64
+	RepaymentDelayRate float64
+	AYR                float64
+}
+
+// riskScorePenalties computes each risk score penalty from the calculated
+// metrics. The comments give the maxima under DefaultRiskWeights.
+func riskScorePenalties(calc *models.CalculatedMetrics, w models.RiskWeights) riskPenalties {
+	p := riskPenalties{}
 
 	// PORR penalty (max: 20 points = 0.20 weight)
-	score -= calc.PORR * 0.20
+	p.PORR = calc.PORR * w.PORR
 
 	// FIMR penalty (max: 15 points = 0.15 weight)
-	score -= calc.FIMR * 0.15
+	p.FIMR = calc.FIMR * w.FIMR
 
 	// Roll penalty (max: 10 points = 0.10 weight)
-	score -= calc.Roll * 0.10
+	p.Roll = calc.Roll * w.Roll
 
 	// Repayment Delay Rate penalty (max: 40 points = 0.40 weight)
 	// Formula: penalty = (1 - (repayment_delay_rate / 100)) * 0.40
@@ -122,12 +238,12 @@ func (s *MetricsService) CalculateRiskScoreNorm(raw *models.RawMetrics, calc *mo
 	// If repayment_delay_rate = 0%, penalty = 0.40
 	// If repayment_delay_rate is negative, penalty > 0.40 (capped at 0.40)
 	if calc.RepaymentDelayRate <= 100 {
-		delayRatePenalty := (1.0 - (calc.RepaymentDelayRate / 100.0)) * 0.40
-		// Cap penalty at 0.40 (for negative delay rates)
-		if delayRatePenalty > 0.40 {
-			delayRatePenalty = 0.40
+		delayRatePenalty := (1.0 - (calc.RepaymentDelayRate / 100.0)) * w.RepaymentDelayRate
+		// Cap penalty at the weight (for negative delay rates)
+		if delayRatePenalty > w.RepaymentDelayRate {
+			delayRatePenalty = w.RepaymentDelayRate
 		}
-		score -= delayRatePenalty
+		p.RepaymentDelayRate = delayRatePenalty
 	}
 	// If repayment_delay_rate > 100%, no penalty (better than expected)
 
@@ -140,17 +256,9 @@ func (s *MetricsService) CalculateRiskScoreNorm(raw *models.RawMetrics, calc *mo
 	if ayrCapped > 1.0 {
 		ayrCapped = 1.0
 	}
-	score -= (1.0 - ayrCapped) * 0.15
-
-	// Ensure score is between 0 and 1
-	if score < 0 {
-		score = 0
-	}
-	if score > 1 {
-		score = 1
-	}
+	p.AYR = (1.0 - ayrCapped) * w.AYR
 
-	return score
+	return p
 }
 
 // CalculateDQI calculates Data Quality Index (0-100)
@@ -160,16 +268,8 @@ func (s *MetricsService) CalculateDQI(raw *models.RawMetrics, calc *models.Calcu
 	// Negative indicators: FIMR
 	// Note: Channel Purity removed as it's no longer part of Risk Score
 
-	dqi := 0.0
-
-	// Risk Score contribution (weight: 0.50 - increased from 0.40)
-	dqi += calc.RiskScoreNorm * 0.50
-
-	// On-time rate contribution (weight: 0.35 - increased from 0.30)
-	dqi += calc.OnTimeRate * 0.35
-
-	// FIMR penalty (weight: 0.15 - increased from 0.10)
-	dqi += (1.0 - calc.FIMR) * 0.15
+	riskTerm, onTimeTerm, fimrTerm := dqiTerms(calc)
+	dqi := riskTerm + onTimeTerm + fimrTerm
 
 	// Convert to 0-100 scale
 	dqiScore := int(dqi * 100)
@@ -185,10 +285,168 @@ func (s *MetricsService) CalculateDQI(raw *models.RawMetrics, calc *models.Calcu
 	return dqiScore
 }
 
+// dqiTerms returns the weighted contributions CalculateDQI sums: risk score,
+// on-time rate and FIMR.
+func dqiTerms(calc *models.CalculatedMetrics) (riskScore, onTimeRate, fimr float64) {
+	// Risk Score contribution (weight: 0.50 - increased from 0.40)
+	riskScore = calc.RiskScoreNorm * 0.50
+
+	// On-time rate contribution (weight: 0.35 - increased from 0.30)
+	onTimeRate = calc.OnTimeRate * 0.35
+
+	// FIMR penalty (weight: 0.15 - increased from 0.10)
+	fimr = (1.0 - calc.FIMR) * 0.15
+
+	return riskScore, onTimeRate, fimr
+}
+
+// CalculateOfficerMetricsBreakdown calculates an officer's metrics like
+// CalculateOfficerMetrics and also explains each one: its formula, the raw
+// inputs it used and any intermediate terms (e.g. the risk score penalties),
+// so a disputed risk band can be traced back to the data.
+func (s *MetricsService) CalculateOfficerMetricsBreakdown(raw *models.RawMetrics) (*models.CalculatedMetrics, []*models.MetricBreakdown) {
+	calc := s.CalculateOfficerMetrics(raw)
+	penalties := riskScorePenalties(calc, s.riskWeights)
+	dqiRisk, dqiOnTime, dqiFIMR := dqiTerms(calc)
+
+	breakdown := []*models.MetricBreakdown{
+		{
+			Metric:  "fimr",
+			Value:   calc.FIMR,
+			Formula: "firstMiss / disbursed",
+			Inputs:  map[string]float64{"firstMiss": float64(raw.FirstMiss), "disbursed": float64(raw.Disbursed)},
+		},
+		{
+			Metric:  "slippage",
+			Value:   calc.Slippage,
+			Formula: "dpd1to6Bal / amountDue7d",
+			Inputs:  map[string]float64{"dpd1to6Bal": raw.Dpd1to6Bal, "amountDue7d": raw.AmountDue7d},
+		},
+		{
+			Metric:  "roll",
+			Value:   calc.Roll,
+			Formula: "movedTo7to30 / prevDpd1to6Bal",
+			Inputs:  map[string]float64{"movedTo7to30": raw.MovedTo7to30, "prevDpd1to6Bal": raw.PrevDpd1to6Bal},
+		},
+		{
+			Metric:  "frr",
+			Value:   calc.FRR,
+			Formula: "feesCollected / feesDue",
+			Inputs:  map[string]float64{"feesCollected": raw.FeesCollected, "feesDue": raw.FeesDue},
+		},
+		{
+			Metric:  "ayr",
+			Value:   calc.AYR,
+			Formula: "(interestCollected + feesCollected) / par15MidMonth",
+			Inputs: map[string]float64{
+				"interestCollected": raw.InterestCollected,
+				"feesCollected":     raw.FeesCollected,
+				"par15MidMonth":     raw.Par15MidMonth,
+			},
+			Terms: map[string]float64{"yield": calc.Yield},
+		},
+		{
+			Metric:  "yield",
+			Value:   calc.Yield,
+			Formula: "interestCollected + feesCollected",
+			Inputs:  map[string]float64{"interestCollected": raw.InterestCollected, "feesCollected": raw.FeesCollected},
+		},
+		{
+			Metric:  "porr",
+			Value:   calc.PORR,
+			Formula: "overdue15d / totalPortfolio",
+			Inputs:  map[string]float64{"overdue15d": raw.Overdue15d, "totalPortfolio": raw.TotalPortfolio},
+		},
+		{
+			Metric:  "onTimeRate",
+			Value:   calc.OnTimeRate,
+			Formula: "max(0, 1 - slippage)",
+			Inputs:  map[string]float64{"slippage": calc.Slippage},
+		},
+		{
+			Metric:  "repaymentDelayRate",
+			Value:   calc.RepaymentDelayRate,
+			Formula: "(1 - ((avgDaysSinceLastRepayment / avgLoanAge) / 0.25)) * 100, or 0 when avgLoanAge is 0",
+			Inputs: map[string]float64{
+				"avgDaysSinceLastRepayment": raw.AvgDaysSinceLastRepayment,
+				"avgLoanAge":                raw.AvgLoanAge,
+			},
+			Terms: map[string]float64{"ratio": SafeDivide(raw.AvgDaysSinceLastRepayment, raw.AvgLoanAge)},
+		},
+		{
+			Metric:  "riskScoreNorm",
+			Value:   calc.RiskScoreNorm,
+			Formula: "clamp(1 - porrPenalty - fimrPenalty - rollPenalty - repaymentDelayRatePenalty - ayrPenalty, 0, 1)",
+			Inputs: map[string]float64{
+				"porr":               calc.PORR,
+				"fimr":               calc.FIMR,
+				"roll":               calc.Roll,
+				"repaymentDelayRate": calc.RepaymentDelayRate,
+				"ayr":                calc.AYR,
+			},
+			Terms: map[string]float64{
+				"porrPenalty":               penalties.PORR,
+				"fimrPenalty":               penalties.FIMR,
+				"rollPenalty":               penalties.Roll,
+				"repaymentDelayRatePenalty": penalties.RepaymentDelayRate,
+				"ayrPenalty":                penalties.AYR,
+			},
+		},
+		{
+			Metric:  "riskScore",
+			Value:   float64(calc.RiskScore),
+			Formula: "int(riskScoreNorm * 100)",
+			Inputs:  map[string]float64{"riskScoreNorm": calc.RiskScoreNorm},
+		},
+		{
+			Metric:  "dqi",
+			Value:   float64(calc.DQI),
+			Formula: "clamp(int((riskScoreNorm * 0.50 + onTimeRate * 0.35 + (1 - fimr) * 0.15) * 100), 0, 100)",
+			Inputs: map[string]float64{
+				"riskScoreNorm": calc.RiskScoreNorm,
+				"onTimeRate":    calc.OnTimeRate,
+				"fimr":          calc.FIMR,
+			},
+			Terms: map[string]float64{
+				"riskScoreTerm":  dqiRisk,
+				"onTimeRateTerm": dqiOnTime,
+				"fimrTerm":       dqiFIMR,
+			},
+		},
+	}
+
+	return calc, breakdown
+}
+
+// SimulateOfficerRisk recalculates officer's metrics under weights and
+// compares them with the metrics under the service's own weights. Nothing is
+// persisted.
+func (s *MetricsService) SimulateOfficerRisk(officer *models.DashboardOfficerMetrics, weights models.RiskWeights) *models.OfficerRiskSimulation {
+	current := s.CalculateOfficerMetrics(officer.RawMetrics)
+	simulated := s.WithRiskWeights(weights).CalculateOfficerMetrics(officer.RawMetrics)
+
+	sim := &models.OfficerRiskSimulation{
+		OfficerID:      officer.OfficerID,
+		Name:           officer.Name,
+		DefaultWeights: s.riskWeights,
+		Weights:        weights,
+		Current: &models.RiskScenario{
+			RiskBand:          models.GetRiskBand(current.RiskScore),
+			CalculatedMetrics: current,
+		},
+		Simulated: &models.RiskScenario{
+			RiskBand:          models.GetRiskBand(simulated.RiskScore),
+			CalculatedMetrics: simulated,
+		},
+	}
+	sim.BandChanged = sim.Current.RiskBand != sim.Simulated.RiskBand
+	return sim
+}
+
 // CalculatePortfolioMetrics calculates portfolio-level metrics from officer metrics
 func (s *MetricsService) CalculatePortfolioMetrics(officers []*models.DashboardOfficerMetrics) *models.PortfolioMetrics {
 	if len(officers) == 0 {
-		return &models.PortfolioMetrics{}
+		return &models.PortfolioMetrics{AtRiskOfficersRule: s.atRiskRule()}
 	}
 
 	portfolio := &models.PortfolioMetrics{
@@ -243,11 +501,7 @@ func (s *MetricsService) CalculatePortfolioMetrics(officers []*models.DashboardO
 				officersWithDelayRate++
 			}
 
-			// Check if officer is at risk (avg DPD > 10 AND avg loan age > 14)
-			// Note: We use AvgDaysSinceLastRepayment as a proxy for DPD and AvgLoanAge from calculated metrics
-			avgLoanAge := officer.CalculatedMetrics.AvgLoanAge
-			avgDaysSinceLastRepayment := officer.CalculatedMetrics.AvgDaysSinceLastRepayment
-			if avgDaysSinceLastRepayment > 10 && avgLoanAge > 14 {
+			if s.IsAtRiskOfficer(officer) {
 				atRiskOfficersCount++
 			}
 		}
@@ -260,7 +514,7 @@ func (s *MetricsService) CalculatePortfolioMetrics(officers []*models.DashboardO
 
 	portfolio.TotalOverdue15d = totalOverdue15d
 	portfolio.AvgDQI = totalDQI / len(officers)
-	portfolio.AvgAYR = totalAYR / float64(len(officers))
+	portfolio.AvgAYR = SafeDivide(totalAYR, float64(len(officers)))
 	portfolio.AvgRiskScore = totalRiskScore / len(officers)
 	portfolio.TopOfficer = topOfficer
 	portfolio.WatchlistCount = watchlistCount
@@ -269,15 +523,12 @@ func (s *MetricsService) CalculatePortfolioMetrics(officers []*models.DashboardO
 	portfolio.TotalPortfolio = totalPortfolio
 
 	// Calculate average repayment delay rate
-	if officersWithDelayRate > 0 {
-		portfolio.AvgRepaymentDelayRate = totalRepaymentDelayRate / float64(officersWithDelayRate)
-	}
+	portfolio.AvgRepaymentDelayRate = SafeDivide(totalRepaymentDelayRate, float64(officersWithDelayRate))
 
-	// Calculate at-risk officers percentage
-	if len(officers) > 0 {
-		portfolio.AtRiskOfficersCount = atRiskOfficersCount
-		portfolio.AtRiskOfficersPercentage = (float64(atRiskOfficersCount) / float64(len(officers))) * 100
-	}
+	// At-risk officers as a share of the officers matching the filters
+	portfolio.AtRiskOfficersRule = s.atRiskRule()
+	portfolio.AtRiskOfficersCount = atRiskOfficersCount
+	portfolio.AtRiskOfficersPercentage = SafeDivide(float64(atRiskOfficersCount), float64(len(officers))) * 100
 
 	return portfolio
 }
@@ -292,6 +543,19 @@ func (s *MetricsService) CalculateRollDirection(currentDPD, previousDPD int) str
 	return "Stable"
 }
 
+// SafeDivide returns a / b, or 0 when b is zero or the result is not finite,
+// so ratios over empty populations never reach JSON as NaN or Inf.
+func SafeDivide(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	q := a / b
+	if math.IsNaN(q) || math.IsInf(q, 0) {
+		return 0
+	}
+	return q
+}
+
 // Round rounds a float to n decimal places
 func Round(val float64, places int) float64 {
 	multiplier := math.Pow(10, float64(places))
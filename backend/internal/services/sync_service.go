@@ -3,9 +3,16 @@ package services
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"time"
 
+	"github.com/lib/pq"
+	"github.com/seeds-metrics/analytics-backend/internal/config"
 	"github.com/seeds-metrics/analytics-backend/internal/models"
 	"github.com/seeds-metrics/analytics-backend/internal/repository"
 	"github.com/seeds-metrics/analytics-backend/pkg/database"
@@ -17,19 +24,150 @@ type SyncService struct {
 	djangoRepo    *repository.DjangoRepository
 	repaymentRepo *repository.RepaymentRepository
 	loanRepo      *repository.LoanRepository
+	syncRepo      *repository.SyncRepository
+
+	repaymentBatchSize int
+	retryAttempts      int
+	retryBackoff       time.Duration
+	errorMaxAttempts   int
 }
 
-// NewSyncService creates a new sync service
-func NewSyncService(djangoDB *sql.DB, seedsDB *database.DB) *SyncService {
+// defaultRepaymentBatchSize is used when NewSyncService is given a
+// non-positive repayment batch size.
+const defaultRepaymentBatchSize = 5000
+
+// defaultSyncErrorMaxAttempts is used when NewSyncService is given a
+// non-positive cfg.SyncErrorMaxAttempts.
+const defaultSyncErrorMaxAttempts = 10
+
+// NewSyncService creates a new sync service. cfg.RepaymentSyncBatchSize is how
+// many repayments SyncNewRepayments fetches and commits per chunk, and
+// cfg.SyncRetryAttempts/SyncRetryBackoff control how transient write failures
+// are retried before a record is recorded in sync_errors. A record is marked
+// dead once its cumulative attempts reach cfg.SyncErrorMaxAttempts.
+func NewSyncService(djangoDB *sql.DB, seedsDB *database.DB, cfg config.ETLConfig) *SyncService {
+	repaymentBatchSize := cfg.RepaymentSyncBatchSize
+	if repaymentBatchSize <= 0 {
+		repaymentBatchSize = defaultRepaymentBatchSize
+	}
+	retryAttempts := cfg.SyncRetryAttempts
+	if retryAttempts <= 0 {
+		retryAttempts = 1
+	}
+	errorMaxAttempts := cfg.SyncErrorMaxAttempts
+	if errorMaxAttempts <= 0 {
+		errorMaxAttempts = defaultSyncErrorMaxAttempts
+	}
 	return &SyncService{
-		djangoRepo:    repository.NewDjangoRepository(djangoDB),
-		repaymentRepo: repository.NewRepaymentRepository(seedsDB),
-		loanRepo:      repository.NewLoanRepository(seedsDB),
+		djangoRepo:         repository.NewDjangoRepository(djangoDB),
+		repaymentRepo:      repository.NewRepaymentRepository(seedsDB),
+		loanRepo:           repository.NewLoanRepository(seedsDB),
+		syncRepo:           repository.NewSyncRepository(seedsDB),
+		repaymentBatchSize: repaymentBatchSize,
+		retryAttempts:      retryAttempts,
+		retryBackoff:       cfg.SyncRetryBackoff,
+		errorMaxAttempts:   errorMaxAttempts,
+	}
+}
+
+// startRun records the start of a sync run. Tracking is best-effort: if the
+// run cannot be recorded the sync still proceeds and 0 is returned, which
+// makes recordError and finishRun no-ops.
+func (s *SyncService) startRun(ctx context.Context, syncType, scope string) int64 {
+	runID, err := s.syncRepo.StartRun(ctx, syncType, scope)
+	if err != nil {
+		log.Printf("⚠️  Failed to record %s sync run: %v", syncType, err)
+		return 0
+	}
+	return runID
+}
+
+// recordError stores a failed record against the current sync run, along
+// with the number of attempts made before giving up on it
+func (s *SyncService) recordError(ctx context.Context, runID int64, entityType, recordID, loanID, message string, attempts int) {
+	if runID == 0 {
+		return
+	}
+	if err := s.syncRepo.RecordError(ctx, runID, entityType, recordID, loanID, message, attempts); err != nil {
+		log.Printf("⚠️  Failed to record sync error for %s %s: %v", entityType, recordID, err)
+	}
+}
+
+// finishRun records the outcome of a sync run
+func (s *SyncService) finishRun(ctx context.Context, runID int64, status string, totalSynced, totalErrors int) {
+	if runID == 0 {
+		return
+	}
+	if err := s.syncRepo.FinishRun(ctx, runID, status, totalSynced, totalErrors); err != nil {
+		log.Printf("⚠️  Failed to finish sync run %d: %v", runID, err)
+	}
+}
+
+// withRetry runs fn until it succeeds, fails with an error that is not
+// transient, or the configured attempts are used up, waiting retryBackoff
+// times the attempt number between tries. It returns the number of attempts
+// made and the last error.
+func (s *SyncService) withRetry(ctx context.Context, fn func() error) (int, error) {
+	attempt := 1
+	for {
+		err := fn()
+		if err == nil || attempt >= s.retryAttempts || !isTransientSyncError(err) {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(s.retryBackoff * time.Duration(attempt)):
+		}
+		attempt++
 	}
 }
 
+// isTransientSyncError reports whether a failed write is worth retrying:
+// dropped or refused connections, deadlocks, serialization failures and
+// server resource exhaustion. Everything else (constraint violations, bad
+// data, missing loans) fails the same way on every attempt.
+func isTransientSyncError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", // connection_exception
+			"40", // transaction_rollback (serialization_failure, deadlock_detected)
+			"53": // insufficient_resources (too_many_connections)
+			return true
+		case "57": // operator_intervention, except statement_timeout cancellations
+			return pqErr.Code != "57014"
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// GetSyncErrors returns a sync run and the records that failed during it.
+// When runID is 0 the most recent run is used.
+func (s *SyncService) GetSyncErrors(ctx context.Context, runID int64) (*models.SyncRun, []*models.SyncError, error) {
+	run, err := s.syncRepo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncErrors, err := s.syncRepo.GetErrors(ctx, run.RunID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return run, syncErrors, nil
+}
+
 // SyncLoanRepaymentsResult contains the result of syncing repayments for a loan
 type SyncLoanRepaymentsResult struct {
+	RunID       int64        `json:"run_id"`
 	LoanID      string       `json:"loan_id"`
 	TotalSynced int          `json:"total_synced"`
 	TotalErrors int          `json:"total_errors"`
@@ -41,18 +179,24 @@ type SyncLoanRepaymentsResult struct {
 func (s *SyncService) SyncLoanRepayments(ctx context.Context, loanID string) (*SyncLoanRepaymentsResult, error) {
 	log.Printf("🔄 Starting repayment sync for loan %s", loanID)
 
+	runID := s.startRun(ctx, "loan_repayments", loanID)
+
 	// Verify loan exists in SeedsMetrics
 	loan, err := s.loanRepo.GetByID(ctx, loanID)
 	if err != nil {
+		s.finishRun(ctx, runID, repository.SyncRunFailed, 0, 0)
 		return nil, fmt.Errorf("failed to get loan: %w", err)
 	}
 	if loan == nil {
+		s.recordError(ctx, runID, "loan", loanID, loanID, "loan not found in SeedsMetrics", 1)
+		s.finishRun(ctx, runID, repository.SyncRunFailed, 0, 1)
 		return nil, fmt.Errorf("loan %s not found", loanID)
 	}
 
 	// Fetch repayments from Django for this specific loan
 	repayments, err := s.djangoRepo.GetRepaymentsByLoanID(ctx, loanID)
 	if err != nil {
+		s.finishRun(ctx, runID, repository.SyncRunFailed, 0, 0)
 		return nil, fmt.Errorf("failed to fetch repayments from Django: %w", err)
 	}
 
@@ -63,41 +207,21 @@ func (s *SyncService) SyncLoanRepayments(ctx context.Context, loanID string) (*S
 
 	// Process each repayment
 	for _, repaymentData := range repayments {
-		// Convert map to RepaymentInput with nil-safe type assertions
-		repaymentID, _ := repaymentData["repayment_id"].(string)
-		loanIDStr, _ := repaymentData["loan_id"].(string)
-		paymentDate, _ := repaymentData["payment_date"].(string)
-		paymentAmount, _ := repaymentData["payment_amount"].(float64)
-		paymentMethod, _ := repaymentData["payment_method"].(string)
-
-		// Skip if essential fields are missing
-		if repaymentID == "" || loanIDStr == "" || paymentDate == "" || paymentAmount <= 0 {
+		input, err := repaymentInputFromDjango(repaymentData)
+		if err != nil {
 			log.Printf("⚠️  Skipping repayment with missing essential fields: %v", repaymentData)
+			repaymentID, _ := repaymentData["repayment_id"].(string)
+			loanIDStr, _ := repaymentData["loan_id"].(string)
+			s.recordError(ctx, runID, "repayment", repaymentID, loanIDStr, err.Error(), 1)
 			errorCount++
 			continue
 		}
 
-		// For Django repayments, we don't have breakdown of principal/interest/fees
-		// So we'll put the full amount as principal_paid and let the triggers calculate
-		input := &models.RepaymentInput{
-			RepaymentID:   repaymentID,
-			LoanID:        loanIDStr,
-			PaymentDate:   paymentDate,
-			PaymentAmount: decimal.NewFromFloat(paymentAmount),
-			PrincipalPaid: decimal.NewFromFloat(paymentAmount), // Full amount as principal
-			InterestPaid:  decimal.Zero,
-			FeesPaid:      decimal.Zero,
-			PenaltyPaid:   decimal.Zero,
-			PaymentMethod: paymentMethod,
-			DPDAtPayment:  0,
-			IsBackdated:   false,
-			IsReversed:    false,
-			WaiverAmount:  decimal.Zero,
-		}
-
 		// Create/update repayment
-		if err := s.repaymentRepo.Create(ctx, input); err != nil {
-			log.Printf("❌ Failed to sync repayment %s: %v", input.RepaymentID, err)
+		attempts, err := s.withRetry(ctx, func() error { return s.repaymentRepo.Create(ctx, input) })
+		if err != nil {
+			log.Printf("❌ Failed to sync repayment %s after %d attempts: %v", input.RepaymentID, attempts, err)
+			s.recordError(ctx, runID, "repayment", input.RepaymentID, input.LoanID, err.Error(), attempts)
 			errorCount++
 		} else {
 			totalSynced++
@@ -106,6 +230,8 @@ func (s *SyncService) SyncLoanRepayments(ctx context.Context, loanID string) (*S
 
 	log.Printf("✅ Repayment sync complete for loan %s: %d successful, %d errors", loanID, totalSynced, errorCount)
 
+	s.finishRun(ctx, runID, repository.SyncRunCompleted, totalSynced, errorCount)
+
 	// Fetch updated loan data
 	updatedLoan, err := s.loanRepo.GetByID(ctx, loanID)
 	if err != nil {
@@ -113,6 +239,7 @@ func (s *SyncService) SyncLoanRepayments(ctx context.Context, loanID string) (*S
 	}
 
 	result := &SyncLoanRepaymentsResult{
+		RunID:       runID,
 		LoanID:      loanID,
 		TotalSynced: totalSynced,
 		TotalErrors: errorCount,
@@ -125,33 +252,45 @@ func (s *SyncService) SyncLoanRepayments(ctx context.Context, loanID string) (*S
 
 // SyncNewRepaymentsResult contains the result of syncing new repayments
 type SyncNewRepaymentsResult struct {
+	RunID         int64  `json:"run_id"`
 	TotalSynced   int    `json:"total_synced"`
 	TotalErrors   int    `json:"total_errors"`
 	LastIDSynced  int64  `json:"last_id_synced"`
 	PreviousMaxID int64  `json:"previous_max_id"`
+	Batches       int    `json:"batches"`
+	BatchSize     int    `json:"batch_size"`
 	Message       string `json:"message"`
 }
 
-// SyncNewRepayments syncs only new repayments from Django that have ID > max existing ID
+// SyncNewRepayments syncs only new repayments from Django that have ID > max existing ID.
+// Repayments are fetched and committed in chunks of the configured batch size.
+// The watermark is the max repayment ID in seedsmetrics, so every committed
+// chunk advances it and a failure part way through only loses the chunk in
+// flight; the next run resumes after the last committed chunk.
 func (s *SyncService) SyncNewRepayments(ctx context.Context) (*SyncNewRepaymentsResult, error) {
 	log.Printf("🔄 Starting incremental repayment sync...")
 
+	runID := s.startRun(ctx, "new_repayments", "")
+
 	// Get the max repayment ID currently in seedsmetrics
 	maxID, err := s.repaymentRepo.GetMaxRepaymentID(ctx)
 	if err != nil {
+		s.finishRun(ctx, runID, repository.SyncRunFailed, 0, 0)
 		return nil, fmt.Errorf("failed to get max repayment ID: %w", err)
 	}
 	log.Printf("📊 Current max repayment ID in seedsmetrics: %d", maxID)
 
 	// Fetch new repayments from Django in batches
-	batchSize := 1000
+	batchSize := s.repaymentBatchSize
 	totalSynced := 0
 	errorCount := 0
+	batches := 0
 	lastIDSynced := maxID
 
 	for {
 		repayments, err := s.djangoRepo.GetRepaymentsAfterID(ctx, lastIDSynced, batchSize)
 		if err != nil {
+			s.finishRun(ctx, runID, repository.SyncRunFailed, totalSynced, errorCount)
 			return nil, fmt.Errorf("failed to fetch new repayments from Django: %w", err)
 		}
 
@@ -161,66 +300,455 @@ func (s *SyncService) SyncNewRepayments(ctx context.Context) (*SyncNewRepayments
 
 		log.Printf("📦 Processing batch of %d new repayments (after ID %d)", len(repayments), lastIDSynced)
 
+		inputs := make([]*models.RepaymentInput, 0, len(repayments))
+		batchErrors := 0
+		batchLastID := lastIDSynced
 		for _, repaymentData := range repayments {
-			repaymentID, _ := repaymentData["repayment_id"].(string)
 			repaymentIDInt, _ := repaymentData["repayment_id_int"].(int64)
-			loanIDStr, _ := repaymentData["loan_id"].(string)
-			paymentDate, _ := repaymentData["payment_date"].(string)
-			paymentAmount, _ := repaymentData["payment_amount"].(float64)
-			paymentMethod, _ := repaymentData["payment_method"].(string)
 
-			// Skip if essential fields are missing
-			if repaymentID == "" || loanIDStr == "" || paymentDate == "" || paymentAmount <= 0 {
-				errorCount++
+			input, err := repaymentInputFromDjango(repaymentData)
+			if err != nil {
+				repaymentID, _ := repaymentData["repayment_id"].(string)
+				loanIDStr, _ := repaymentData["loan_id"].(string)
+				s.recordError(ctx, runID, "repayment", repaymentID, loanIDStr, err.Error(), 1)
+				batchErrors++
 				continue
 			}
 
-			input := &models.RepaymentInput{
-				RepaymentID:   repaymentID,
-				LoanID:        loanIDStr,
-				PaymentDate:   paymentDate,
-				PaymentAmount: decimal.NewFromFloat(paymentAmount),
-				PrincipalPaid: decimal.NewFromFloat(paymentAmount),
-				InterestPaid:  decimal.Zero,
-				FeesPaid:      decimal.Zero,
-				PenaltyPaid:   decimal.Zero,
-				PaymentMethod: paymentMethod,
-				DPDAtPayment:  0,
-				IsBackdated:   false,
-				IsReversed:    false,
-				WaiverAmount:  decimal.Zero,
+			inputs = append(inputs, input)
+
+			// Track the highest ID we've processed
+			if repaymentIDInt > batchLastID {
+				batchLastID = repaymentIDInt
 			}
+		}
 
-			if err := s.repaymentRepo.Create(ctx, input); err != nil {
-				if err.Error() != "loan not found" {
-					log.Printf("❌ Failed to sync repayment %s: %v", input.RepaymentID, err)
+		// The batch is all-or-nothing, so a transient failure of the
+		// transaction itself can safely be retried as a whole
+		var failed map[int]error
+		_, err = s.withRetry(ctx, func() error {
+			var batchErr error
+			failed, batchErr = s.repaymentRepo.CreateBatch(ctx, inputs)
+			return batchErr
+		})
+		if err != nil {
+			log.Printf("❌ Repayment batch after ID %d failed; %d repayments from %d earlier batches are committed: %v", lastIDSynced, totalSynced, batches, err)
+			s.finishRun(ctx, runID, repository.SyncRunFailed, totalSynced, errorCount)
+			return nil, fmt.Errorf("failed to write repayment batch after ID %d: %w", lastIDSynced, err)
+		}
+		for i, err := range failed {
+			input := inputs[i]
+			attempts := 1
+			if isTransientSyncError(err) {
+				// Retry rows that failed transiently on their own, outside the batch
+				var retries int
+				retries, err = s.withRetry(ctx, func() error { return s.repaymentRepo.Create(ctx, input) })
+				attempts += retries
+				if err == nil {
+					delete(failed, i)
+					continue
 				}
-				errorCount++
-			} else {
-				totalSynced++
 			}
-
-			// Track the highest ID we've processed
-			if repaymentIDInt > lastIDSynced {
-				lastIDSynced = repaymentIDInt
+			if err.Error() != "loan not found" {
+				log.Printf("❌ Failed to sync repayment %s after %d attempts: %v", input.RepaymentID, attempts, err)
 			}
+			s.recordError(ctx, runID, "repayment", input.RepaymentID, input.LoanID, err.Error(), attempts)
 		}
 
+		batches++
+		totalSynced += len(inputs) - len(failed)
+		errorCount += batchErrors + len(failed)
+		lastIDSynced = batchLastID
+		log.Printf("💾 Committed batch %d: %d synced so far (up to ID %d)", batches, totalSynced, lastIDSynced)
+
 		// If we got fewer than batchSize, we're done
 		if len(repayments) < batchSize {
 			break
 		}
 	}
 
-	log.Printf("✅ Incremental sync complete: %d synced, %d errors (ID range: %d -> %d)", totalSynced, errorCount, maxID, lastIDSynced)
+	log.Printf("✅ Incremental sync complete: %d synced in %d batches, %d errors (ID range: %d -> %d)", totalSynced, batches, errorCount, maxID, lastIDSynced)
+
+	s.finishRun(ctx, runID, repository.SyncRunCompleted, totalSynced, errorCount)
 
 	result := &SyncNewRepaymentsResult{
+		RunID:         runID,
 		TotalSynced:   totalSynced,
 		TotalErrors:   errorCount,
 		LastIDSynced:  lastIDSynced,
 		PreviousMaxID: maxID,
-		Message:       fmt.Sprintf("Synced %d new repayments (%d errors). ID range: %d -> %d", totalSynced, errorCount, maxID, lastIDSynced),
+		Batches:       batches,
+		BatchSize:     batchSize,
+		Message:       fmt.Sprintf("Synced %d new repayments in %d batches (%d errors). ID range: %d -> %d", totalSynced, batches, errorCount, maxID, lastIDSynced),
+	}
+
+	return result, nil
+}
+
+// ResyncStaleLoansResult contains the result of re-syncing stale loans
+type ResyncStaleLoansResult struct {
+	RunID         int64  `json:"run_id"`
+	OlderThanDays int    `json:"older_than_days"`
+	StaleLoans    int    `json:"stale_loans"`
+	TotalSynced   int    `json:"total_synced"`
+	TotalErrors   int    `json:"total_errors"`
+	Message       string `json:"message"`
+}
+
+// ResyncStaleLoans re-fetches from Django every loan whose last_synced_at is
+// older than olderThanDays and upserts it, picking up changes (e.g. status)
+// that earlier syncs missed.
+func (s *SyncService) ResyncStaleLoans(ctx context.Context, olderThanDays int) (*ResyncStaleLoansResult, error) {
+	log.Printf("🔄 Starting stale loan resync (older than %d days)...", olderThanDays)
+
+	runID := s.startRun(ctx, "stale_loans", fmt.Sprintf("older_than_days=%d", olderThanDays))
+
+	staleIDs, err := s.loanRepo.GetStaleLoanIDs(ctx, olderThanDays)
+	if err != nil {
+		s.finishRun(ctx, runID, repository.SyncRunFailed, 0, 0)
+		return nil, fmt.Errorf("failed to get stale loans: %w", err)
+	}
+	log.Printf("📊 Found %d stale loans", len(staleIDs))
+
+	batchSize := 500
+	totalSynced := 0
+	errorCount := 0
+
+	for start := 0; start < len(staleIDs); start += batchSize {
+		end := start + batchSize
+		if end > len(staleIDs) {
+			end = len(staleIDs)
+		}
+		batch := staleIDs[start:end]
+
+		loans, err := s.djangoRepo.GetLoansByIDs(ctx, batch)
+		if err != nil {
+			s.finishRun(ctx, runID, repository.SyncRunFailed, totalSynced, errorCount)
+			return nil, fmt.Errorf("failed to fetch loans from Django: %w", err)
+		}
+
+		log.Printf("📦 Processing batch of %d stale loans (%d found in Django)", len(batch), len(loans))
+
+		found := make(map[string]bool, len(loans))
+		for _, loanData := range loans {
+			loanID, _ := loanData["loan_id"].(string)
+			found[loanID] = true
+
+			input, err := loanInputFromDjango(loanData)
+			if err != nil {
+				s.recordError(ctx, runID, "loan", loanID, loanID, err.Error(), 1)
+				errorCount++
+				continue
+			}
+
+			attempts, err := s.withRetry(ctx, func() error { return s.loanRepo.Create(ctx, input) })
+			if err != nil {
+				log.Printf("❌ Failed to resync loan %s after %d attempts: %v", loanID, attempts, err)
+				s.recordError(ctx, runID, "loan", loanID, loanID, err.Error(), attempts)
+				errorCount++
+			} else {
+				totalSynced++
+			}
+		}
+
+		for _, loanID := range batch {
+			if !found[loanID] {
+				s.recordError(ctx, runID, "loan", loanID, loanID, "loan not found in Django (or no longer disbursed)", 1)
+				errorCount++
+			}
+		}
+	}
+
+	log.Printf("✅ Stale loan resync complete: %d synced, %d errors", totalSynced, errorCount)
+
+	s.finishRun(ctx, runID, repository.SyncRunCompleted, totalSynced, errorCount)
+
+	result := &ResyncStaleLoansResult{
+		RunID:         runID,
+		OlderThanDays: olderThanDays,
+		StaleLoans:    len(staleIDs),
+		TotalSynced:   totalSynced,
+		TotalErrors:   errorCount,
+		Message:       fmt.Sprintf("Resynced %d of %d stale loans (%d errors)", totalSynced, len(staleIDs), errorCount),
 	}
 
 	return result, nil
 }
+
+// RetrySyncErrorsResult contains the result of reprocessing dead-letter records
+type RetrySyncErrorsResult struct {
+	RunID        int64  `json:"run_id"`
+	Pending      int    `json:"pending"`
+	Resolved     int    `json:"resolved"`
+	StillFailing int    `json:"still_failing"`
+	Dead         int    `json:"dead"`
+	Message      string `json:"message"`
+}
+
+// RetrySyncErrors reprocesses up to limit records left in sync_errors by
+// earlier runs. Each loan or repayment is refetched from Django and written
+// again with the usual retries. Its pending errors are then marked retried,
+// and records that still fail are recorded against this run with their
+// cumulative attempts, so they stay pending for the next retry, unless those
+// attempts reach the configured maximum, in which case they are marked dead.
+func (s *SyncService) RetrySyncErrors(ctx context.Context, limit int) (*RetrySyncErrorsResult, error) {
+	log.Printf("🔄 Starting sync error retry (limit %d)...", limit)
+
+	// Unlike the other syncs the run is required here: without it failures
+	// could not be recorded again and would drop out of the dead-letter queue
+	runID, err := s.syncRepo.StartRun(ctx, "retry_errors", fmt.Sprintf("limit=%d", limit))
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.syncRepo.GetPendingErrors(ctx, limit)
+	if err != nil {
+		s.finishRun(ctx, runID, repository.SyncRunFailed, 0, 0)
+		return nil, fmt.Errorf("failed to get pending sync errors: %w", err)
+	}
+	log.Printf("📊 Found %d pending sync errors", len(pending))
+
+	var loanErrors []*models.SyncError
+	repaymentErrors := make(map[string][]*models.SyncError)
+	var repaymentLoanIDs []string
+	for _, se := range pending {
+		switch se.EntityType {
+		case "loan":
+			loanErrors = append(loanErrors, se)
+		case "repayment":
+			if _, ok := repaymentErrors[*se.LoanID]; !ok {
+				repaymentLoanIDs = append(repaymentLoanIDs, *se.LoanID)
+			}
+			repaymentErrors[*se.LoanID] = append(repaymentErrors[*se.LoanID], se)
+		default:
+			log.Printf("⚠️  Skipping sync error %d with unknown entity type %q", se.ErrorID, se.EntityType)
+		}
+	}
+
+	resolved := 0
+	stillFailing := 0
+	dead := 0
+
+	// settle marks a record's pending errors retried before recording any new
+	// failure, so the new error row is the one left pending
+	settle := func(se *models.SyncError, attempts int, err error) {
+		if markErr := s.syncRepo.MarkErrorsRetried(ctx, se.EntityType, *se.RecordID, err == nil); markErr != nil {
+			log.Printf("⚠️  Failed to mark %s %s retried: %v", se.EntityType, *se.RecordID, markErr)
+		}
+		if err == nil {
+			resolved++
+			return
+		}
+
+		loanID := ""
+		if se.LoanID != nil {
+			loanID = *se.LoanID
+		}
+		log.Printf("❌ Retry of %s %s failed: %v", se.EntityType, *se.RecordID, err)
+		totalAttempts := se.Attempts + attempts
+		s.recordError(ctx, runID, se.EntityType, *se.RecordID, loanID, err.Error(), totalAttempts)
+		if totalAttempts >= s.errorMaxAttempts {
+			log.Printf("🛑 Giving up on %s %s after %d attempts", se.EntityType, *se.RecordID, totalAttempts)
+			if deadErr := s.syncRepo.MarkErrorsDead(ctx, se.EntityType, *se.RecordID); deadErr != nil {
+				log.Printf("⚠️  Failed to mark %s %s dead: %v", se.EntityType, *se.RecordID, deadErr)
+			}
+			dead++
+			return
+		}
+		stillFailing++
+	}
+
+	batchSize := 500
+	for start := 0; start < len(loanErrors); start += batchSize {
+		end := start + batchSize
+		if end > len(loanErrors) {
+			end = len(loanErrors)
+		}
+		batch := loanErrors[start:end]
+
+		ids := make([]string, len(batch))
+		for i, se := range batch {
+			ids[i] = *se.RecordID
+		}
+		loans, err := s.djangoRepo.GetLoansByIDs(ctx, ids)
+		if err != nil {
+			s.finishRun(ctx, runID, repository.SyncRunFailed, resolved, stillFailing+dead)
+			return nil, fmt.Errorf("failed to fetch loans from Django: %w", err)
+		}
+
+		byID := make(map[string]map[string]interface{}, len(loans))
+		for _, loanData := range loans {
+			loanID, _ := loanData["loan_id"].(string)
+			byID[loanID] = loanData
+		}
+
+		for _, se := range batch {
+			loanData, ok := byID[*se.RecordID]
+			if !ok {
+				settle(se, 1, fmt.Errorf("loan not found in Django (or no longer disbursed)"))
+				continue
+			}
+			input, err := loanInputFromDjango(loanData)
+			if err != nil {
+				settle(se, 1, err)
+				continue
+			}
+			attempts, err := s.withRetry(ctx, func() error { return s.loanRepo.Create(ctx, input) })
+			settle(se, attempts, err)
+		}
+	}
+
+	for _, loanID := range repaymentLoanIDs {
+		repayments, err := s.djangoRepo.GetRepaymentsByLoanID(ctx, loanID)
+		if err != nil {
+			s.finishRun(ctx, runID, repository.SyncRunFailed, resolved, stillFailing+dead)
+			return nil, fmt.Errorf("failed to fetch repayments from Django for loan %s: %w", loanID, err)
+		}
+
+		byID := make(map[string]map[string]interface{}, len(repayments))
+		for _, repaymentData := range repayments {
+			repaymentID, _ := repaymentData["repayment_id"].(string)
+			byID[repaymentID] = repaymentData
+		}
+
+		for _, se := range repaymentErrors[loanID] {
+			repaymentData, ok := byID[*se.RecordID]
+			if !ok {
+				settle(se, 1, fmt.Errorf("repayment not found in Django"))
+				continue
+			}
+			input, err := repaymentInputFromDjango(repaymentData)
+			if err != nil {
+				settle(se, 1, err)
+				continue
+			}
+			attempts, err := s.withRetry(ctx, func() error { return s.repaymentRepo.Create(ctx, input) })
+			settle(se, attempts, err)
+		}
+	}
+
+	log.Printf("✅ Sync error retry complete: %d resolved, %d still failing, %d dead", resolved, stillFailing, dead)
+
+	s.finishRun(ctx, runID, repository.SyncRunCompleted, resolved, stillFailing+dead)
+
+	result := &RetrySyncErrorsResult{
+		RunID:        runID,
+		Pending:      len(pending),
+		Resolved:     resolved,
+		StillFailing: stillFailing,
+		Dead:         dead,
+		Message:      fmt.Sprintf("Retried %d failed records: %d resolved, %d still failing, %d dead", len(pending), resolved, stillFailing, dead),
+	}
+
+	return result, nil
+}
+
+// repaymentInputFromDjango converts a repayment map returned by
+// DjangoRepository into a RepaymentInput, using nil-safe type assertions.
+// Django repayments have no principal/interest/fees breakdown, so the full
+// amount goes to principal_paid and the triggers calculate the rest.
+func repaymentInputFromDjango(repaymentData map[string]interface{}) (*models.RepaymentInput, error) {
+	repaymentID, _ := repaymentData["repayment_id"].(string)
+	loanID, _ := repaymentData["loan_id"].(string)
+	paymentDate, _ := repaymentData["payment_date"].(string)
+	paymentAmount, _ := repaymentData["payment_amount"].(float64)
+	paymentMethod, _ := repaymentData["payment_method"].(string)
+
+	if repaymentID == "" || loanID == "" || paymentDate == "" || paymentAmount <= 0 {
+		return nil, fmt.Errorf("missing essential fields (repayment_id, loan_id, payment_date or positive payment_amount)")
+	}
+
+	return &models.RepaymentInput{
+		RepaymentID:   repaymentID,
+		LoanID:        loanID,
+		PaymentDate:   paymentDate,
+		PaymentAmount: decimal.NewFromFloat(paymentAmount),
+		PrincipalPaid: decimal.NewFromFloat(paymentAmount), // Full amount as principal
+		InterestPaid:  decimal.Zero,
+		FeesPaid:      decimal.Zero,
+		PenaltyPaid:   decimal.Zero,
+		PaymentMethod: paymentMethod,
+		DPDAtPayment:  0,
+		IsBackdated:   false,
+		IsReversed:    false,
+		WaiverAmount:  decimal.Zero,
+	}, nil
+}
+
+// loanInputFromDjango converts a loan map returned by DjangoRepository into a
+// LoanInput, using nil-safe type assertions.
+func loanInputFromDjango(loanData map[string]interface{}) (*models.LoanInput, error) {
+	loanID, _ := loanData["loan_id"].(string)
+	customerID, _ := loanData["customer_id"].(string)
+	customerName, _ := loanData["customer_name"].(string)
+	officerID, _ := loanData["officer_id"].(string)
+	officerName, _ := loanData["officer_name"].(string)
+	branch, _ := loanData["branch"].(string)
+	region, _ := loanData["region"].(string)
+	loanAmount, _ := loanData["loan_amount"].(float64)
+	loanTermDays, _ := loanData["loan_term_days"].(int)
+	status, _ := loanData["status"].(string)
+	channel, _ := loanData["channel"].(string)
+	disbursementDate, _ := loanData["disbursement_date"].(string)
+	firstPaymentDueDate, _ := loanData["first_payment_due_date"].(string)
+	maturityDate, _ := loanData["maturity_date"].(string)
+
+	if loanID == "" || customerID == "" || officerID == "" || disbursementDate == "" || maturityDate == "" {
+		return nil, fmt.Errorf("missing essential fields (loan_id, customer_id, officer_id, disbursement_date or maturity_date)")
+	}
+
+	input := &models.LoanInput{
+		LoanID:           loanID,
+		CustomerID:       customerID,
+		CustomerName:     customerName,
+		OfficerID:        officerID,
+		OfficerName:      officerName,
+		Branch:           branch,
+		Region:           region,
+		LoanAmount:       decimal.NewFromFloat(loanAmount),
+		LoanTermDays:     loanTermDays,
+		Status:           status,
+		Channel:          channel,
+		DisbursementDate: disbursementDate,
+		MaturityDate:     maturityDate,
+	}
+
+	// Optional fields
+	if djangoStatus, ok := loanData["django_status"].(string); ok && djangoStatus != "" {
+		input.DjangoStatus = &djangoStatus
+	}
+	if customerPhone, ok := loanData["customer_phone"].(string); ok && customerPhone != "" {
+		input.CustomerPhone = &customerPhone
+	}
+	if officerPhone, ok := loanData["officer_phone"].(string); ok && officerPhone != "" {
+		input.OfficerPhone = &officerPhone
+	}
+	if performanceStatus, ok := loanData["performance_status"].(string); ok && performanceStatus != "" {
+		input.PerformanceStatus = &performanceStatus
+	}
+	if loanType, ok := loanData["loan_type"].(string); ok && loanType != "" {
+		input.LoanType = &loanType
+	}
+	if verificationStatus, ok := loanData["verification_status"].(string); ok && verificationStatus != "" {
+		input.VerificationStatus = &verificationStatus
+	}
+	if firstPaymentDueDate != "" {
+		input.FirstPaymentDueDate = &firstPaymentDueDate
+	}
+
+	// Decimal fields
+	if repaymentAmt, ok := loanData["repayment_amount"].(float64); ok && repaymentAmt > 0 {
+		amt := decimal.NewFromFloat(repaymentAmt)
+		input.RepaymentAmount = &amt
+	}
+	if interestRate, ok := loanData["interest_rate"].(float64); ok && interestRate > 0 {
+		rate := decimal.NewFromFloat(interestRate)
+		input.InterestRate = &rate
+	}
+	if feeAmount, ok := loanData["fee_amount"].(float64); ok && feeAmount > 0 {
+		fee := decimal.NewFromFloat(feeAmount)
+		input.FeeAmount = &fee
+	}
+
+	return input, nil
+}
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- Migration: 041_add_sync_runs_and_errors.sql
+-- Description: Record each sync run and the individual records that failed
+--
+-- Purpose: SyncService used to report only a TotalErrors count. Persisting the
+--          failed record ids and error messages per run lets operators see
+--          which upstream loans/repayments need fixing
+--          (GET /api/v1/sync/errors?run_id=).
+-- ============================================================================
+
+-- One row per sync invocation
+CREATE TABLE IF NOT EXISTS sync_runs (
+    run_id BIGSERIAL PRIMARY KEY,
+    sync_type VARCHAR(50) NOT NULL,            -- 'loan_repayments', 'new_repayments'
+    scope VARCHAR(100),                        -- e.g. the loan_id for a single-loan sync
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- 'running', 'completed', 'failed'
+    total_synced INTEGER NOT NULL DEFAULT 0,
+    total_errors INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_runs_started_at ON sync_runs(started_at DESC);
+
+-- One row per record that failed during a sync run
+CREATE TABLE IF NOT EXISTS sync_errors (
+    error_id BIGSERIAL PRIMARY KEY,
+    run_id BIGINT NOT NULL REFERENCES sync_runs(run_id) ON DELETE CASCADE,
+    entity_type VARCHAR(50) NOT NULL,          -- 'repayment', 'loan'
+    record_id VARCHAR(100),                    -- NULL when the source record had no id
+    loan_id VARCHAR(100),
+    error_message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_errors_run_id ON sync_errors(run_id);
@@ -0,0 +1,19 @@
+-- ============================================================================
+-- Migration: 042_add_last_synced_at_to_loans.sql
+-- Description: Track when each loan was last synced from Django
+--
+-- Purpose: Loans that no sync has touched for a while may have drifted from
+--          Django (e.g. missed status changes). last_synced_at is stamped on
+--          every loan upsert so stale loans can be found and re-synced
+--          (POST /api/v1/sync/loans/stale?older_than_days=).
+-- ============================================================================
+
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS last_synced_at TIMESTAMP;
+
+-- Every upsert so far also bumped updated_at, so it is the best available
+-- approximation of the last sync for existing rows.
+UPDATE loans SET last_synced_at = updated_at WHERE last_synced_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_loans_last_synced_at ON loans(last_synced_at);
+
+COMMENT ON COLUMN loans.last_synced_at IS 'When the loan was last upserted from Django';
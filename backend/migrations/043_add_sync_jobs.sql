@@ -0,0 +1,28 @@
+-- ============================================================================
+-- Migration: 043_add_sync_jobs.sql
+-- Description: Track background ETL batch sync jobs and their progress
+--
+-- Purpose: A large POST /api/v1/etl/sync can run longer than the HTTP request
+--          allows. POST /api/v1/etl/sync/jobs runs the same batch in the
+--          background and returns a job_id; progress and final counts are
+--          persisted here so GET /api/v1/sync/jobs/:job_id can report them
+--          after the originating request has returned.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    job_id UUID PRIMARY KEY,
+    job_type VARCHAR(50) NOT NULL,              -- 'etl_batch'
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- 'running', 'done', 'failed'
+    total_records INTEGER NOT NULL DEFAULT 0,
+    processed_records INTEGER NOT NULL DEFAULT 0,
+    loans_inserted INTEGER NOT NULL DEFAULT 0,
+    loans_failed INTEGER NOT NULL DEFAULT 0,
+    repayments_inserted INTEGER NOT NULL DEFAULT 0,
+    repayments_failed INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,                         -- first failure, or why the job failed
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_started_at ON sync_jobs(started_at DESC);
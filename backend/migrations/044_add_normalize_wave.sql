@@ -0,0 +1,26 @@
+-- ============================================================================
+-- Migration: 044_add_normalize_wave.sql
+-- Description: Canonicalise loans.wave values
+--
+-- Purpose: Wave values arrived as "Wave 2", "wave2", "WAVE_2", ... so exact
+--          wave filters matched different populations. normalize_wave() maps
+--          any "wave<sep><n>" spelling onto "Wave <n>"; existing rows are
+--          rewritten here, new loans are normalised on ingest, and the API
+--          wave filters compare against normalize_wave(<requested value>).
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION normalize_wave(raw TEXT)
+RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN raw IS NULL OR TRIM(raw) = '' THEN NULL
+        WHEN TRIM(raw) ~* '^wave[[:space:]_-]*[0-9]+$'
+            THEN 'Wave ' || (SUBSTRING(TRIM(raw) FROM '[0-9]+$')::INTEGER)::TEXT
+        ELSE TRIM(raw)
+    END;
+$$ LANGUAGE SQL IMMUTABLE;
+
+UPDATE loans
+SET wave = normalize_wave(wave)
+WHERE wave IS DISTINCT FROM normalize_wave(wave);
+
+COMMENT ON FUNCTION normalize_wave(TEXT) IS 'Canonical "Wave <n>" form of a wave label; keep in sync with repository.NormalizeWave';
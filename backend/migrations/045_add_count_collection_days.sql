@@ -0,0 +1,45 @@
+-- ============================================================================
+-- Migration: 045_add_count_collection_days.sql
+-- Description: Exclude company-wide holidays from collection business days
+--
+-- Purpose: Business-day denominators (agent activity repayment rate,
+--          collections progress) counted every Mon-Fri, so holiday weeks
+--          showed artificially low rates. count_collection_days() is
+--          count_business_days() minus the weekday company-wide holidays
+--          (no agent_id or branch_id) in the Django-synced holiday table.
+--          count_business_days() itself is left unchanged because DPD and
+--          outstanding calculations use it.
+--
+--          POST /api/v1/holidays adds company-wide holidays to the same
+--          table with negative ids from holiday_local_id_seq, so they never
+--          collide with Django ids; scripts/sync_holidays.go only replaces
+--          the rows with positive (Django) ids.
+-- ============================================================================
+
+CREATE SEQUENCE IF NOT EXISTS holiday_local_id_seq;
+
+-- One locally added holiday per date (upserted by POST /api/v1/holidays)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holiday_local_date
+    ON holiday(date)
+    WHERE id < 0;
+
+-- Company-wide holidays, looked up by date
+CREATE INDEX IF NOT EXISTS idx_holiday_company_date
+    ON holiday(date)
+    WHERE agent_id IS NULL AND branch_id IS NULL;
+
+-- Business days (Mon-Fri) between two dates, inclusive, excluding company-wide holidays
+CREATE OR REPLACE FUNCTION count_collection_days(start_date DATE, end_date DATE)
+RETURNS INTEGER AS $$
+    SELECT count_business_days(start_date, end_date) - (
+        SELECT COUNT(DISTINCT h.date)::INTEGER
+        FROM holiday h
+        WHERE h.date BETWEEN start_date AND end_date
+          AND h.agent_id IS NULL
+          AND h.branch_id IS NULL
+          AND EXTRACT(ISODOW FROM h.date) BETWEEN 1 AND 5
+    );
+$$ LANGUAGE SQL STABLE;
+
+COMMENT ON FUNCTION count_collection_days(DATE, DATE) IS
+'Counts business days (Monday-Friday) between two dates, inclusive, excluding company-wide holidays in the holiday table.';
@@ -0,0 +1,34 @@
+-- ============================================================================
+-- Migration: 046_add_officer_snapshots.sql
+-- Description: Daily per-officer portfolio snapshots
+--
+-- Purpose: Officer metrics are computed from the live loans table, so a
+--          manager reviewing past performance could only ever see today.
+--          officer_snapshots keeps one row per officer per day with the
+--          portfolio, overdue and collection figures as of that day. Rows
+--          are captured after each loan field recalculation
+--          (POST /api/v1/loans/recalculate-fields), the last capture of a
+--          day winning, and served by
+--          GET /api/v1/officers/:officer_id/snapshot?date=.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS officer_snapshots (
+    snapshot_date DATE NOT NULL,
+    officer_id VARCHAR(50) NOT NULL REFERENCES officers(officer_id) ON DELETE CASCADE,
+    active_loans INTEGER NOT NULL DEFAULT 0,          -- loans in an open django_status
+    portfolio_total DECIMAL(15, 2) NOT NULL DEFAULT 0, -- SUM(repayment_amount) of active loans
+    total_outstanding DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    principal_outstanding DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    overdue_15d DECIMAL(15, 2) NOT NULL DEFAULT 0,     -- principal outstanding at DPD >= 15
+    par15_ratio DECIMAL(10, 6) NOT NULL DEFAULT 0,     -- overdue_15d / principal_outstanding
+    loans_in_arrears INTEGER NOT NULL DEFAULT 0,       -- active loans at DPD >= 1
+    due_today DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    collected_today DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    collected_mtd DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    captured_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (officer_id, snapshot_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_officer_snapshots_date ON officer_snapshots(snapshot_date);
+
+COMMENT ON TABLE officer_snapshots IS 'Daily per-officer portfolio, overdue and collection figures for as-of-date reporting';
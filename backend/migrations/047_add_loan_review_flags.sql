@@ -0,0 +1,23 @@
+-- ============================================================================
+-- Migration: 047_add_loan_review_flags.sql
+-- Description: Manual review worklist of flagged loans
+--
+-- Purpose: Collections supervisors flag loans for manual follow-up with a
+--          note and an assignee (POST /api/v1/loans/review-flags). Flags live
+--          outside the synced loans table so they survive syncs, and are
+--          listed by GET /api/v1/loans/review-flags and the
+--          review_flagged=true filter on GET /api/v1/loans. Flagging a loan
+--          again replaces its note and assignee.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS loan_review_flags (
+    loan_id VARCHAR(50) PRIMARY KEY REFERENCES loans(loan_id) ON DELETE CASCADE,
+    note TEXT NOT NULL DEFAULT '',
+    assignee VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- first flagged
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP   -- last re-flagged
+);
+
+CREATE INDEX IF NOT EXISTS idx_loan_review_flags_assignee ON loan_review_flags(assignee);
+
+COMMENT ON TABLE loan_review_flags IS 'Loans flagged by collections supervisors for manual follow-up';
@@ -0,0 +1,30 @@
+-- ============================================================================
+-- Migration: 048_add_sync_error_retry_state.sql
+-- Description: Turn sync_errors into a dead-letter queue that can be retried
+--
+-- Purpose: SyncService now retries transient failures (connection drops,
+--          deadlocks, serialization failures) up to ETL_SYNC_RETRY_ATTEMPTS
+--          times before giving up on a record. Records that still fail land
+--          in sync_errors with the number of attempts made, and
+--          POST /api/v1/sync/retry-errors reprocesses them. A row is pending
+--          until a retry run picks it up (retried_at); resolved records
+--          whether that retry succeeded. A failed retry is recorded as a new
+--          pending row in the retry run, carrying the cumulative attempts.
+--          Once those reach ETL_SYNC_ERROR_MAX_ATTEMPTS the row is marked
+--          dead and no longer retried.
+-- ============================================================================
+
+ALTER TABLE sync_errors
+    ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 1,  -- tries made before giving up
+    ADD COLUMN IF NOT EXISTS retried_at TIMESTAMP,                 -- picked up by a retry-errors run
+    ADD COLUMN IF NOT EXISTS resolved BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS dead BOOLEAN NOT NULL DEFAULT FALSE;  -- gave up after ETL_SYNC_ERROR_MAX_ATTEMPTS
+
+-- Pending dead letters, looked up per record by retry-errors
+CREATE INDEX IF NOT EXISTS idx_sync_errors_pending
+    ON sync_errors(entity_type, record_id)
+    WHERE retried_at IS NULL AND NOT dead;
+
+COMMENT ON COLUMN sync_errors.attempts IS 'Sync attempts made for the record, including earlier retry runs';
+COMMENT ON COLUMN sync_errors.retried_at IS 'When POST /sync/retry-errors reprocessed the record; NULL while pending';
+COMMENT ON COLUMN sync_errors.dead IS 'Set once attempts reach ETL_SYNC_ERROR_MAX_ATTEMPTS; dead records are not retried';
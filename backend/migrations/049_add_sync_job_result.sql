@@ -0,0 +1,15 @@
+-- ============================================================================
+-- Migration: 049_add_sync_job_result.sql
+-- Description: Store per-step results of non-ETL background jobs
+--
+-- Purpose: POST /api/v1/loans/recalculate-fields now runs as a sync_jobs row
+--          (job_type 'loan_recalculation') instead of a fire-and-forget
+--          goroutine. Its per-step row counts do not fit the ETL batch
+--          counters, so they are stored as JSON in result and returned by
+--          GET /api/v1/sync/jobs/:job_id.
+-- ============================================================================
+
+ALTER TABLE sync_jobs
+    ADD COLUMN IF NOT EXISTS result JSONB;  -- job-type specific outcome, e.g. rows affected per step
+
+COMMENT ON COLUMN sync_jobs.result IS 'Job-type specific result; for loan_recalculation the rows affected by each step';
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- Migration: 050_add_repayments_numeric_id_index.sql
+-- Description: Index the numeric repayment_id used as the sync watermark
+--
+-- Purpose: The incremental repayment sync and GET /api/v1/data-freshness
+--          read MAX(CAST(repayment_id AS BIGINT)) over the numeric
+--          repayment_ids. repayment_id is VARCHAR, so without an index on
+--          the cast expression that is a full scan of repayments. The
+--          expression and predicate below must match
+--          maxNumericRepaymentIDSQL in repayment_repository.go exactly for
+--          the planner to answer the MAX from the index.
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_repayments_numeric_id
+    ON repayments ((CAST(repayment_id AS BIGINT)))
+    WHERE repayment_id ~ '^[0-9]+$';
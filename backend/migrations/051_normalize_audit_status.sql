@@ -0,0 +1,29 @@
+-- ============================================================================
+-- Migration: 051_normalize_audit_status.sql
+-- Description: Map legacy audit_status values onto the dashboard's values
+--
+-- Purpose: PUT /api/v1/officers/:officer_id/audit only accepts the statuses
+--          the Agent Performance audit dropdown sends: 'Unassigned',
+--          'Assigned', 'In Progress' and 'Resolved'. Rows written earlier
+--          may carry lowercase or snake_case variants (unassigned, pending,
+--          in_progress, completed, ...), which the dropdown cannot show.
+--          Values that match none of the known variants are left as they are.
+-- ============================================================================
+
+UPDATE audit_tracking
+SET audit_status = CASE
+        WHEN LOWER(TRIM(audit_status)) = 'unassigned'
+            THEN 'Unassigned'
+        WHEN LOWER(TRIM(audit_status)) IN ('pending', 'assigned', 'open', 'new')
+            THEN 'Assigned'
+        WHEN LOWER(TRIM(audit_status)) IN ('in_progress', 'in progress', 'in-progress', 'inprogress')
+            THEN 'In Progress'
+        WHEN LOWER(TRIM(audit_status)) IN ('completed', 'complete', 'resolved', 'done', 'closed')
+            THEN 'Resolved'
+    END
+WHERE LOWER(TRIM(audit_status)) IN (
+        'unassigned', 'pending', 'assigned', 'open', 'new',
+        'in_progress', 'in progress', 'in-progress', 'inprogress',
+        'completed', 'complete', 'resolved', 'done', 'closed'
+    )
+  AND audit_status NOT IN ('Unassigned', 'Assigned', 'In Progress', 'Resolved');
@@ -79,11 +79,12 @@ func syncHolidays(ctx context.Context, seedsDB, djangoDB *sql.DB) error {
 		return nil
 	}
 
-	// Clear existing holidays in SeedsMetrics
+	// Clear previously synced holidays in SeedsMetrics. Holidays added through
+	// POST /api/v1/holidays have negative ids and are kept.
 	log.Println("🗑️  Clearing existing holidays in SeedsMetrics...")
-	_, err = seedsDB.ExecContext(ctx, "TRUNCATE TABLE holiday")
+	_, err = seedsDB.ExecContext(ctx, "DELETE FROM holiday WHERE id > 0")
 	if err != nil {
-		return fmt.Errorf("failed to truncate holiday table: %w", err)
+		return fmt.Errorf("failed to clear synced holidays: %w", err)
 	}
 
 	// Insert holidays into SeedsMetrics
@@ -111,7 +112,7 @@ func syncHolidays(ctx context.Context, seedsDB, djangoDB *sql.DB) error {
 
 	// Verify count
 	var count int
-	err = seedsDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM holiday").Scan(&count)
+	err = seedsDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM holiday WHERE id > 0").Scan(&count)
 	if err != nil {
 		return fmt.Errorf("failed to verify holiday count: %w", err)
 	}
@@ -592,6 +592,7 @@ const AgentPerformance = ({ agents, onViewPortfolio, onViewLowDelayLoans, initia
                     onChange={(e) => handleAuditStatusChange(agent.officerName, e.target.value)}
                     className="audit-status-select"
                   >
+                    <option value="Unassigned">Unassigned</option>
                     <option value="In Progress">In Progress</option>
                     <option value="Assigned">Assigned</option>
                     <option value="Resolved">Resolved</option>
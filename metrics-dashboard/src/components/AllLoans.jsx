@@ -316,10 +316,10 @@ const AllLoans = ({ initialLoans = [], initialFilter = null }) => {
         // The backend total represents the actual number of records matching server-side filters
         // Client-side filtering (loan_type, rot_type, delay_type) is for display only
         setPagination({
-          page: data.data.page,
-          limit: data.data.limit,
-          total: data.data.total, // Use backend total, not fetchedLoans.length
-          pages: data.data.pages, // Use backend calculated pages
+          page: data.data.pagination.page,
+          limit: data.data.pagination.limit,
+          total: data.data.pagination.total, // Use backend total, not fetchedLoans.length
+          pages: data.data.pagination.totalPages, // Use backend calculated pages
         });
 
         // Use summary metrics from backend (calculated from ALL filtered loans, not just current page)